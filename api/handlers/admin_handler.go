@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/EngenMe/go-clean-architecture/application/behaviors"
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler handles operational requests reserved to admins
+type AdminHandler struct {
+	requestMetrics *behaviors.RequestMetrics
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(requestMetrics *behaviors.RequestMetrics) *AdminHandler {
+	return &AdminHandler{
+		requestMetrics: requestMetrics,
+	}
+}
+
+// GetRequestMetrics gets the latency and error counts of commands and queries
+// @Summary Get request metrics
+// @Description Retrieves per-request-type counts, errors and latencies of the commands and queries handled since startup (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} behaviors.RequestStats
+// @Failure 401 {object} utils.Problem
+// @Failure 403 {object} utils.Problem
+// @Router /api/v1/admin/metrics/requests [get]
+func (h *AdminHandler) GetRequestMetrics(c *gin.Context) {
+	c.JSON(http.StatusOK, h.requestMetrics.Snapshot())
+}
+
+// RegisterRoutes registers admin routes
+func (h *AdminHandler) RegisterRoutes(
+	router *gin.RouterGroup,
+	authMiddleware gin.HandlerFunc,
+	adminMiddleware gin.HandlerFunc,
+) {
+	admin := router.Group("/admin")
+	admin.Use(authMiddleware, adminMiddleware)
+	{
+		admin.GET("/metrics/requests", h.GetRequestMetrics)
+	}
+}
@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 
+	"github.com/EngenMe/go-clean-architecture/api/middlewares"
 	"github.com/EngenMe/go-clean-architecture/application/services"
-	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
 	"github.com/gin-gonic/gin"
 )
 
@@ -28,23 +30,20 @@ func NewAuthHandler(authService *services.AuthService) *AuthHandler {
 // @Produce json
 // @Param request body services.LoginRequest true "Login credentials"
 // @Success 200 {object} services.AuthResponse
-// @Failure 400 {object} utils.APIError
-// @Failure 401 {object} utils.APIError
+// @Failure 400 {object} utils.Problem
+// @Failure 401 {object} utils.Problem
+// @Failure 429 {object} utils.Problem
 // @Router /api/v1/auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var request services.LoginRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(
-			http.StatusBadRequest,
-			utils.NewAPIError(http.StatusBadRequest, err.Error()),
-		)
+		_ = c.Error(err)
 		return
 	}
 
 	response, err := h.authService.Login(c.Request.Context(), request)
 	if err != nil {
-		status := utils.ErrorToStatusCode(err)
-		c.JSON(status, utils.NewAPIError(status, err.Error()))
+		_ = c.Error(err)
 		return
 	}
 
@@ -58,36 +57,86 @@ func (h *AuthHandler) Login(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param request body services.SignUpRequest true "Sign-up details"
+// @Param Idempotency-Key header string false "Client-generated key making retries safe"
 // @Success 201 {object} services.AuthResponse
-// @Failure 400 {object} utils.APIError
-// @Failure 409 {object} utils.APIError
+// @Failure 400 {object} utils.Problem
+// @Failure 409 {object} utils.Problem
+// @Failure 422 {object} utils.Problem
+// @Failure 429 {object} utils.Problem
 // @Router /api/v1/auth/signup [post]
 func (h *AuthHandler) SignUp(c *gin.Context) {
 	var request services.SignUpRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(
-			http.StatusBadRequest,
-			utils.NewAPIError(http.StatusBadRequest, err.Error()),
-		)
+		_ = c.Error(err)
 		return
 	}
 
+	// New users receive their emails in the language they signed up in
+	if request.PreferredLocale == "" {
+		request.PreferredLocale = middlewares.Locale(c)
+	}
+
 	response, err := h.authService.SignUp(c.Request.Context(), request)
 	if err != nil {
-		status := utils.ErrorToStatusCode(err)
-		c.JSON(status, utils.NewAPIError(status, err.Error()))
+		_ = c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, response)
 }
 
-// RegisterRoutes registers authentication routes
-func (h *AuthHandler) RegisterRoutes(router *gin.RouterGroup) {
+// TokenlessResponses returns the codec storing sign-up responses without
+// their token, which replays issue again for the user that signed up
+func (h *AuthHandler) TokenlessResponses() middlewares.IdempotentResponseCodec {
+	return tokenlessResponses{authService: h.authService}
+}
+
+// tokenlessResponses implements IdempotentResponseCodec interface for
+// responses holding a services.AuthResponse
+type tokenlessResponses struct {
+	authService *services.AuthService
+}
+
+// Store removes the token of successful responses
+func (r tokenlessResponses) Store(_ *gin.Context, statusCode int, body []byte) ([]byte, error) {
+	if statusCode != http.StatusCreated {
+		return body, nil
+	}
+	var response services.AuthResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode the auth response: %w", err)
+	}
+	response.Token = ""
+	return json.Marshal(response)
+}
+
+// Replay issues a new token for the user of a stored successful response
+func (r tokenlessResponses) Replay(c *gin.Context, statusCode int, stored []byte) ([]byte, error) {
+	if statusCode != http.StatusCreated {
+		return stored, nil
+	}
+	var response services.AuthResponse
+	if err := json.Unmarshal(stored, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode the stored auth response: %w", err)
+	}
+	token, err := r.authService.IssueToken(c.Request.Context(), response.User.ID)
+	if err != nil {
+		return nil, err
+	}
+	response.Token = token
+	return json.Marshal(response)
+}
+
+// RegisterRoutes registers authentication routes; idempotencyMiddleware
+// must store responses with the codec of TokenlessResponses
+func (h *AuthHandler) RegisterRoutes(
+	router *gin.RouterGroup,
+	idempotencyMiddleware gin.HandlerFunc,
+) {
 	authGroup := router.Group("/auth")
 	{
 		authGroup.POST("/login", h.Login)
-		authGroup.POST("/signup", h.SignUp)
+		authGroup.POST("/signup", idempotencyMiddleware, h.SignUp)
 	}
 
 	// Add standalone signup route at the top level for better discoverability
@@ -97,9 +146,12 @@ func (h *AuthHandler) RegisterRoutes(router *gin.RouterGroup) {
 	// @Accept json
 	// @Produce json
 	// @Param request body services.SignUpRequest true "Sign-up details"
+	// @Param Idempotency-Key header string false "Client-generated key making retries safe"
 	// @Success 201 {object} services.AuthResponse
-	// @Failure 400 {object} utils.APIError
-	// @Failure 409 {object} utils.APIError
+	// @Failure 400 {object} utils.Problem
+	// @Failure 409 {object} utils.Problem
+	// @Failure 422 {object} utils.Problem
+	// @Failure 429 {object} utils.Problem
 	// @Router /api/v1/signup [post]
-	router.POST("/signup", h.SignUp)
+	router.POST("/signup", idempotencyMiddleware, h.SignUp)
 }
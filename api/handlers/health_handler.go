@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/EngenMe/go-clean-architecture/infrastructure/health"
+	"github.com/gin-gonic/gin"
+)
+
+// HealthHandler handles the liveness and readiness probes
+type HealthHandler struct {
+	registry *health.Registry
+}
+
+// NewHealthHandler creates a new health handler
+func NewHealthHandler(registry *health.Registry) *HealthHandler {
+	return &HealthHandler{
+		registry: registry,
+	}
+}
+
+// Livez reports that the process is running
+// @Summary Liveness probe
+// @Description Checks that the API process is running, without checking its dependencies. /health is kept as an alias.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Router /livez [get]
+// @Router /health [get]
+func (h *HealthHandler) Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": health.StatusUp})
+}
+
+// Readyz reports whether the API can serve requests
+// @Summary Readiness probe
+// @Description Runs the readiness checks (database, migrations, mediatr handlers, outbox lag) and reports the status of each. Failures are detailed in the logs only. Answers 503 when a check fails or while the server shuts down.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} health.Report
+// @Failure 503 {object} health.Report
+// @Router /readyz [get]
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	report := h.registry.Check(c.Request.Context())
+
+	status := http.StatusOK
+	if report.Status != health.StatusUp {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
+
+// RegisterRoutes registers the health routes at the root of the router
+func (h *HealthHandler) RegisterRoutes(router gin.IRoutes) {
+	router.GET("/livez", h.Livez)
+	router.GET("/health", h.Livez)
+	router.GET("/readyz", h.Readyz)
+}
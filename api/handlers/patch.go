@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strings"
+
+	"github.com/EngenMe/go-clean-architecture/application/commands"
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
+)
+
+// Patch document media types
+const (
+	MediaTypeMergePatch = "application/merge-patch+json"
+	MediaTypeJSONPatch  = "application/json-patch+json"
+)
+
+// patchableUserFields lists the user members that a patch may write
+var patchableUserFields = map[string]bool{
+	"email":           true,
+	"password":        true,
+	"firstName":       true,
+	"lastName":        true,
+	"preferredLocale": true,
+}
+
+// readableUserFields lists the user members that a JSON Patch may read
+// through "test" and "copy" operations
+var readableUserFields = map[string]bool{
+	"email":           true,
+	"firstName":       true,
+	"lastName":        true,
+	"preferredLocale": true,
+}
+
+// jsonPatchOperation is a single RFC 6902 operation
+type jsonPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// patchMediaType returns the media type of a Content-Type header value
+func patchMediaType(contentType string) (string, error) {
+	if contentType == "" {
+		return "", fmt.Errorf("%w: Content-Type is required", utils.ErrUnsupportedMedia)
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", utils.ErrUnsupportedMedia, contentType)
+	}
+
+	switch mediaType {
+	case MediaTypeMergePatch, MediaTypeJSONPatch:
+		return mediaType, nil
+	default:
+		return "", fmt.Errorf(
+			"%w: %s (expected %s or %s)",
+			utils.ErrUnsupportedMedia,
+			mediaType,
+			MediaTypeMergePatch,
+			MediaTypeJSONPatch,
+		)
+	}
+}
+
+// decodeMergePatch converts an RFC 7396 merge patch document into a patch command
+func decodeMergePatch(body []byte) (commands.PatchUserCommand, error) {
+	var command commands.PatchUserCommand
+
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(body, &document); err != nil {
+		return command, fmt.Errorf(
+			"%w: merge patch must be a JSON object",
+			utils.ErrInvalidInput,
+		)
+	}
+
+	for field, raw := range document {
+		if !patchableUserFields[field] {
+			return command, fmt.Errorf(
+				"%w: field %q cannot be patched",
+				utils.ErrInvalidInput,
+				field,
+			)
+		}
+		if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+			return command, fmt.Errorf(
+				"%w: field %q is required and cannot be removed",
+				utils.ErrInvalidInput,
+				field,
+			)
+		}
+
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return command, fmt.Errorf(
+				"%w: field %q must be a string",
+				utils.ErrInvalidInput,
+				field,
+			)
+		}
+		setPatchField(&command, field, value)
+	}
+
+	return command, nil
+}
+
+// decodeJSONPatch applies an RFC 6902 JSON Patch document to the current user
+// representation and converts the resulting changes into a patch command
+func decodeJSONPatch(
+	body []byte,
+	current *entities.UserDTO,
+) (commands.PatchUserCommand, error) {
+	var command commands.PatchUserCommand
+
+	var operations []jsonPatchOperation
+	if err := json.Unmarshal(body, &operations); err != nil {
+		return command, fmt.Errorf(
+			"%w: JSON patch must be an array of operations",
+			utils.ErrInvalidInput,
+		)
+	}
+
+	document := map[string]string{
+		"email":           current.Email,
+		"firstName":       current.FirstName,
+		"lastName":        current.LastName,
+		"preferredLocale": current.PreferredLocale,
+	}
+	changed := map[string]string{}
+
+	for i, operation := range operations {
+		field, err := patchPointerField(operation.Path)
+		if err != nil {
+			return command, fmt.Errorf("operation %d: %w", i, err)
+		}
+
+		switch operation.Op {
+		case "add", "replace":
+			if !patchableUserFields[field] {
+				return command, fmt.Errorf(
+					"%w: operation %d: path %q cannot be patched",
+					utils.ErrInvalidInput,
+					i,
+					operation.Path,
+				)
+			}
+			var value string
+			if err := json.Unmarshal(operation.Value, &value); err != nil {
+				return command, fmt.Errorf(
+					"%w: operation %d: value must be a string",
+					utils.ErrInvalidInput,
+					i,
+				)
+			}
+			document[field] = value
+			changed[field] = value
+
+		case "test":
+			if !readableUserFields[field] {
+				return command, fmt.Errorf(
+					"%w: operation %d: path %q cannot be tested",
+					utils.ErrInvalidInput,
+					i,
+					operation.Path,
+				)
+			}
+			var value string
+			if err := json.Unmarshal(operation.Value, &value); err != nil || document[field] != value {
+				return command, fmt.Errorf(
+					"%w: operation %d: %s",
+					utils.ErrPatchTestFailed,
+					i,
+					operation.Path,
+				)
+			}
+
+		case "copy":
+			from, err := patchPointerField(operation.From)
+			if err != nil {
+				return command, fmt.Errorf("operation %d: %w", i, err)
+			}
+			if !readableUserFields[from] || !patchableUserFields[field] {
+				return command, fmt.Errorf(
+					"%w: operation %d: cannot copy %q to %q",
+					utils.ErrInvalidInput,
+					i,
+					operation.From,
+					operation.Path,
+				)
+			}
+			document[field] = document[from]
+			changed[field] = document[from]
+
+		case "remove", "move":
+			return command, fmt.Errorf(
+				"%w: operation %d: %q would remove required field %q",
+				utils.ErrInvalidInput,
+				i,
+				operation.Op,
+				operation.Path,
+			)
+
+		default:
+			return command, fmt.Errorf(
+				"%w: operation %d: unknown op %q",
+				utils.ErrInvalidInput,
+				i,
+				operation.Op,
+			)
+		}
+	}
+
+	for field, value := range changed {
+		setPatchField(&command, field, value)
+	}
+
+	return command, nil
+}
+
+// patchPointerField resolves a single-level JSON pointer to a user field name
+func patchPointerField(pointer string) (string, error) {
+	if !strings.HasPrefix(pointer, "/") || strings.Count(pointer, "/") != 1 {
+		return "", fmt.Errorf(
+			"%w: unsupported path %q",
+			utils.ErrInvalidInput,
+			pointer,
+		)
+	}
+
+	field := strings.NewReplacer("~1", "/", "~0", "~").Replace(pointer[1:])
+	if !patchableUserFields[field] && !readableUserFields[field] {
+		return "", fmt.Errorf(
+			"%w: unknown path %q",
+			utils.ErrInvalidInput,
+			pointer,
+		)
+	}
+
+	return field, nil
+}
+
+// setPatchField sets the command field matching a JSON member name
+func setPatchField(command *commands.PatchUserCommand, field, value string) {
+	switch field {
+	case "email":
+		command.Email = &value
+	case "password":
+		command.Password = &value
+	case "firstName":
+		command.FirstName = &value
+	case "lastName":
+		command.LastName = &value
+	case "preferredLocale":
+		command.PreferredLocale = &value
+	}
+}
@@ -4,10 +4,13 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/EngenMe/go-clean-architecture/api/middlewares"
 	"github.com/EngenMe/go-clean-architecture/application/commands"
 	"github.com/EngenMe/go-clean-architecture/application/services"
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
 	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 // UserHandler handles user-related requests
@@ -15,6 +18,14 @@ type UserHandler struct {
 	userService *services.UserService
 }
 
+// UserRouteAccess holds the authorization middlewares of the user routes
+// whose access is configurable
+type UserRouteAccess struct {
+	Create        []gin.HandlerFunc
+	LookupByEmail []gin.HandlerFunc
+	PublicLookup  bool // Lookups by email only confirm that the address is registered
+}
+
 // NewUserHandler creates a new user handler
 func NewUserHandler(userService *services.UserService) *UserHandler {
 	return &UserHandler{
@@ -24,29 +35,30 @@ func NewUserHandler(userService *services.UserService) *UserHandler {
 
 // CreateUser handles user creation
 // @Summary Create a new user
-// @Description Creates a new user (public endpoint)
+// @Description Creates a new user (admin only unless USER_CREATE_ACCESS is authenticated; self-registration goes through /auth/signup)
 // @Tags Users
 // @Accept json
 // @Produce json
 // @Param command body commands.CreateUserCommand true "User creation details"
+// @Param Idempotency-Key header string false "Client-generated key making retries safe"
+// @Security BearerAuth
 // @Success 201 {object} entities.UserDTO
-// @Failure 400 {object} utils.APIError
-// @Failure 409 {object} utils.APIError
+// @Failure 400 {object} utils.Problem
+// @Failure 401 {object} utils.Problem
+// @Failure 403 {object} utils.Problem
+// @Failure 409 {object} utils.Problem
+// @Failure 422 {object} utils.Problem
 // @Router /api/v1/users [post]
 func (h *UserHandler) CreateUser(c *gin.Context) {
 	var command commands.CreateUserCommand
 	if err := c.ShouldBindJSON(&command); err != nil {
-		c.JSON(
-			http.StatusBadRequest,
-			utils.NewAPIError(http.StatusBadRequest, err.Error()),
-		)
+		_ = c.Error(err)
 		return
 	}
 
 	user, err := h.userService.CreateUser(c.Request.Context(), command)
 	if err != nil {
-		statusCode := utils.ErrorToStatusCode(err)
-		c.JSON(statusCode, utils.NewAPIError(statusCode, err.Error()))
+		_ = c.Error(err)
 		return
 	}
 
@@ -55,31 +67,28 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 
 // GetUserByID gets a user by ID
 // @Summary Get user by ID
-// @Description Retrieves a user by their ID (protected endpoint)
+// @Description Retrieves a user by their ID (the user themselves or an admin)
 // @Tags Users
 // @Produce json
 // @Param id path uint true "User ID"
 // @Security BearerAuth
 // @Success 200 {object} entities.UserDTO
-// @Failure 400 {object} utils.APIError
-// @Failure 401 {object} utils.APIError
-// @Failure 404 {object} utils.APIError
+// @Failure 400 {object} utils.Problem
+// @Failure 401 {object} utils.Problem
+// @Failure 403 {object} utils.Problem
+// @Failure 404 {object} utils.Problem
 // @Router /api/v1/users/{id} [get]
 func (h *UserHandler) GetUserByID(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(
-			http.StatusBadRequest,
-			utils.NewAPIError(http.StatusBadRequest, "Invalid user ID"),
-		)
+		_ = c.Error(utils.NewLocalizedError(utils.ErrBadRequest, "error.invalid_parameter", "name", "id"))
 		return
 	}
 
 	user, err := h.userService.GetUserByID(c.Request.Context(), uint(id))
 	if err != nil {
-		statusCode := utils.ErrorToStatusCode(err)
-		c.JSON(statusCode, utils.NewAPIError(statusCode, err.Error()))
+		_ = c.Error(err)
 		return
 	}
 
@@ -88,49 +97,67 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 
 // GetUserByEmail gets a user by email
 // @Summary Get user by email
-// @Description Retrieves a user by their email address (public endpoint)
+// @Description Retrieves a user by their email address (admin only unless USER_LOOKUP_ACCESS says otherwise). When public, the lookup only answers the registered email, without the user's details.
 // @Tags Users
 // @Produce json
 // @Param email path string true "User email"
+// @Security BearerAuth
 // @Success 200 {object} entities.UserDTO
-// @Failure 400 {object} utils.APIError
-// @Failure 404 {object} utils.APIError
+// @Failure 400 {object} utils.Problem
+// @Failure 401 {object} utils.Problem
+// @Failure 403 {object} utils.Problem
+// @Failure 404 {object} utils.Problem
+// @Failure 429 {object} utils.Problem
 // @Router /api/v1/users/email/{email} [get]
 func (h *UserHandler) GetUserByEmail(c *gin.Context) {
 	email := c.Param("email")
 	if email == "" {
-		c.JSON(
-			http.StatusBadRequest,
-			utils.NewAPIError(http.StatusBadRequest, "Email is required"),
-		)
+		_ = c.Error(utils.NewFieldError("email", "required", "validation.required"))
 		return
 	}
 
 	user, err := h.userService.GetUserByEmail(c.Request.Context(), email)
 	if err != nil {
-		statusCode := utils.ErrorToStatusCode(err)
-		c.JSON(statusCode, utils.NewAPIError(statusCode, err.Error()))
+		_ = c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, user)
 }
 
+// confirmUserEmail answers a public lookup by email with the registered
+// address only, so that anonymous callers learn nothing else of the user
+func (h *UserHandler) confirmUserEmail(c *gin.Context) {
+	email := c.Param("email")
+	if email == "" {
+		_ = c.Error(utils.NewFieldError("email", "required", "validation.required"))
+		return
+	}
+
+	user, err := h.userService.GetUserByEmail(c.Request.Context(), email)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entities.UserLookupDTO{Email: user.Email})
+}
+
 // GetAllUsers gets all users
 // @Summary Get all users
-// @Description Retrieves a list of all users (protected endpoint)
+// @Description Retrieves a list of all users (admin only)
 // @Tags Users
 // @Produce json
 // @Security BearerAuth
 // @Success 200 {array} entities.UserDTO
-// @Failure 401 {object} utils.APIError
-// @Failure 500 {object} utils.APIError
+// @Failure 401 {object} utils.Problem
+// @Failure 403 {object} utils.Problem
+// @Failure 500 {object} utils.Problem
 // @Router /api/v1/users [get]
 func (h *UserHandler) GetAllUsers(c *gin.Context) {
 	users, err := h.userService.GetAllUsers(c.Request.Context())
 	if err != nil {
-		statusCode := utils.ErrorToStatusCode(err)
-		c.JSON(statusCode, utils.NewAPIError(statusCode, err.Error()))
+		_ = c.Error(err)
 		return
 	}
 
@@ -139,7 +166,7 @@ func (h *UserHandler) GetAllUsers(c *gin.Context) {
 
 // UpdateUser updates a user
 // @Summary Update user
-// @Description Updates a user's information (protected endpoint)
+// @Description Updates a user's information (the user themselves or an admin)
 // @Tags Users
 // @Accept json
 // @Produce json
@@ -147,17 +174,16 @@ func (h *UserHandler) GetAllUsers(c *gin.Context) {
 // @Param command body commands.UpdateUserCommand true "User update details"
 // @Security BearerAuth
 // @Success 200 {object} entities.UserDTO
-// @Failure 400 {object} utils.APIError
-// @Failure 401 {object} utils.APIError
-// @Failure 404 {object} utils.APIError
+// @Failure 400 {object} utils.Problem
+// @Failure 401 {object} utils.Problem
+// @Failure 403 {object} utils.Problem
+// @Failure 404 {object} utils.Problem
+// @Failure 409 {object} utils.Problem
 // @Router /api/v1/users/{id} [put]
 func (h *UserHandler) UpdateUser(c *gin.Context) {
 	var command commands.UpdateUserCommand
 	if err := c.ShouldBindJSON(&command); err != nil {
-		c.JSON(
-			http.StatusBadRequest,
-			utils.NewAPIError(http.StatusBadRequest, err.Error()),
-		)
+		_ = c.Error(err)
 		return
 	}
 
@@ -165,20 +191,84 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil || uint(id) != command.ID {
-		c.JSON(
-			http.StatusBadRequest,
-			utils.NewAPIError(
-				http.StatusBadRequest,
-				"ID in path must match ID in body",
-			),
-		)
+		_ = c.Error(utils.NewLocalizedError(utils.ErrBadRequest, "error.id_mismatch"))
 		return
 	}
 
 	user, err := h.userService.UpdateUser(c.Request.Context(), command)
 	if err != nil {
-		statusCode := utils.ErrorToStatusCode(err)
-		c.JSON(statusCode, utils.NewAPIError(statusCode, err.Error()))
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// PatchUser partially updates a user
+// @Summary Patch user
+// @Description Partially updates a user with a JSON Merge Patch (RFC 7396) or JSON Patch (RFC 6902) document (the user themselves or an admin)
+// @Tags Users
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Produce json
+// @Param id path uint true "User ID"
+// @Param patch body commands.PatchUserCommand true "Fields to change"
+// @Security BearerAuth
+// @Success 200 {object} entities.UserDTO
+// @Failure 400 {object} utils.Problem
+// @Failure 401 {object} utils.Problem
+// @Failure 403 {object} utils.Problem
+// @Failure 404 {object} utils.Problem
+// @Failure 409 {object} utils.Problem
+// @Failure 415 {object} utils.Problem
+// @Router /api/v1/users/{id} [patch]
+func (h *UserHandler) PatchUser(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		_ = c.Error(utils.NewLocalizedError(utils.ErrBadRequest, "error.invalid_parameter", "name", "id"))
+		return
+	}
+
+	mediaType, err := patchMediaType(c.ContentType())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	body, err := c.GetRawData()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var command commands.PatchUserCommand
+	switch mediaType {
+	case MediaTypeMergePatch:
+		command, err = decodeMergePatch(body)
+	case MediaTypeJSONPatch:
+		// JSON Patch operations are applied against the current representation
+		current, getErr := h.userService.GetUserByID(c.Request.Context(), uint(id))
+		if getErr != nil {
+			_ = c.Error(getErr)
+			return
+		}
+		command, err = decodeJSONPatch(body, current)
+	}
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	command.ID = uint(id)
+	if err := binding.Validator.ValidateStruct(&command); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	user, err := h.userService.PatchUser(c.Request.Context(), command)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
 
@@ -187,30 +277,27 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 
 // DeleteUser deletes a user
 // @Summary Delete user
-// @Description Deletes a user by ID (protected endpoint)
+// @Description Deletes a user by ID (the user themselves or an admin)
 // @Tags Users
 // @Param id path uint true "User ID"
 // @Security BearerAuth
 // @Success 204
-// @Failure 400 {object} utils.APIError
-// @Failure 401 {object} utils.APIError
-// @Failure 404 {object} utils.APIError
+// @Failure 400 {object} utils.Problem
+// @Failure 401 {object} utils.Problem
+// @Failure 403 {object} utils.Problem
+// @Failure 404 {object} utils.Problem
 // @Router /api/v1/users/{id} [delete]
 func (h *UserHandler) DeleteUser(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(
-			http.StatusBadRequest,
-			utils.NewAPIError(http.StatusBadRequest, "Invalid user ID"),
-		)
+		_ = c.Error(utils.NewLocalizedError(utils.ErrBadRequest, "error.invalid_parameter", "name", "id"))
 		return
 	}
 
 	err = h.userService.DeleteUser(c.Request.Context(), uint(id))
 	if err != nil {
-		statusCode := utils.ErrorToStatusCode(err)
-		c.JSON(statusCode, utils.NewAPIError(statusCode, err.Error()))
+		_ = c.Error(err)
 		return
 	}
 
@@ -221,21 +308,45 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 func (h *UserHandler) RegisterRoutes(
 	router *gin.RouterGroup,
 	authMiddleware gin.HandlerFunc,
+	idempotencyMiddleware gin.HandlerFunc,
+	access UserRouteAccess,
 ) {
+	lookupByEmail := h.GetUserByEmail
+	if access.PublicLookup {
+		lookupByEmail = h.confirmUserEmail
+	}
+
 	users := router.Group("/users")
 	{
-		// Public routes
-		users.POST("", h.CreateUser)
-		users.GET("/email/:email", h.GetUserByEmail)
+		// Routes with configurable access
+		users.POST(
+			"",
+			withMiddlewares(access.Create, idempotencyMiddleware, h.CreateUser)...,
+		)
+		users.GET(
+			"/email/:email",
+			withMiddlewares(access.LookupByEmail, lookupByEmail)...,
+		)
 
-		// Protected routes
+		// Protected routes; only admins list users, and a user may only
+		// access themselves unless admin
+		selfOrAdmin := middlewares.RequireSelfOrRole("id", entities.RoleAdmin)
 		authenticated := users.Group("")
 		authenticated.Use(authMiddleware)
 		{
-			authenticated.GET("", h.GetAllUsers)
-			authenticated.GET("/:id", h.GetUserByID)
-			authenticated.PUT("/:id", h.UpdateUser)
-			authenticated.DELETE("/:id", h.DeleteUser)
+			authenticated.GET("", middlewares.RequireRole(entities.RoleAdmin), h.GetAllUsers)
+			authenticated.GET("/:id", selfOrAdmin, h.GetUserByID)
+			authenticated.PUT("/:id", selfOrAdmin, h.UpdateUser)
+			authenticated.PATCH("/:id", selfOrAdmin, h.PatchUser)
+			authenticated.DELETE("/:id", selfOrAdmin, h.DeleteUser)
 		}
 	}
 }
+
+// withMiddlewares prepends middlewares to a route's handlers
+func withMiddlewares(
+	middlewares []gin.HandlerFunc,
+	handlers ...gin.HandlerFunc,
+) []gin.HandlerFunc {
+	return append(append([]gin.HandlerFunc(nil), middlewares...), handlers...)
+}
@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/EngenMe/go-clean-architecture/application/commands"
+	"github.com/EngenMe/go-clean-architecture/application/services"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookHandler handles webhook subscription requests
+type WebhookHandler struct {
+	webhookService *services.WebhookService
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(webhookService *services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// CreateWebhook handles webhook subscription creation
+// @Summary Create a webhook
+// @Description Subscribes an endpoint to user events. The signing secret is only returned in this response (admin only)
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param command body commands.CreateWebhookCommand true "Webhook subscription details"
+// @Security BearerAuth
+// @Success 201 {object} entities.WebhookSubscriptionDTO
+// @Failure 400 {object} utils.Problem
+// @Failure 401 {object} utils.Problem
+// @Failure 403 {object} utils.Problem
+// @Router /api/v1/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var command commands.CreateWebhookCommand
+	if err := c.ShouldBindJSON(&command); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(c.Request.Context(), command)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, webhook)
+}
+
+// GetAllWebhooks gets all webhook subscriptions
+// @Summary Get all webhooks
+// @Description Retrieves all webhook subscriptions (admin only)
+// @Tags Webhooks
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} entities.WebhookSubscriptionDTO
+// @Failure 401 {object} utils.Problem
+// @Failure 403 {object} utils.Problem
+// @Router /api/v1/webhooks [get]
+func (h *WebhookHandler) GetAllWebhooks(c *gin.Context) {
+	webhooks, err := h.webhookService.GetAllWebhooks(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, webhooks)
+}
+
+// GetWebhookByID gets a webhook subscription by ID
+// @Summary Get webhook by ID
+// @Description Retrieves a webhook subscription by its ID (admin only)
+// @Tags Webhooks
+// @Produce json
+// @Param id path uint true "Webhook ID"
+// @Security BearerAuth
+// @Success 200 {object} entities.WebhookSubscriptionDTO
+// @Failure 400 {object} utils.Problem
+// @Failure 401 {object} utils.Problem
+// @Failure 403 {object} utils.Problem
+// @Failure 404 {object} utils.Problem
+// @Router /api/v1/webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhookByID(c *gin.Context) {
+	id, ok := webhookIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	webhook, err := h.webhookService.GetWebhookByID(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+// UpdateWebhook replaces a webhook subscription
+// @Summary Update webhook
+// @Description Replaces a webhook subscription; setting a secret rotates the signing key (admin only)
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path uint true "Webhook ID"
+// @Param command body commands.UpdateWebhookCommand true "Webhook subscription details"
+// @Security BearerAuth
+// @Success 200 {object} entities.WebhookSubscriptionDTO
+// @Failure 400 {object} utils.Problem
+// @Failure 401 {object} utils.Problem
+// @Failure 403 {object} utils.Problem
+// @Failure 404 {object} utils.Problem
+// @Router /api/v1/webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	id, ok := webhookIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var command commands.UpdateWebhookCommand
+	if err := c.ShouldBindJSON(&command); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	command.ID = id
+
+	webhook, err := h.webhookService.UpdateWebhook(c.Request.Context(), command)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+// DeleteWebhook deletes a webhook subscription
+// @Summary Delete webhook
+// @Description Deletes a webhook subscription and its delivery log (admin only)
+// @Tags Webhooks
+// @Param id path uint true "Webhook ID"
+// @Security BearerAuth
+// @Success 204
+// @Failure 400 {object} utils.Problem
+// @Failure 401 {object} utils.Problem
+// @Failure 403 {object} utils.Problem
+// @Failure 404 {object} utils.Problem
+// @Router /api/v1/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	id, ok := webhookIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.webhookService.DeleteWebhook(c.Request.Context(), id); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// GetWebhookDeliveries gets the delivery log of a webhook subscription
+// @Summary Get webhook deliveries
+// @Description Retrieves the latest deliveries of a webhook subscription, newest first (admin only)
+// @Tags Webhooks
+// @Produce json
+// @Param id path uint true "Webhook ID"
+// @Param limit query int false "Maximum number of deliveries (default 50, max 500)"
+// @Security BearerAuth
+// @Success 200 {array} entities.WebhookDelivery
+// @Failure 400 {object} utils.Problem
+// @Failure 401 {object} utils.Problem
+// @Failure 403 {object} utils.Problem
+// @Failure 404 {object} utils.Problem
+// @Router /api/v1/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) GetWebhookDeliveries(c *gin.Context) {
+	id, ok := webhookIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil {
+		_ = c.Error(utils.NewLocalizedError(utils.ErrBadRequest, "error.invalid_parameter", "name", "limit"))
+		return
+	}
+
+	deliveries, err := h.webhookService.GetWebhookDeliveries(
+		c.Request.Context(),
+		id,
+		limit,
+	)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// ReplayWebhookDelivery sends a recorded delivery again
+// @Summary Replay webhook delivery
+// @Description Schedules a recorded delivery to be sent again, whatever its status (admin only)
+// @Tags Webhooks
+// @Produce json
+// @Param id path uint true "Webhook ID"
+// @Param deliveryId path uint true "Delivery ID"
+// @Security BearerAuth
+// @Success 202 {object} entities.WebhookDelivery
+// @Failure 400 {object} utils.Problem
+// @Failure 401 {object} utils.Problem
+// @Failure 403 {object} utils.Problem
+// @Failure 404 {object} utils.Problem
+// @Router /api/v1/webhooks/{id}/deliveries/{deliveryId}/replay [post]
+func (h *WebhookHandler) ReplayWebhookDelivery(c *gin.Context) {
+	id, ok := webhookIDParam(c, "id")
+	if !ok {
+		return
+	}
+	deliveryID, ok := webhookIDParam(c, "deliveryId")
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookService.ReplayWebhookDelivery(
+		c.Request.Context(),
+		id,
+		deliveryID,
+	)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+// RegisterRoutes registers webhook routes, all restricted to admins
+func (h *WebhookHandler) RegisterRoutes(
+	router *gin.RouterGroup,
+	authMiddleware gin.HandlerFunc,
+	adminMiddleware gin.HandlerFunc,
+) {
+	webhooks := router.Group("/webhooks")
+	webhooks.Use(authMiddleware, adminMiddleware)
+	{
+		webhooks.POST("", h.CreateWebhook)
+		webhooks.GET("", h.GetAllWebhooks)
+		webhooks.GET("/:id", h.GetWebhookByID)
+		webhooks.PUT("/:id", h.UpdateWebhook)
+		webhooks.DELETE("/:id", h.DeleteWebhook)
+		webhooks.GET("/:id/deliveries", h.GetWebhookDeliveries)
+		webhooks.POST("/:id/deliveries/:deliveryId/replay", h.ReplayWebhookDelivery)
+	}
+}
+
+// webhookIDParam parses a numeric path parameter, reporting a bad request
+// when it is invalid
+func webhookIDParam(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 32)
+	if err != nil {
+		_ = c.Error(utils.NewLocalizedError(utils.ErrBadRequest, "error.invalid_parameter", "name", name))
+		return 0, false
+	}
+	return uint(id), true
+}
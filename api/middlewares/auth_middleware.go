@@ -1,24 +1,30 @@
 package middlewares
 
 import (
-	"net/http"
 	"strings"
 
 	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
 	"github.com/gin-gonic/gin"
 )
 
+// TokenRejectionKey is the context key of the reason why AuthMiddleware
+// rejected a request: missing, malformed or invalid
+const TokenRejectionKey = "tokenRejection"
+
+// TokenValidator validates access tokens and returns their claims
+type TokenValidator interface {
+	ValidateToken(tokenString string) (*utils.JWTClaims, error)
+}
+
 // AuthMiddleware is a middleware for JWT authentication
-func AuthMiddleware() gin.HandlerFunc {
+func AuthMiddleware(tokens TokenValidator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.AbortWithStatusJSON(
-				http.StatusUnauthorized,
-				utils.NewAPIError(
-					http.StatusUnauthorized,
-					"Authorization header is required",
-				),
+			c.Set(TokenRejectionKey, "missing")
+			abortWithError(
+				c,
+				utils.NewLocalizedError(utils.ErrUnauthorized, "error.authorization_required"),
 			)
 			return
 		}
@@ -26,32 +32,29 @@ func AuthMiddleware() gin.HandlerFunc {
 		// Check if the header has the Bearer prefix
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.AbortWithStatusJSON(
-				http.StatusUnauthorized,
-				utils.NewAPIError(
-					http.StatusUnauthorized,
-					"Authorization header must be Bearer token",
-				),
+			c.Set(TokenRejectionKey, "malformed")
+			abortWithError(
+				c,
+				utils.NewLocalizedError(utils.ErrUnauthorized, "error.bearer_required"),
 			)
 			return
 		}
 
 		tokenString := parts[1]
-		claims, err := utils.ValidateToken(tokenString)
+		claims, err := tokens.ValidateToken(tokenString)
 		if err != nil {
-			c.AbortWithStatusJSON(
-				http.StatusUnauthorized,
-				utils.NewAPIError(
-					http.StatusUnauthorized,
-					"Invalid or expired JWT token",
-				),
+			c.Set(TokenRejectionKey, "invalid")
+			abortWithError(
+				c,
+				utils.NewLocalizedError(utils.ErrUnauthorized, "error.invalid_token"),
 			)
 			return
 		}
 
-		// Set user ID and email in the context
+		// Set user ID, email and role in the context
 		c.Set("userID", claims.UserID)
 		c.Set("userEmail", claims.Email)
+		c.Set("userRole", claims.Role)
 
 		c.Next()
 	}
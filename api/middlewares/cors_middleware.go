@@ -0,0 +1,103 @@
+package middlewares
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/EngenMe/go-clean-architecture/infrastructure/logging"
+	"github.com/gin-gonic/gin"
+)
+
+// CORS request and response headers
+const (
+	OriginHeader                     = "Origin"
+	AccessControlRequestMethodHeader = "Access-Control-Request-Method"
+)
+
+// corsAllowedHeaders are the request headers browsers may send
+var corsAllowedHeaders = strings.Join(
+	[]string{
+		"Authorization",
+		"Content-Type",
+		"Accept-Language",
+		IdempotencyKeyHeader,
+		APIKeyHeader,
+		logging.RequestIDHeader,
+		"traceparent",
+	},
+	", ",
+)
+
+// corsExposedHeaders are the response headers scripts may read
+var corsExposedHeaders = strings.Join(
+	[]string{
+		"Content-Language",
+		"Location",
+		IdempotentReplayedHeader,
+		logging.RequestIDHeader,
+		RateLimitLimitHeader,
+		RateLimitRemainingHeader,
+		RateLimitResetHeader,
+		RateLimitPolicyHeader,
+		RetryAfterHeader,
+	},
+	", ",
+)
+
+// CORSPolicy holds the origins allowed to call the API from a browser.
+// The origins can be replaced while requests are served, e.g. when the
+// configuration is reloaded.
+type CORSPolicy struct {
+	origins atomic.Pointer[[]string]
+}
+
+// NewCORSPolicy creates a policy allowing origins such as
+// "https://app.example.com", or any origin with "*"
+func NewCORSPolicy(origins []string) *CORSPolicy {
+	policy := &CORSPolicy{}
+	policy.SetAllowedOrigins(origins)
+	return policy
+}
+
+// SetAllowedOrigins replaces the allowed origins atomically
+func (p *CORSPolicy) SetAllowedOrigins(origins []string) {
+	origins = slices.Clone(origins)
+	p.origins.Store(&origins)
+}
+
+// Allows reports whether an origin may call the API
+func (p *CORSPolicy) Allows(origin string) bool {
+	origins := *p.origins.Load()
+	return slices.Contains(origins, "*") || slices.Contains(origins, origin)
+}
+
+// CORSMiddleware adds the CORS headers to the responses to allowed
+// origins and answers their preflight requests. Requests from other
+// origins are served without the headers, so that browsers block them.
+func CORSMiddleware(policy *CORSPolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		header.Add("Vary", OriginHeader)
+
+		origin := c.GetHeader(OriginHeader)
+		if origin == "" || !policy.Allows(origin) {
+			c.Next()
+			return
+		}
+
+		header.Set("Access-Control-Allow-Origin", origin)
+		header.Set("Access-Control-Expose-Headers", corsExposedHeaders)
+
+		if c.Request.Method == http.MethodOptions && c.GetHeader(AccessControlRequestMethodHeader) != "" {
+			header.Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE")
+			header.Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+			header.Set("Access-Control-Max-Age", "600")
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		c.Next()
+	}
+}
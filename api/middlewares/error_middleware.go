@@ -0,0 +1,115 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"reflect"
+
+	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceIDKey is the context key of the ID reported as traceId in problems
+const TraceIDKey = "traceID"
+
+// ErrorMiddleware writes the errors that handlers and middlewares report
+// with c.Error as RFC 7807 application/problem+json responses. It must be
+// registered before the middlewares whose errors it reports.
+func ErrorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(TraceIDKey, requestTraceID(c))
+
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		WriteProblem(c, c.Errors.Last().Err)
+	}
+}
+
+// WriteProblem writes err as a problem response. Internal errors are
+// logged, since their details are not sent to the client.
+func WriteProblem(c *gin.Context, err error) {
+	locale := Locale(c)
+	problem := utils.NewProblem(normalizeError(err), locale)
+	problem.Instance = c.Request.URL.Path
+	problem.TraceID = c.GetString(TraceIDKey)
+
+	if problem.Status >= 500 {
+		slog.ErrorContext(
+			c.Request.Context(),
+			"Internal error",
+			slog.String("trace_id", problem.TraceID),
+			slog.String("method", c.Request.Method),
+			slog.String("path", problem.Instance),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	body, marshalErr := json.Marshal(problem)
+	if marshalErr != nil {
+		body = []byte(`{"title":"Internal server error","status":500}`)
+	}
+	c.Header("Content-Language", locale)
+	c.Writer.Header().Add("Vary", "Accept-Language")
+	c.Data(problem.Status, utils.ProblemContentType, body)
+}
+
+// abortWithError reports err to ErrorMiddleware and stops the chain
+func abortWithError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
+
+// normalizeError turns request decoding errors into the errors they are
+// reported as
+func normalizeError(err error) error {
+	var validationErrors validator.ValidationErrors
+	var typeError *json.UnmarshalTypeError
+	var syntaxError *json.SyntaxError
+	switch {
+	case errors.As(err, &validationErrors):
+		return utils.NewValidationError(validationErrors)
+	case errors.As(err, &typeError):
+		return utils.NewFieldError(typeError.Field, "type", "validation.type."+jsonKind(typeError.Type))
+	case errors.As(err, &syntaxError), errors.Is(err, io.ErrUnexpectedEOF):
+		return utils.NewLocalizedError(utils.ErrBadRequest, "error.invalid_json")
+	case errors.Is(err, io.EOF):
+		return utils.NewLocalizedError(utils.ErrBadRequest, "error.body_required")
+	}
+	return err
+}
+
+// jsonKind names the JSON type expected for a Go type
+func jsonKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// requestTraceID returns the ID of the trace of the request, continued
+// from an incoming W3C traceparent header by TracingMiddleware, or a new
+// random ID
+func requestTraceID(c *gin.Context) string {
+	if spanContext := trace.SpanContextFromContext(c.Request.Context()); spanContext.HasTraceID() {
+		return spanContext.TraceID().String()
+	}
+	return newRandomID()
+}
@@ -0,0 +1,220 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+// Idempotency headers
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	maxIdempotencyRequestBody = 1 << 20
+)
+
+// IdempotentResponseCodec changes what is stored of the responses of a
+// route and restores it on replay, so that credentials they hold are not
+// stored
+type IdempotentResponseCodec interface {
+	// Store returns what to store of a response body
+	Store(c *gin.Context, statusCode int, body []byte) ([]byte, error)
+	// Replay returns the body to replay from a stored one
+	Replay(c *gin.Context, statusCode int, stored []byte) ([]byte, error)
+}
+
+// IdempotencyMiddleware makes requests carrying an Idempotency-Key header
+// safe to retry. The first response is stored per key and caller and
+// replayed for retries with the same payload; reusing a key with another
+// payload is rejected with 422, and retrying while the first request is in
+// progress with 409. Requests without the header are untouched. Keys
+// expire after ttl as told by clk. Responses are stored as is unless a
+// codec is given, which routes returning credentials must give.
+func IdempotencyMiddleware(
+	store repositories.IdempotencyStore,
+	ttl time.Duration,
+	clk clock.Clock,
+	codec IdempotentResponseCodec,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clientKey := c.GetHeader(IdempotencyKeyHeader)
+		if clientKey == "" {
+			c.Next()
+			return
+		}
+		if len(clientKey) > maxIdempotencyKeyLength {
+			abortWithError(
+				c,
+				utils.NewLocalizedError(
+					utils.ErrBadRequest,
+					"error.idempotency_key_too_long",
+					"max", strconv.Itoa(maxIdempotencyKeyLength),
+				),
+			)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxIdempotencyRequestBody+1))
+		if err != nil {
+			abortWithError(
+				c,
+				utils.NewLocalizedError(utils.ErrBadRequest, "error.unreadable_body"),
+			)
+			return
+		}
+		if len(body) > maxIdempotencyRequestBody {
+			abortWithError(
+				c,
+				utils.NewLocalizedError(
+					utils.ErrPayloadTooLarge,
+					"error.idempotent_body_too_large",
+					"max", strconv.Itoa(maxIdempotencyRequestBody),
+				),
+			)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		key := idempotencyScope(c, clientKey)
+		requestHash := hashIdempotentRequest(c, body)
+
+		record, reserved, err := store.Reserve(ctx, key, requestHash, clk.Now(), ttl)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		// A key reused for another payload is rejected whether or not its
+		// first request is still in progress
+		if !reserved {
+			switch {
+			case record != nil && record.RequestHash != requestHash:
+				abortWithError(c, utils.ErrIdempotencyKeyReused)
+			case record == nil || !record.IsCompleted():
+				abortWithError(c, utils.ErrRequestInProgress)
+			default:
+				body := record.ResponseBody
+				if codec != nil {
+					if body, err = codec.Replay(c, record.StatusCode, body); err != nil {
+						abortWithError(c, err)
+						return
+					}
+				}
+				c.Header(IdempotentReplayedHeader, "true")
+				c.Data(record.StatusCode, record.ContentType, body)
+				c.Abort()
+			}
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		// Store the outcome even if the client went away meanwhile
+		storeCtx := context.WithoutCancel(ctx)
+		completed := false
+		defer func() {
+			// A panicking handler must not leave the key in progress
+			if !completed {
+				if err := store.Release(storeCtx, key); err != nil {
+					slog.ErrorContext(
+						storeCtx,
+						"Failed to release idempotency key",
+						slog.String("error", err.Error()),
+					)
+				}
+			}
+		}()
+
+		c.Next()
+
+		// Write reported errors now, so that the stored response has them
+		if len(c.Errors) > 0 && !c.Writer.Written() {
+			WriteProblem(c, c.Errors.Last().Err)
+		}
+
+		// Server errors are not final: let the client retry with the same key
+		statusCode := c.Writer.Status()
+		if statusCode >= http.StatusInternalServerError {
+			return
+		}
+		stored := recorder.body.Bytes()
+		if codec != nil {
+			if stored, err = codec.Store(c, statusCode, stored); err != nil {
+				slog.ErrorContext(
+					storeCtx,
+					"Failed to encode idempotent response",
+					slog.String("error", err.Error()),
+				)
+				return
+			}
+		}
+		if err := store.Complete(
+			storeCtx,
+			key,
+			statusCode,
+			c.Writer.Header().Get("Content-Type"),
+			stored,
+		); err != nil {
+			slog.ErrorContext(
+				storeCtx,
+				"Failed to store idempotent response",
+				slog.String("error", err.Error()),
+			)
+			return
+		}
+		completed = true
+	}
+}
+
+// idempotencyScope scopes a client key to the caller and the route, so
+// that different callers or endpoints never share responses. Callers are
+// identified by their Authorization header, or are anonymous.
+func idempotencyScope(c *gin.Context, clientKey string) string {
+	caller := "anonymous"
+	if authorization := c.GetHeader("Authorization"); authorization != "" {
+		sum := sha256.Sum256([]byte(authorization))
+		caller = hex.EncodeToString(sum[:8])
+	}
+	scope := sha256.Sum256([]byte(c.Request.Method + " " + c.FullPath() + "\x00" + clientKey))
+	return caller + ":" + hex.EncodeToString(scope[:])
+}
+
+// hashIdempotentRequest hashes what identifies the payload of a request
+func hashIdempotentRequest(c *gin.Context, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Request.Method + " " + c.Request.URL.RequestURI() + "\x00"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder copies the response body while writing it
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write writes the data to the response and keeps a copy
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+// WriteString writes the string to the response and keeps a copy
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
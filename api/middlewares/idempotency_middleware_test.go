@@ -0,0 +1,144 @@
+package middlewares_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/api/middlewares"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/memory"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// idempotentRoute is a route behind the idempotency middleware whose
+// handler echoes the body, counting its calls and optionally blocking
+type idempotentRoute struct {
+	router  *gin.Engine
+	calls   atomic.Int32
+	started chan struct{} // Receives a value when the handler starts, if not nil
+	release chan struct{} // Blocks the handler until closed, if not nil
+}
+
+// newIdempotentRoute serves POST /items behind an in-memory store
+func newIdempotentRoute() *idempotentRoute {
+	gin.SetMode(gin.TestMode)
+	r := &idempotentRoute{router: gin.New()}
+	r.router.Use(middlewares.ErrorMiddleware())
+	r.router.POST(
+		"/items",
+		middlewares.IdempotencyMiddleware(memory.NewIdempotencyStore(), time.Hour, clock.System, nil),
+		func(c *gin.Context) {
+			calls := r.calls.Add(1)
+			if r.started != nil {
+				r.started <- struct{}{}
+			}
+			if r.release != nil {
+				<-r.release
+			}
+			body, _ := io.ReadAll(c.Request.Body)
+			c.JSON(http.StatusCreated, gin.H{"call": calls, "body": string(body)})
+		},
+	)
+	return r
+}
+
+// post sends body with an Idempotency-Key
+func (r *idempotentRoute) post(key, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(middlewares.IdempotencyKeyHeader, key)
+	recorder := httptest.NewRecorder()
+	r.router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+// expectProblem checks that a response is a problem with status and code
+func expectProblem(t *testing.T, response *httptest.ResponseRecorder, status int, code string) {
+	t.Helper()
+	var problem utils.Problem
+	if err := json.Unmarshal(response.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("expected a problem, got %s", response.Body)
+	}
+	if response.Code != status || problem.Code != code {
+		t.Errorf("expected %d %s, got %d %s", status, code, response.Code, problem.Code)
+	}
+}
+
+func TestIdempotencyReplay(t *testing.T) {
+	r := newIdempotentRoute()
+
+	first := r.post("key", `{"name":"a"}`)
+	if first.Code != http.StatusCreated || first.Header().Get(middlewares.IdempotentReplayedHeader) != "" {
+		t.Fatalf("expected the first request to be handled, got %d %s", first.Code, first.Body)
+	}
+
+	retry := r.post("key", `{"name":"a"}`)
+	if retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() {
+		t.Errorf("expected the first response, got %d %s", retry.Code, retry.Body)
+	}
+	if retry.Header().Get(middlewares.IdempotentReplayedHeader) != "true" {
+		t.Error("expected the retry to be marked as replayed")
+	}
+	if retry.Header().Get("Content-Type") != first.Header().Get("Content-Type") {
+		t.Errorf("expected the content type %q, got %q", first.Header().Get("Content-Type"), retry.Header().Get("Content-Type"))
+	}
+
+	// Other keys are handled on their own
+	if other := r.post("other", `{"name":"a"}`); other.Code != http.StatusCreated {
+		t.Errorf("expected another key to be handled, got %d", other.Code)
+	}
+	if calls := r.calls.Load(); calls != 2 {
+		t.Errorf("expected 2 handler calls, got %d", calls)
+	}
+}
+
+func TestIdempotencyKeyReused(t *testing.T) {
+	r := newIdempotentRoute()
+	r.post("key", `{"name":"a"}`)
+
+	expectProblem(t, r.post("key", `{"name":"b"}`), http.StatusUnprocessableEntity, "idempotency_key_reused")
+	if calls := r.calls.Load(); calls != 1 {
+		t.Errorf("expected the reused key not to reach the handler, got %d calls", calls)
+	}
+}
+
+func TestIdempotencyConcurrentRequests(t *testing.T) {
+	r := newIdempotentRoute()
+	r.started = make(chan struct{}, 1)
+	r.release = make(chan struct{})
+
+	var wg sync.WaitGroup
+	var first *httptest.ResponseRecorder
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		first = r.post("key", `{"name":"a"}`)
+	}()
+	<-r.started
+
+	// While the first request runs, a retry is told to wait and another
+	// payload is rejected as a reused key
+	expectProblem(t, r.post("key", `{"name":"a"}`), http.StatusConflict, "request_in_progress")
+	expectProblem(t, r.post("key", `{"name":"b"}`), http.StatusUnprocessableEntity, "idempotency_key_reused")
+
+	close(r.release)
+	wg.Wait()
+	if first.Code != http.StatusCreated {
+		t.Fatalf("expected the first request to succeed, got %d %s", first.Code, first.Body)
+	}
+	retry := r.post("key", `{"name":"a"}`)
+	if retry.Body.String() != first.Body.String() || retry.Header().Get(middlewares.IdempotentReplayedHeader) != "true" {
+		t.Errorf("expected the retry to replay the first response, got %d %s", retry.Code, retry.Body)
+	}
+	if calls := r.calls.Load(); calls != 1 {
+		t.Errorf("expected a single handler call, got %d", calls)
+	}
+}
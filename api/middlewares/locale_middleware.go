@@ -0,0 +1,26 @@
+package middlewares
+
+import (
+	"github.com/EngenMe/go-clean-architecture/infrastructure/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// LocaleKey is the context key of the negotiated locale
+const LocaleKey = "locale"
+
+// LocaleMiddleware negotiates the locale of the response from the
+// Accept-Language header, falling back to English
+func LocaleMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(LocaleKey, i18n.Negotiate(c.GetHeader("Accept-Language")))
+		c.Next()
+	}
+}
+
+// Locale returns the negotiated locale of the request
+func Locale(c *gin.Context) string {
+	if locale := c.GetString(LocaleKey); locale != "" {
+		return locale
+	}
+	return i18n.Negotiate(c.GetHeader("Accept-Language"))
+}
@@ -1,13 +1,17 @@
 package middlewares
 
 import (
-	"log"
+	"fmt"
+	"io"
+	"log/slog"
+	"runtime/debug"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-// LoggingMiddleware logs HTTP requests
+// LoggingMiddleware logs HTTP requests. The query string is left out since
+// it may carry tokens; the request ID comes from the request context.
 func LoggingMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Before request
@@ -19,18 +23,44 @@ func LoggingMiddleware() gin.HandlerFunc {
 		c.Next()
 
 		// After request
-		latency := time.Since(startTime)
 		statusCode := c.Writer.Status()
-		clientIP := c.ClientIP()
+		attrs := []slog.Attr{
+			slog.String("method", method),
+			slog.String("path", path),
+			slog.String("route", c.FullPath()),
+			slog.Int("status", statusCode),
+			slog.Duration("latency", time.Since(startTime)),
+			slog.String("client_ip", c.ClientIP()),
+			slog.Int("bytes", c.Writer.Size()),
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("errors", c.Errors.String()))
+		}
 
-		log.Printf(
-			"[%s] %s %s %d %s %s",
-			method,
-			path,
-			clientIP,
-			statusCode,
-			latency,
-			c.Errors.String(),
-		)
+		level := slog.LevelInfo
+		switch {
+		case statusCode >= 500:
+			level = slog.LevelError
+		case statusCode >= 400:
+			level = slog.LevelWarn
+		}
+		slog.LogAttrs(c.Request.Context(), level, "Request", attrs...)
 	}
 }
+
+// RecoveryMiddleware turns panics into internal errors reported by
+// ErrorMiddleware, logging the panic with its stack trace. It must be
+// registered after ErrorMiddleware.
+func RecoveryMiddleware() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(
+		io.Discard, func(c *gin.Context, recovered any) {
+			slog.ErrorContext(
+				c.Request.Context(),
+				"Panic recovered",
+				slog.Any("panic", recovered),
+				slog.String("stack", string(debug.Stack())),
+			)
+			abortWithError(c, fmt.Errorf("panic: %v", recovered))
+		},
+	)
+}
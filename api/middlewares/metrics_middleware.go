@@ -0,0 +1,60 @@
+package middlewares
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedRoute labels requests that matched no route, so that arbitrary
+// paths do not create new metric series
+const unmatchedRoute = "unmatched"
+
+// otherMethod labels requests with a nonstandard method, which clients
+// may choose freely
+const otherMethod = "OTHER"
+
+// HTTPMetrics records handled HTTP requests and rejected tokens
+type HTTPMetrics interface {
+	ObserveHTTPRequest(method, route string, status int, duration time.Duration)
+	TokenRejected(reason string)
+}
+
+// MetricsMiddleware records the duration of each request by route
+// template, method and status, and counts the requests AuthMiddleware
+// rejected
+func MetricsMiddleware(metrics HTTPMetrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		metrics.ObserveHTTPRequest(
+			methodLabel(c.Request.Method),
+			route,
+			c.Writer.Status(),
+			time.Since(start),
+		)
+
+		if reason := c.GetString(TokenRejectionKey); reason != "" {
+			metrics.TokenRejected(reason)
+		}
+	}
+}
+
+// methodLabel returns the method of a request as a metric label, mapping
+// the methods outside of the standard ones to otherMethod
+func methodLabel(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return method
+	default:
+		return otherMethod
+	}
+}
@@ -0,0 +1,64 @@
+package middlewares_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/api/middlewares"
+	"github.com/gin-gonic/gin"
+)
+
+// observedRequest is a request recorded by recordingMetrics
+type observedRequest struct {
+	method string
+	route  string
+	status int
+}
+
+// recordingMetrics records the observed requests
+type recordingMetrics struct {
+	requests []observedRequest
+}
+
+// ObserveHTTPRequest records the labels of a request
+func (m *recordingMetrics) ObserveHTTPRequest(method, route string, status int, _ time.Duration) {
+	m.requests = append(m.requests, observedRequest{method, route, status})
+}
+
+// TokenRejected ignores rejected tokens
+func (m *recordingMetrics) TokenRejected(string) {}
+
+func TestMetricsMiddlewareLabels(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	metrics := &recordingMetrics{}
+	router := gin.New()
+	router.Use(middlewares.MetricsMiddleware(metrics))
+	router.Any("/items/:id", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   observedRequest
+	}{
+		{"standard method", http.MethodGet, "/items/1", observedRequest{"GET", "/items/:id", 204}},
+		{"another standard method", http.MethodDelete, "/items/2", observedRequest{"DELETE", "/items/:id", 204}},
+		{"unmatched route", http.MethodGet, "/unknown/3", observedRequest{"GET", "unmatched", 404}},
+		{"nonstandard method", "PROPFIND", "/unknown/4", observedRequest{"OTHER", "unmatched", 404}},
+		{"arbitrary method", "X-RANDOM-5", "/unknown/5", observedRequest{"OTHER", "unmatched", 404}},
+		{"lowercase method", "get", "/unknown/6", observedRequest{"OTHER", "unmatched", 404}},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				metrics.requests = nil
+				router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.path, nil))
+				if len(metrics.requests) != 1 || metrics.requests[0] != tt.want {
+					t.Errorf("expected %+v, got %+v", tt.want, metrics.requests)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,116 @@
+package middlewares
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/infrastructure/ratelimit"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// Rate limit headers, as in the IETF RateLimit header fields draft
+const (
+	RateLimitLimitHeader     = "RateLimit-Limit"
+	RateLimitRemainingHeader = "RateLimit-Remaining"
+	RateLimitResetHeader     = "RateLimit-Reset"
+	RateLimitPolicyHeader    = "RateLimit-Policy"
+	RetryAfterHeader         = "Retry-After"
+	APIKeyHeader             = "X-API-Key"
+)
+
+// APIKeyValidator authenticates API keys and returns the ID of the client
+// a key belongs to
+type APIKeyValidator interface {
+	ValidateAPIKey(key string) (clientID string, ok bool)
+}
+
+// RateLimitMiddleware limits the requests to the routes covered by
+// policies, answering 429 with Retry-After once a client exceeds its
+// quota. It must be registered before the routes. Requests are let
+// through when the limiter store fails. apiKeys may be nil when the API
+// accepts no API keys.
+func RateLimitMiddleware(
+	limiter *ratelimit.Limiter,
+	policies *ratelimit.PolicySet,
+	tokens TokenValidator,
+	apiKeys APIKeyValidator,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policy, ok := policies.Lookup(c.Request.Method + " " + c.FullPath())
+		if !ok {
+			c.Next()
+			return
+		}
+
+		decision, err := limiter.Allow(
+			c.Request.Context(),
+			policy,
+			rateLimitKey(c, policy.Key, tokens, apiKeys),
+		)
+		if err != nil {
+			slog.WarnContext(
+				c.Request.Context(),
+				"Rate limit unavailable, allowing request",
+				slog.String("policy", policy.Name),
+				slog.String("error", err.Error()),
+			)
+			c.Next()
+			return
+		}
+
+		c.Header(RateLimitLimitHeader, strconv.Itoa(decision.Limit))
+		c.Header(RateLimitRemainingHeader, strconv.Itoa(decision.Remaining))
+		c.Header(RateLimitResetHeader, ceilSeconds(decision.Reset))
+		c.Header(
+			RateLimitPolicyHeader,
+			fmt.Sprintf("%d;w=%s", policy.Limit, ceilSeconds(policy.Period)),
+		)
+
+		if !decision.Allowed {
+			c.Header(RetryAfterHeader, ceilSeconds(decision.RetryAfter))
+			abortWithError(c, utils.ErrTooManyRequests)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// rateLimitKey identifies the client of the request for a key source.
+// Only authenticated users and API keys identify a client: anyone could
+// send a new token or key with each request to get a fresh quota.
+func rateLimitKey(
+	c *gin.Context,
+	source ratelimit.KeySource,
+	tokens TokenValidator,
+	apiKeys APIKeyValidator,
+) string {
+	switch source {
+	case ratelimit.KeyByUser:
+		// The route may be public, so the token is checked here rather
+		// than relying on AuthMiddleware having run
+		token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if found {
+			if claims, err := tokens.ValidateToken(token); err == nil {
+				return fmt.Sprintf("user:%d", claims.UserID)
+			}
+		}
+	case ratelimit.KeyByAPIKey:
+		if apiKey := c.GetHeader(APIKeyHeader); apiKey != "" && apiKeys != nil {
+			if clientID, ok := apiKeys.ValidateAPIKey(apiKey); ok {
+				return "api_key:" + clientID
+			}
+		}
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// ceilSeconds formats a duration as a whole number of seconds, rounded up
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(max(d, 0).Seconds())))
+}
@@ -0,0 +1,68 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/EngenMe/go-clean-architecture/infrastructure/ratelimit"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// fakeTokens accepts the token "valid" as user 42
+type fakeTokens struct{}
+
+// ValidateToken accepts the token "valid"
+func (fakeTokens) ValidateToken(token string) (*utils.JWTClaims, error) {
+	if token != "valid" {
+		return nil, errors.New("invalid token")
+	}
+	return &utils.JWTClaims{UserID: 42}, nil
+}
+
+// fakeAPIKeys accepts the keys of a map, by client ID
+type fakeAPIKeys map[string]string
+
+// ValidateAPIKey returns the client of a known key
+func (k fakeAPIKeys) ValidateAPIKey(key string) (string, bool) {
+	clientID, ok := k[key]
+	return clientID, ok
+}
+
+func TestRateLimitKey(t *testing.T) {
+	apiKeys := fakeAPIKeys{"partner-key": "partner"}
+	tests := []struct {
+		name    string
+		source  ratelimit.KeySource
+		header  map[string]string
+		apiKeys APIKeyValidator
+		want    string
+	}{
+		{"ip", ratelimit.KeyByIP, map[string]string{"Authorization": "Bearer valid"}, apiKeys, "ip:203.0.113.7"},
+		{"user", ratelimit.KeyByUser, map[string]string{"Authorization": "Bearer valid"}, apiKeys, "user:42"},
+		{"invalid token", ratelimit.KeyByUser, map[string]string{"Authorization": "Bearer forged"}, apiKeys, "ip:203.0.113.7"},
+		{"no token", ratelimit.KeyByUser, nil, apiKeys, "ip:203.0.113.7"},
+		{"api key", ratelimit.KeyByAPIKey, map[string]string{APIKeyHeader: "partner-key"}, apiKeys, "api_key:partner"},
+		{"unknown api key", ratelimit.KeyByAPIKey, map[string]string{APIKeyHeader: "made-up"}, apiKeys, "ip:203.0.113.7"},
+		{"no api key", ratelimit.KeyByAPIKey, nil, apiKeys, "ip:203.0.113.7"},
+		{"api keys not accepted", ratelimit.KeyByAPIKey, map[string]string{APIKeyHeader: "partner-key"}, nil, "ip:203.0.113.7"},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				c, _ := gin.CreateTestContext(httptest.NewRecorder())
+				c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/users", nil)
+				c.Request.RemoteAddr = "203.0.113.7:41234"
+				for name, value := range tt.header {
+					c.Request.Header.Set(name, value)
+				}
+
+				if got := rateLimitKey(c, tt.source, fakeTokens{}, tt.apiKeys); got != tt.want {
+					t.Errorf("expected key %q, got %q", tt.want, got)
+				}
+			},
+		)
+	}
+}
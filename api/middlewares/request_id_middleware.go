@@ -0,0 +1,47 @@
+package middlewares
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/EngenMe/go-clean-architecture/infrastructure/logging"
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDKey is the context key of the request ID
+const RequestIDKey = "requestID"
+
+// requestIDPattern restricts propagated request IDs, which end up in logs
+// and response headers, to short printable tokens
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestIDMiddleware propagates the X-Request-ID header, or generates an
+// ID when it is missing or invalid. The ID is echoed in the response and
+// carried by the request context, so that every log line written while
+// handling the request includes it.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(logging.RequestIDHeader)
+		if !requestIDPattern.MatchString(id) {
+			id = newRandomID()
+		}
+
+		c.Set(RequestIDKey, id)
+		c.Header(logging.RequestIDHeader, id)
+		c.Request = c.Request.WithContext(
+			logging.WithRequestID(c.Request.Context(), id),
+		)
+
+		c.Next()
+	}
+}
+
+// newRandomID returns 16 random bytes as hex
+func newRandomID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,91 @@
+package middlewares
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole is a middleware allowing only users with the given role.
+// It must run after AuthMiddleware, which sets the user role.
+func RequireRole(role entities.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userRole, _ := c.Get("userRole")
+		if userRole != role {
+			abortWithError(
+				c,
+				utils.NewLocalizedError(utils.ErrForbidden, "error.role_required", "role", string(role)),
+			)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireSelfOrRole is a middleware allowing only the user whose ID is the
+// path parameter param, and users with the given role. It must run after
+// AuthMiddleware, which sets the user ID and role. Invalid IDs are let
+// through for the handler to reject.
+func RequireSelfOrRole(param string, role entities.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userRole, _ := c.Get("userRole")
+		if userRole == role {
+			c.Next()
+			return
+		}
+
+		id, err := strconv.ParseUint(c.Param(param), 10, 32)
+		userID, _ := c.Get("userID")
+		if err == nil && userID != uint(id) {
+			abortWithError(
+				c,
+				utils.NewLocalizedError(utils.ErrForbidden, "error.self_or_role_required", "role", string(role)),
+			)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// Access is the authorization a route requires
+type Access string
+
+// Access levels
+const (
+	AccessPublic        Access = "public"
+	AccessAuthenticated Access = "authenticated"
+	AccessAdmin         Access = "admin"
+)
+
+// ParseAccess parses an access level name
+func ParseAccess(value string) (Access, error) {
+	switch access := Access(value); access {
+	case AccessPublic, AccessAuthenticated, AccessAdmin:
+		return access, nil
+	default:
+		return "", fmt.Errorf(
+			"%w: unknown access %q (expected public, authenticated or admin)",
+			utils.ErrInvalidInput,
+			value,
+		)
+	}
+}
+
+// Authorize returns the middlewares enforcing access: none for public
+// routes, authentication, or authentication and the admin role. Unknown
+// levels are treated as admin-only.
+func Authorize(access Access, authMiddleware gin.HandlerFunc) []gin.HandlerFunc {
+	switch access {
+	case AccessPublic:
+		return nil
+	case AccessAuthenticated:
+		return []gin.HandlerFunc{authMiddleware}
+	default:
+		return []gin.HandlerFunc{authMiddleware, RequireRole(entities.RoleAdmin)}
+	}
+}
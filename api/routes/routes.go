@@ -1,35 +1,153 @@
 package routes
 
 import (
+	"time"
+
 	"github.com/EngenMe/go-clean-architecture/api/handlers"
 	"github.com/EngenMe/go-clean-architecture/api/middlewares"
+	"github.com/EngenMe/go-clean-architecture/application/behaviors"
 	"github.com/EngenMe/go-clean-architecture/application/services"
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/health"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/metrics"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/ratelimit"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 	"github.com/swaggo/files"
 	"github.com/swaggo/gin-swagger"
 
 	_ "github.com/EngenMe/go-clean-architecture/docs" // Import generated Swagger docs
 )
 
+// Dependencies holds the services and stores used by the routes
+type Dependencies struct {
+	AuthService       *services.AuthService
+	UserService       *services.UserService
+	WebhookService    *services.WebhookService
+	RequestMetrics    *behaviors.RequestMetrics
+	Metrics           *metrics.Metrics // nil disables /metrics
+	Health            *health.Registry // nil reports ready without checks
+	JWT               *utils.JWT
+	IdempotencyStore  repositories.IdempotencyStore
+	IdempotencyTTL    time.Duration
+	RateLimiter       *ratelimit.Limiter // nil disables rate limiting
+	RateLimitPolicies *ratelimit.PolicySet
+	APIKeys           middlewares.APIKeyValidator // nil accepts no API keys
+	CORS              *middlewares.CORSPolicy     // nil disables CORS
+	UserCreateAccess  middlewares.Access          // Defaults to admin
+	UserLookupAccess  middlewares.Access          // Defaults to admin
+	Clock             clock.Clock                 // Defaults to the system clock
+}
+
 // SetupRoutes configures all API routes
-func SetupRoutes(
-	router *gin.Engine,
-	authService *services.AuthService,
-	userService *services.UserService,
-) {
+func SetupRoutes(router *gin.Engine, deps Dependencies) {
+	// Report validation errors of request bodies with their JSON field names
+	if validate, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		validate.RegisterTagNameFunc(utils.JSONFieldName)
+	}
+
 	// Register global middlewares
-	router.Use(middlewares.LoggingMiddleware())
+	router.Use(
+		middlewares.RequestIDMiddleware(),
+		middlewares.TracingMiddleware(),
+		middlewares.LoggingMiddleware(),
+	)
+	if deps.Metrics != nil {
+		router.Use(middlewares.MetricsMiddleware(deps.Metrics))
+	}
+	router.Use(
+		middlewares.LocaleMiddleware(),
+		middlewares.ErrorMiddleware(),
+		middlewares.RecoveryMiddleware(),
+	)
+	if deps.CORS != nil {
+		router.Use(middlewares.CORSMiddleware(deps.CORS))
+	}
+	if deps.RateLimiter != nil {
+		router.Use(
+			middlewares.RateLimitMiddleware(
+				deps.RateLimiter,
+				deps.RateLimitPolicies,
+				deps.JWT,
+				deps.APIKeys,
+			),
+		)
+	}
 
 	// Create an API group
 	api := router.Group("/api/v1")
 
-	// Register auth routes (no auth middleware - these are public endpoints)
-	authHandler := handlers.NewAuthHandler(authService)
-	authHandler.RegisterRoutes(api)
+	// Retried creations replay their first response instead of failing
+	clk := deps.Clock
+	if clk == nil {
+		clk = clock.System
+	}
+	idempotencyMiddleware := middlewares.IdempotencyMiddleware(
+		deps.IdempotencyStore,
+		deps.IdempotencyTTL,
+		clk,
+		nil,
+	)
+	authMiddleware := middlewares.AuthMiddleware(deps.JWT)
+
+	// Register auth routes (no auth middleware - these are public endpoints).
+	// Sign-up responses are stored without their token.
+	authHandler := handlers.NewAuthHandler(deps.AuthService)
+	authHandler.RegisterRoutes(
+		api,
+		middlewares.IdempotencyMiddleware(
+			deps.IdempotencyStore,
+			deps.IdempotencyTTL,
+			clk,
+			authHandler.TokenlessResponses(),
+		),
+	)
 
 	// Register user routes with auth middleware
-	userHandler := handlers.NewUserHandler(userService)
-	userHandler.RegisterRoutes(api, middlewares.AuthMiddleware())
+	userHandler := handlers.NewUserHandler(deps.UserService)
+	userHandler.RegisterRoutes(
+		api,
+		authMiddleware,
+		idempotencyMiddleware,
+		handlers.UserRouteAccess{
+			Create:        middlewares.Authorize(deps.UserCreateAccess, authMiddleware),
+			LookupByEmail: middlewares.Authorize(deps.UserLookupAccess, authMiddleware),
+			PublicLookup:  deps.UserLookupAccess == middlewares.AccessPublic,
+		},
+	)
+
+	// Register webhook routes, restricted to admins
+	webhookHandler := handlers.NewWebhookHandler(deps.WebhookService)
+	webhookHandler.RegisterRoutes(
+		api,
+		authMiddleware,
+		middlewares.RequireRole(entities.RoleAdmin),
+	)
+
+	// Register admin routes
+	adminHandler := handlers.NewAdminHandler(deps.RequestMetrics)
+	adminHandler.RegisterRoutes(
+		api,
+		authMiddleware,
+		middlewares.RequireRole(entities.RoleAdmin),
+	)
+
+	// Unknown routes get a problem response as well
+	router.NoRoute(
+		func(c *gin.Context) {
+			_ = c.Error(
+				utils.NewLocalizedError(
+					utils.ErrNotFound,
+					"error.no_route",
+					"path", c.Request.URL.Path,
+				),
+			)
+		},
+	)
 
 	// Serve Swagger UI
 	router.GET(
@@ -37,20 +155,16 @@ func SetupRoutes(
 		ginSwagger.WrapHandler(swaggerFiles.Handler),
 	)
 
-	// Health check route
-	// @Summary Health check
-	// @Description Checks if the API is running
-	// @Tags Health
-	// @Produce json
-	// @Success 200 {object} map[string]string
-	// @Router /health [get]
-	router.GET(
-		"/health", func(c *gin.Context) {
-			c.JSON(
-				200, gin.H{
-					"status": "up",
-				},
-			)
-		},
-	)
+	// Prometheus metrics, in the text exposition format
+	if deps.Metrics != nil {
+		router.GET("/metrics", gin.WrapH(deps.Metrics.Handler()))
+	}
+
+	// Liveness and readiness probes
+	healthRegistry := deps.Health
+	if healthRegistry == nil {
+		healthRegistry = health.NewRegistry(time.Second)
+	}
+	healthHandler := handlers.NewHealthHandler(healthRegistry)
+	healthHandler.RegisterRoutes(router)
 }
@@ -0,0 +1,399 @@
+// Package app builds the API from its configuration: storage, mediator,
+// services, routes and background workers. The CLI serves it over HTTP,
+// and tests can run it in-process with their own storage, clock, random
+// source and mailer. Applications share no state, so that several can coexist.
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/api/middlewares"
+	"github.com/EngenMe/go-clean-architecture/api/routes"
+	"github.com/EngenMe/go-clean-architecture/application/behaviors"
+	"github.com/EngenMe/go-clean-architecture/application/mediator"
+	"github.com/EngenMe/go-clean-architecture/application/services"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/config"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/health"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/messaging"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/metrics"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/random"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/ratelimit"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+// App is an instance of the API
+type App struct {
+	config         *config.Config
+	clock          clock.Clock
+	random         random.Source
+	storage        Storage
+	ownsDB         bool // the database was connected by New
+	mediator       *mediator.Mediator
+	jwt            *utils.JWT
+	metrics        *metrics.Metrics
+	requestMetrics *behaviors.RequestMetrics
+	userRepository repositories.UserRepository
+	userService    *services.UserService
+	authService    *services.AuthService
+	webhookService *services.WebhookService
+	health         *health.Registry
+	cors           *middlewares.CORSPolicy
+	policySet      *ratelimit.PolicySet // nil when rate limiting is disabled
+	policies       []ratelimit.Policy
+	handler        http.Handler
+
+	relay       *messaging.OutboxRelay
+	dispatcher  *messaging.WebhookDispatcher
+	publishers  io.Closer
+	stopWorkers context.CancelFunc
+	workers     sync.WaitGroup
+}
+
+// New builds an application from cfg, or from the default configuration
+// when cfg is nil, with the overrides of opts. The configuration is
+// validated once overridden. Close releases the application.
+func New(ctx context.Context, cfg *config.Config, opts ...Option) (*App, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	// Override a copy of the configuration
+	if cfg == nil {
+		// Load derives the log level from ENV when it is not set
+		cfg = config.Default()
+		cfg.Log.Level = "info"
+	}
+	overridden := *cfg
+	for _, configure := range o.configure {
+		configure(&overridden)
+	}
+	if err := overridden.Validate(); err != nil {
+		return nil, err
+	}
+
+	a := &App{
+		config:         &overridden,
+		clock:          o.clock,
+		random:         o.random,
+		metrics:        metrics.New(),
+		requestMetrics: behaviors.NewRequestMetrics(),
+	}
+	if a.clock == nil {
+		a.clock = clock.System
+	}
+	if a.random == nil {
+		a.random = random.Crypto
+	}
+	a.jwt = utils.NewJWT(
+		overridden.JWT.Secret,
+		overridden.JWT.PreviousSecrets,
+		overridden.JWT.Expiration(),
+		a.clock,
+	)
+	if err := a.build(ctx, o); err != nil {
+		a.Close()
+		return nil, err
+	}
+	return a, nil
+}
+
+// build connects the storage and creates the services, the workers and
+// the routes
+func (a *App) build(ctx context.Context, o options) error {
+	// Trace, log, measure and validate every command and query
+	a.mediator = mediator.New(
+		behaviors.NewTracingBehavior(),
+		behaviors.NewLoggingBehavior(nil),
+		behaviors.NewMetricsBehavior(a.requestMetrics, a.metrics),
+		behaviors.NewValidationBehavior(),
+	)
+
+	if o.storage != nil {
+		a.storage = *o.storage
+	} else {
+		storage, err := openStorage(ctx, a.config, a.metrics, a.clock)
+		if err != nil {
+			return err
+		}
+		a.storage, a.ownsDB = storage, true
+	}
+
+	// Register services
+	var err error
+	a.userRepository = services.NewUserRepositoryAdapter(a.storage.Users)
+	a.userService, err = services.RegisterUserService(
+		a.mediator,
+		a.storage.Users,
+		a.storage.UnitOfWork,
+		a.storage.Outbox,
+		a.clock,
+		a.random,
+	)
+	if err != nil {
+		return err
+	}
+	a.authService = services.RegisterAuthService(
+		a.mediator,
+		a.userRepository,
+		a.jwt,
+		a.metrics,
+	)
+	a.webhookService, err = services.RegisterWebhookService(
+		a.mediator,
+		a.storage.Webhooks,
+		a.storage.Deliveries,
+		a.clock,
+		a.random,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Deliver domain events from the outbox and send webhooks once started
+	mailer := o.mailer
+	if !o.mailerSet {
+		if mailer, err = newMailer(a.config.Mail); err != nil {
+			return err
+		}
+	}
+	if a.relay, a.publishers, err = newOutboxRelay(a, mailer); err != nil {
+		return err
+	}
+	a.dispatcher = newWebhookDispatcher(a)
+
+	if a.health, err = newHealthRegistry(a); err != nil {
+		return err
+	}
+	a.handler, err = a.newRouter()
+	return err
+}
+
+// newRouter creates the Gin engine serving the routes
+func (a *App) newRouter() (http.Handler, error) {
+	cfg := a.config
+	deps := routes.Dependencies{
+		AuthService:      a.authService,
+		UserService:      a.userService,
+		WebhookService:   a.webhookService,
+		RequestMetrics:   a.requestMetrics,
+		Health:           a.health,
+		JWT:              a.jwt,
+		IdempotencyStore: a.storage.Idempotency,
+		IdempotencyTTL:   cfg.Idempotency.TTL,
+		Clock:            a.clock,
+	}
+	if cfg.Metrics.Enabled {
+		deps.Metrics = a.metrics
+	}
+	if err := configureUserAccess(&deps, cfg.Access); err != nil {
+		return nil, err
+	}
+	if cfg.RateLimit.Enabled {
+		apiKeys, err := ratelimit.ParseAPIKeys(cfg.RateLimit.APIKeys)
+		if err != nil {
+			return nil, err
+		}
+		if apiKeys.Len() > 0 {
+			deps.APIKeys = apiKeys
+		}
+		policies, err := ratelimit.LoadPolicies(cfg.RateLimit.PoliciesFile, apiKeys.Len() > 0)
+		if err != nil {
+			return nil, err
+		}
+		if err := CheckPolicies(cfg, policies); err != nil {
+			return nil, err
+		}
+		a.policySet, a.policies = ratelimit.NewPolicySet(policies), policies
+		deps.RateLimiter = ratelimit.NewLimiter(a.storage.RateLimits, a.clock)
+		deps.RateLimitPolicies = a.policySet
+	}
+	// CORS is always installed so that origins can be allowed by a reload
+	a.cors = middlewares.NewCORSPolicy(cfg.CORS.AllowedOrigins)
+	deps.CORS = a.cors
+
+	// Requests are logged by the routes' middlewares
+	router := gin.New()
+
+	// Client IPs, which rate limits are keyed by, are only taken from
+	// X-Forwarded-For when the request comes through a trusted proxy
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid TRUSTED_PROXIES: %w", err)
+	}
+
+	routes.SetupRoutes(router, deps)
+	return router, nil
+}
+
+// Handler returns the HTTP handler serving the API
+func (a *App) Handler() http.Handler {
+	return a.handler
+}
+
+// Config returns the configuration the application was built with
+func (a *App) Config() *config.Config {
+	return a.config
+}
+
+// UserService returns the service managing users
+func (a *App) UserService() *services.UserService {
+	return a.userService
+}
+
+// Mediator returns the mediator of the commands, queries and in-process
+// notifications, e.g. to subscribe to the events of the mediatr publisher
+func (a *App) Mediator() *mediator.Mediator {
+	return a.mediator
+}
+
+// UserRepository returns the repository of users
+func (a *App) UserRepository() repositories.UserRepository {
+	return a.userRepository
+}
+
+// JWT returns the issuer and validator of access tokens
+func (a *App) JWT() *utils.JWT {
+	return a.jwt
+}
+
+// RateLimitPolicies returns the rate limit policies in force, nil when
+// rate limiting is disabled
+func (a *App) RateLimitPolicies() []ratelimit.Policy {
+	return a.policies
+}
+
+// Reconfigure applies the reloadable settings of cfg while requests are
+// served: the JWT keys, the CORS origins and, when rate limiting is
+// enabled, policies. Other settings only apply to a new application.
+func (a *App) Reconfigure(cfg *config.Config, policies []ratelimit.Policy) {
+	a.jwt.SetKeys(cfg.JWT.Secret, cfg.JWT.PreviousSecrets, cfg.JWT.Expiration())
+	a.cors.SetAllowedOrigins(cfg.CORS.AllowedOrigins)
+	if a.policySet != nil {
+		a.policySet.Store(policies)
+		a.policies = policies
+	}
+}
+
+// Start runs the background workers until Close: the outbox relay, the
+// webhook dispatcher and the pruning of expired records
+func (a *App) Start(ctx context.Context) {
+	ctx, a.stopWorkers = context.WithCancel(ctx)
+	a.workers.Add(3)
+	go func() {
+		defer a.workers.Done()
+		a.relay.Run(ctx)
+	}()
+	go func() {
+		defer a.workers.Done()
+		a.dispatcher.Run(ctx)
+	}()
+	go func() {
+		defer a.workers.Done()
+		a.pruneExpired(ctx)
+	}()
+}
+
+// Drain reports the application unready, so that load balancers stop
+// sending it new requests before it shuts down
+func (a *App) Drain() {
+	a.health.SetShuttingDown()
+}
+
+// Close stops the background workers and releases the publishers and the
+// database connection opened by New
+func (a *App) Close() error {
+	if a.stopWorkers != nil {
+		a.stopWorkers()
+		a.workers.Wait()
+	}
+
+	var first error
+	if a.publishers != nil {
+		first = a.publishers.Close()
+	}
+	if a.ownsDB {
+		if err := closeDatabase(a.storage.DB); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// pruneExpired deletes expired idempotency records and rate limit
+// counters every hour until ctx is canceled
+func (a *App) pruneExpired(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			now := a.clock.Now()
+			deleted, err := a.storage.Idempotency.DeleteExpired(ctx, now)
+			if err != nil && ctx.Err() == nil {
+				slog.Error("Failed to prune idempotency keys", slog.String("error", err.Error()))
+			} else if deleted > 0 {
+				slog.Info("Pruned expired idempotency keys", slog.Int64("count", deleted))
+			}
+
+			deleted, err = a.storage.RateLimits.DeleteExpired(ctx, now)
+			if err != nil && ctx.Err() == nil {
+				slog.Error("Failed to prune rate limit counters", slog.String("error", err.Error()))
+			} else if deleted > 0 {
+				slog.Info("Pruned expired rate limit counters", slog.Int64("count", deleted))
+			}
+		}
+	}
+}
+
+// userLookupRoute is the route looking users up by email
+const userLookupRoute = "GET /api/v1/users/email/:email"
+
+// CheckPolicies checks that the rate limit policies cover the routes cfg
+// opens to anonymous callers: a public lookup by email, which tells
+// whether an account exists, must be rate limited
+func CheckPolicies(cfg *config.Config, policies []ratelimit.Policy) error {
+	if cfg.Access.UserLookup != string(middlewares.AccessPublic) {
+		return nil
+	}
+	for _, policy := range policies {
+		if slices.Contains(policy.Routes, userLookupRoute) {
+			return nil
+		}
+	}
+	return fmt.Errorf(
+		"%w: USER_LOOKUP_ACCESS=public requires a rate limit policy covering %q",
+		utils.ErrInvalidInput,
+		userLookupRoute,
+	)
+}
+
+// configureUserAccess reads who may create users and look them up by
+// email, as validated with the configuration
+func configureUserAccess(deps *routes.Dependencies, cfg config.AccessConfig) error {
+	createAccess, err := middlewares.ParseAccess(cfg.UserCreate)
+	if err != nil {
+		return fmt.Errorf("invalid USER_CREATE_ACCESS: %w", err)
+	}
+
+	lookupAccess, err := middlewares.ParseAccess(cfg.UserLookup)
+	if err != nil {
+		return fmt.Errorf("invalid USER_LOOKUP_ACCESS: %w", err)
+	}
+
+	deps.UserCreateAccess = createAccess
+	deps.UserLookupAccess = lookupAccess
+	return nil
+}
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/application/services"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/database"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/health"
+)
+
+// newHealthRegistry creates the readiness checks: the database answers a
+// ping and has every migration of this version applied, so that newer
+// versions may migrate during a rolling deployment, the mediatr handlers are
+// registered and the outbox relay keeps up with the domain events
+func newHealthRegistry(a *App) (*health.Registry, error) {
+	registry := health.NewRegistry(a.config.Health.CheckTimeout)
+
+	if db := a.storage.DB; db != nil {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get database connection: %w", err)
+		}
+		registry.Register("database", sqlDB.PingContext)
+
+		migrator, err := database.NewMigrator(db)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load migrations: %w", err)
+		}
+		registry.Register(
+			"migrations", func(ctx context.Context) error {
+				version, err := migrator.AppliedVersion(ctx)
+				if err != nil {
+					return err
+				}
+				if latest := migrator.LatestVersion(); version < latest {
+					return fmt.Errorf(
+						"schema is at version %d, expected at least %d",
+						version,
+						latest,
+					)
+				}
+				return nil
+			},
+		)
+	}
+
+	registry.Register(
+		"mediatr", func(context.Context) error {
+			return services.CheckHandlers(a.mediator)
+		},
+	)
+
+	maxLag := a.config.Health.OutboxMaxLag
+	registry.Register(
+		"outbox", func(ctx context.Context) error {
+			oldest, err := a.storage.Outbox.OldestPending(ctx)
+			if err != nil {
+				return err
+			}
+			if oldest == nil {
+				return nil
+			}
+			if lag := a.clock.Now().Sub(*oldest); lag > maxLag {
+				return fmt.Errorf(
+					"oldest pending event is %s old, more than %s",
+					lag.Round(time.Second),
+					maxLag,
+				)
+			}
+			return nil
+		},
+	)
+
+	return registry, nil
+}
@@ -0,0 +1,61 @@
+package app
+
+import (
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/config"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/random"
+	"github.com/EngenMe/go-clean-architecture/interfaces/messaging"
+)
+
+// Option overrides a part of the application built by New
+type Option func(*options)
+
+// options holds the overrides of an application
+type options struct {
+	storage   *Storage
+	clock     clock.Clock
+	random    random.Source
+	mailer    messaging.Mailer
+	mailerSet bool
+	configure []func(*config.Config)
+}
+
+// WithStorage uses storage instead of connecting the one selected by
+// STORAGE. The caller owns it: Close does not close its database.
+func WithStorage(storage Storage) Option {
+	return func(o *options) {
+		o.storage = &storage
+	}
+}
+
+// WithClock uses c instead of the system clock
+func WithClock(c clock.Clock) Option {
+	return func(o *options) {
+		o.clock = c
+	}
+}
+
+// WithRandom reads event IDs and generated secrets from source instead of
+// the cryptographically secure source of the operating system
+func WithRandom(source random.Source) Option {
+	return func(o *options) {
+		o.random = source
+	}
+}
+
+// WithMailer sends the emails through mailer instead of the one selected
+// by MAILER; nil disables emails
+func WithMailer(mailer messaging.Mailer) Option {
+	return func(o *options) {
+		o.mailer = mailer
+		o.mailerSet = true
+	}
+}
+
+// WithConfig changes the configuration before it is validated, e.g. to
+// set a JWT secret or disable rate limiting in tests
+func WithConfig(configure func(cfg *config.Config)) Option {
+	return func(o *options) {
+		o.configure = append(o.configure, configure)
+	}
+}
@@ -0,0 +1,126 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/EngenMe/go-clean-architecture/infrastructure/config"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/mail"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/messaging"
+	messagingInterfaces "github.com/EngenMe/go-clean-architecture/interfaces/messaging"
+)
+
+// newOutboxRelay creates the relay delivering outbox events to the
+// webhook subscriptions and to the publishers listed in OUTBOX_PUBLISHERS
+// (log, file and mediatr), and sending welcome emails through mailer
+// unless nil. The returned closer releases the resources held by the
+// publishers.
+func newOutboxRelay(
+	a *App,
+	mailer messagingInterfaces.Mailer,
+) (*messaging.OutboxRelay, io.Closer, error) {
+	cfg := a.config.Outbox
+	publishers := []messagingInterfaces.Publisher{
+		messaging.NewWebhookPublisher(a.storage.Webhooks, a.storage.Deliveries, a.clock),
+	}
+	var closers closerList
+
+	if mailer != nil {
+		publishers = append(publishers, messaging.NewWelcomeMailPublisher(mailer))
+	}
+
+	for _, name := range cfg.Publishers {
+		switch name {
+		case "log":
+			publishers = append(publishers, messaging.NewLogPublisher(os.Stdout))
+		case "file":
+			publisher, closer, err := messaging.NewFilePublisher(cfg.File)
+			if err != nil {
+				closers.Close()
+				return nil, nil, err
+			}
+			publishers = append(publishers, publisher)
+			closers = append(closers, closer)
+		case "mediatr":
+			publishers = append(publishers, messaging.NewMediatrPublisher(a.mediator))
+		default:
+			closers.Close()
+			return nil, nil, fmt.Errorf(
+				"unsupported OUTBOX_PUBLISHERS entry %q (expected log, file or mediatr)",
+				name,
+			)
+		}
+	}
+
+	options := messaging.DefaultRelayOptions()
+	options.PollInterval = cfg.PollInterval
+	options.BatchSize = cfg.BatchSize
+	options.Clock = a.clock
+	options.Random = a.random
+
+	relay := messaging.NewOutboxRelay(
+		a.storage.Outbox,
+		messaging.NewMultiPublisher(publishers...),
+		options,
+	)
+	return relay, closers, nil
+}
+
+// newMailer creates the configured mailer: log, smtp or none, which
+// disables emails and returns a nil mailer
+func newMailer(cfg config.MailConfig) (messagingInterfaces.Mailer, error) {
+	switch cfg.Mailer {
+	case "none":
+		return nil, nil
+	case "log":
+		return mail.NewLogMailer(), nil
+	case "smtp":
+		mailer, err := mail.NewSMTPMailer(
+			mail.SMTPOptions{
+				Host:     cfg.SMTP.Host,
+				Port:     strconv.Itoa(cfg.SMTP.Port),
+				Username: cfg.SMTP.Username,
+				Password: cfg.SMTP.Password,
+				From:     cfg.SMTP.From,
+			},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SMTP configuration: %w", err)
+		}
+		return mailer, nil
+	default:
+		return nil, fmt.Errorf("unsupported MAILER %q (expected log, smtp or none)", cfg.Mailer)
+	}
+}
+
+// newWebhookDispatcher creates the worker sending webhook deliveries
+func newWebhookDispatcher(a *App) *messaging.WebhookDispatcher {
+	options := messaging.DefaultWebhookOptions()
+	options.PollInterval = a.config.Webhooks.PollInterval
+	options.Timeout = a.config.Webhooks.Timeout
+	options.MaxAttempts = a.config.Webhooks.MaxAttempts
+	options.Clock = a.clock
+	options.Random = a.random
+
+	return messaging.NewWebhookDispatcher(
+		a.storage.Webhooks,
+		a.storage.Deliveries,
+		options,
+	)
+}
+
+// closerList closes several resources, returning the first error
+type closerList []io.Closer
+
+// Close closes every resource of the list
+func (l closerList) Close() error {
+	var first error
+	for _, closer := range l {
+		if err := closer.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
@@ -0,0 +1,146 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/config"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/database"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/memory"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/metrics"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/tracing"
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+	"gorm.io/gorm"
+)
+
+// Storage holds the repositories and stores of an application
+type Storage struct {
+	DB          *gorm.DB // nil with in-memory storage
+	Users       repositories.GenericRepository[entities.User]
+	UnitOfWork  repositories.UnitOfWork
+	Outbox      repositories.OutboxRepository
+	Webhooks    repositories.GenericRepository[entities.WebhookSubscription]
+	Deliveries  repositories.WebhookDeliveryRepository
+	Idempotency repositories.IdempotencyStore
+	RateLimits  repositories.RateLimitStore
+}
+
+// MemoryStorage creates empty in-memory storage timestamping records
+// with clk
+func MemoryStorage(clk clock.Clock) Storage {
+	return Storage{
+		Users:       memory.NewGenericMemoryUserRepository(clk),
+		UnitOfWork:  memory.NewUnitOfWork(),
+		Outbox:      memory.NewOutboxRepository(clk),
+		Webhooks:    memory.NewGenericMemoryRepository[entities.WebhookSubscription](clk),
+		Deliveries:  memory.NewWebhookDeliveryRepository(clk),
+		Idempotency: memory.NewIdempotencyStore(),
+		RateLimits:  memory.NewRateLimitStore(),
+	}
+}
+
+// DatabaseStorage creates storage backed by db, including the rate limit
+// counters. Deliveries are timestamped with clk, which should also be the
+// clock of db (see database.NewDatabaseConnection).
+func DatabaseStorage(db *gorm.DB, clk clock.Clock) Storage {
+	return Storage{
+		DB:          db,
+		Users:       database.NewGenericPostgresRepository[entities.User](db),
+		UnitOfWork:  database.NewGormUnitOfWork(db),
+		Outbox:      database.NewGormOutboxRepository(db),
+		Webhooks:    database.NewGenericPostgresRepository[entities.WebhookSubscription](db),
+		Deliveries:  database.NewGormWebhookDeliveryRepository(db, clk),
+		Idempotency: database.NewGormIdempotencyStore(db),
+		RateLimits:  database.NewGormRateLimitStore(db),
+	}
+}
+
+// openStorage connects the storage selected by STORAGE and
+// RATE_LIMIT_STORE, instrumenting the database connection and
+// timestamping records with clk
+func openStorage(
+	ctx context.Context,
+	cfg *config.Config,
+	m *metrics.Metrics,
+	clk clock.Clock,
+) (Storage, error) {
+	var storage Storage
+	switch cfg.Storage {
+	case "database":
+		db, err := database.NewDatabaseConnection(cfg.Database, clk)
+		if err != nil {
+			return Storage{}, err
+		}
+		storage = DatabaseStorage(db, clk)
+		if err := instrumentDatabase(ctx, cfg, db, m); err != nil {
+			closeDatabase(db)
+			return Storage{}, err
+		}
+	case "memory":
+		slog.Warn("Using in-memory storage, data will be lost on shutdown")
+		storage = MemoryStorage(clk)
+	default:
+		return Storage{}, fmt.Errorf(
+			"unsupported STORAGE %q (expected database or memory)",
+			cfg.Storage,
+		)
+	}
+
+	// Rate limit counters are local to the process unless shared through
+	// the database
+	switch store := cfg.RateLimit.Store; {
+	case store == "memory":
+		storage.RateLimits = memory.NewRateLimitStore()
+	case store == "database" && storage.DB != nil:
+	default:
+		closeDatabase(storage.DB)
+		return Storage{}, fmt.Errorf(
+			"unsupported RATE_LIMIT_STORE %q (expected memory, or database with database storage)",
+			store,
+		)
+	}
+
+	return storage, nil
+}
+
+// instrumentDatabase measures and traces the queries of db, and applies
+// the pending migrations when explicitly requested
+func instrumentDatabase(
+	ctx context.Context,
+	cfg *config.Config,
+	db *gorm.DB,
+	m *metrics.Metrics,
+) error {
+	if err := m.InstrumentDB(db, cfg.Database.Name); err != nil {
+		return err
+	}
+	if err := db.Use(tracing.NewGormPlugin()); err != nil {
+		return fmt.Errorf("failed to register database tracing: %w", err)
+	}
+
+	if cfg.Database.MigrateOnStart {
+		migrator, err := database.NewMigrator(db)
+		if err != nil {
+			return fmt.Errorf("failed to load migrations: %w", err)
+		}
+		if _, err := migrator.Up(ctx); err != nil {
+			return fmt.Errorf("failed to migrate database: %w", err)
+		}
+	}
+	return nil
+}
+
+// closeDatabase closes the connections of db, if any
+func closeDatabase(db *gorm.DB) error {
+	if db == nil {
+		return nil
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
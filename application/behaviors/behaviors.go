@@ -0,0 +1,14 @@
+// Package behaviors implements the mediatr pipeline behaviors that wrap
+// every command and query: tracing, validation, logging and metrics.
+package behaviors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RequestName returns the name of a request type, e.g. "CreateUserCommand"
+func RequestName(request interface{}) string {
+	name := fmt.Sprintf("%T", request)
+	return name[strings.LastIndex(name, ".")+1:]
+}
@@ -0,0 +1,59 @@
+package behaviors
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/mehdihadeli/go-mediatr"
+)
+
+// LoggingBehavior logs every request with its type, duration and outcome
+type LoggingBehavior struct {
+	logger *slog.Logger
+}
+
+// NewLoggingBehavior creates a new logging behavior writing to logger, or
+// to the default logger if nil
+func NewLoggingBehavior(logger *slog.Logger) mediatr.PipelineBehavior {
+	return &LoggingBehavior{logger: logger}
+}
+
+// Handle calls the next handler and logs the outcome
+func (b *LoggingBehavior) Handle(
+	ctx context.Context,
+	request interface{},
+	next mediatr.RequestHandlerFunc,
+) (interface{}, error) {
+	logger := b.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	start := time.Now()
+	response, err := next(ctx)
+	duration := time.Since(start)
+
+	if err != nil {
+		logger.LogAttrs(
+			ctx,
+			slog.LevelWarn,
+			"Request failed",
+			slog.String("request", RequestName(request)),
+			slog.Duration("duration", duration),
+			slog.String("outcome", "error"),
+			slog.String("error", err.Error()),
+		)
+		return response, err
+	}
+
+	logger.LogAttrs(
+		ctx,
+		slog.LevelInfo,
+		"Request handled",
+		slog.String("request", RequestName(request)),
+		slog.Duration("duration", duration),
+		slog.String("outcome", "success"),
+	)
+	return response, nil
+}
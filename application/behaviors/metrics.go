@@ -0,0 +1,121 @@
+package behaviors
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mehdihadeli/go-mediatr"
+)
+
+// MetricsRecorder records the outcome of handled requests
+type MetricsRecorder interface {
+	ObserveRequest(request string, duration time.Duration, err error)
+}
+
+// MetricsBehavior records the latency and errors of every request
+type MetricsBehavior struct {
+	recorders []MetricsRecorder
+}
+
+// NewMetricsBehavior creates a new metrics behavior reporting to every recorder
+func NewMetricsBehavior(recorders ...MetricsRecorder) mediatr.PipelineBehavior {
+	return &MetricsBehavior{recorders: recorders}
+}
+
+// Handle calls the next handler and records its latency and outcome
+func (b *MetricsBehavior) Handle(
+	ctx context.Context,
+	request interface{},
+	next mediatr.RequestHandlerFunc,
+) (interface{}, error) {
+	start := time.Now()
+	response, err := next(ctx)
+	duration := time.Since(start)
+	for _, recorder := range b.recorders {
+		recorder.ObserveRequest(RequestName(request), duration, err)
+	}
+	return response, err
+}
+
+// RequestStats summarizes the requests of one type
+type RequestStats struct {
+	Request       string  `json:"request" example:"CreateUserCommand"`
+	Count         int64   `json:"count" example:"42"`
+	Errors        int64   `json:"errors" example:"3"`
+	AvgDurationMs float64 `json:"avgDurationMs" example:"12.5"`
+	MaxDurationMs float64 `json:"maxDurationMs" example:"80.1"`
+}
+
+// RequestMetrics implements MetricsRecorder interface by aggregating
+// per-request-type counters in memory
+type RequestMetrics struct {
+	mu    sync.Mutex
+	stats map[string]*requestCounters
+}
+
+// requestCounters are the raw counters of one request type
+type requestCounters struct {
+	count, errors      int64
+	total, maxDuration time.Duration
+}
+
+// NewRequestMetrics creates a new in-memory request metrics recorder
+func NewRequestMetrics() *RequestMetrics {
+	return &RequestMetrics{stats: make(map[string]*requestCounters)}
+}
+
+// ObserveRequest records one handled request
+func (m *RequestMetrics) ObserveRequest(
+	request string,
+	duration time.Duration,
+	err error,
+) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counters, ok := m.stats[request]
+	if !ok {
+		counters = &requestCounters{}
+		m.stats[request] = counters
+	}
+	counters.count++
+	counters.total += duration
+	if duration > counters.maxDuration {
+		counters.maxDuration = duration
+	}
+	if err != nil {
+		counters.errors++
+	}
+}
+
+// Snapshot returns the current statistics, sorted by request type
+func (m *RequestMetrics) Snapshot() []RequestStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := make([]RequestStats, 0, len(m.stats))
+	for request, counters := range m.stats {
+		snapshot = append(
+			snapshot, RequestStats{
+				Request:       request,
+				Count:         counters.count,
+				Errors:        counters.errors,
+				AvgDurationMs: milliseconds(counters.total) / float64(counters.count),
+				MaxDurationMs: milliseconds(counters.maxDuration),
+			},
+		)
+	}
+	sort.Slice(
+		snapshot, func(i, j int) bool {
+			return snapshot[i].Request < snapshot[j].Request
+		},
+	)
+	return snapshot
+}
+
+// milliseconds converts a duration to fractional milliseconds
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
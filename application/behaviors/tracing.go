@@ -0,0 +1,42 @@
+package behaviors
+
+import (
+	"context"
+
+	"github.com/EngenMe/go-clean-architecture/infrastructure/tracing"
+	"github.com/mehdihadeli/go-mediatr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracingBehavior records every request as a span, child of the span of
+// the request context, e.g. the server span of an HTTP request
+type TracingBehavior struct{}
+
+// NewTracingBehavior creates a new tracing behavior
+func NewTracingBehavior() mediatr.PipelineBehavior {
+	return &TracingBehavior{}
+}
+
+// Handle calls the next handler within a span named after the request type
+func (b *TracingBehavior) Handle(
+	ctx context.Context,
+	request interface{},
+	next mediatr.RequestHandlerFunc,
+) (interface{}, error) {
+	name := RequestName(request)
+	ctx, span := tracing.Tracer().Start(
+		ctx,
+		"mediatr "+name,
+		trace.WithAttributes(attribute.String("mediatr.request", name)),
+	)
+	defer span.End()
+
+	response, err := next(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return response, err
+}
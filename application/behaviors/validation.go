@@ -0,0 +1,64 @@
+package behaviors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/mehdihadeli/go-mediatr"
+)
+
+// Validatable is implemented by requests with rules that struct tags cannot
+// express. Validate runs after the struct tags passed.
+type Validatable interface {
+	Validate() error
+}
+
+// ValidationBehavior rejects invalid requests before they reach their
+// handler, so that every caller (HTTP, CLI, workers) gets the same checks.
+// Requests are validated with their `binding` struct tags, the same rules
+// Gin applies to request bodies, then with their Validate method if any.
+type ValidationBehavior struct {
+	validate *validator.Validate
+}
+
+// NewValidationBehavior creates a new validation behavior
+func NewValidationBehavior() mediatr.PipelineBehavior {
+	validate := validator.New()
+	validate.SetTagName("binding")
+	// Report fields by their JSON name, as clients know them
+	validate.RegisterTagNameFunc(utils.JSONFieldName)
+	return &ValidationBehavior{validate: validate}
+}
+
+// Handle validates the request and calls the next handler if it is valid
+func (b *ValidationBehavior) Handle(
+	ctx context.Context,
+	request interface{},
+	next mediatr.RequestHandlerFunc,
+) (interface{}, error) {
+	if err := b.validate.Struct(request); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return nil, utils.NewValidationError(validationErrors)
+		}
+		// Requests that are not structs carry no tags to check
+		var invalid *validator.InvalidValidationError
+		if !errors.As(err, &invalid) {
+			return nil, err
+		}
+	}
+
+	if validatable, ok := request.(Validatable); ok {
+		if err := validatable.Validate(); err != nil {
+			if !errors.Is(err, utils.ErrInvalidInput) {
+				err = fmt.Errorf("%w: %w", utils.ErrInvalidInput, err)
+			}
+			return nil, err
+		}
+	}
+
+	return next(ctx)
+}
@@ -3,26 +3,59 @@ package commands
 import (
 	"context"
 	"fmt"
-	"time"
 
+	"github.com/EngenMe/go-clean-architecture/application/mediator"
 	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/domain/events"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/i18n"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/random"
 	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
 	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
-	"github.com/mehdihadeli/go-mediatr"
-	"golang.org/x/crypto/bcrypt"
 )
 
 // CreateUserCommand is a command to create a new user
 type CreateUserCommand struct {
-	Email     string `json:"email" binding:"required,email" example:"user@example.com"`
-	Password  string `json:"password" binding:"required,min=6" example:"password123"`
-	FirstName string `json:"firstName" binding:"required" example:"John"`
-	LastName  string `json:"lastName" binding:"required" example:"Doe"`
+	Email     string `json:"email" binding:"required" format:"email" maxLength:"254" example:"user@example.com"`
+	Password  string `json:"password" binding:"required" minLength:"6" maxLength:"72" example:"password123"`
+	FirstName string `json:"firstName" binding:"required" maxLength:"100" example:"John"`
+	LastName  string `json:"lastName" binding:"required" maxLength:"100" example:"Doe"`
+	// PreferredLocale defaults to English
+	PreferredLocale string `json:"preferredLocale,omitempty" binding:"omitempty,oneof=en fr ar" example:"fr"`
+	// Role is only set by trusted callers such as the management CLI;
+	// it is never bound from request bodies and defaults to "user"
+	Role entities.Role `json:"-" swaggerignore:"true"`
+}
+
+// Validate checks the user fields against the rules of the domain, and the
+// role, which only trusted callers can set
+func (c CreateUserCommand) Validate() error {
+	if _, err := c.fields().parse(); err != nil {
+		return err
+	}
+	if c.Role != "" && !c.Role.IsValid() {
+		return fmt.Errorf("%w: unknown role %q", utils.ErrInvalidInput, c.Role)
+	}
+	return nil
+}
+
+// fields returns the user fields of the command, which are all set
+func (c CreateUserCommand) fields() userFields {
+	return userFields{
+		Email:     &c.Email,
+		Password:  &c.Password,
+		FirstName: &c.FirstName,
+		LastName:  &c.LastName,
+	}
 }
 
 // CreateUserHandler handle creation of new users
 type CreateUserHandler struct {
 	UserRepository repositories.UserRepository
+	UnitOfWork     repositories.UnitOfWork
+	Outbox         repositories.OutboxRepository
+	Clock          clock.Clock
+	Random         random.Source // Source of the event IDs
 }
 
 // Handle processes the create user command
@@ -30,47 +63,85 @@ func (h *CreateUserHandler) Handle(
 	ctx context.Context,
 	command CreateUserCommand,
 ) (*entities.UserDTO, error) {
-	// Check if user already exists
-	existingUser, err := h.UserRepository.GetByEmail(ctx, command.Email)
+	values, err := command.fields().parse()
 	if err != nil {
 		return nil, err
 	}
-	if existingUser != nil {
-		return nil, utils.ErrEmailAlreadyExists
-	}
 
 	// Hash the password
-	hashedPassword, err := bcrypt.GenerateFromPassword(
-		[]byte(command.Password),
-		bcrypt.DefaultCost,
-	)
+	hashedPassword, err := utils.HashPassword(ctx, *values.Password)
 	if err != nil {
 		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
 
 	// Create the user
-	user := &entities.User{
-		Email:     command.Email,
-		Password:  string(hashedPassword),
-		FirstName: command.FirstName,
-		LastName:  command.LastName,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+	user, err := entities.NewUser(
+		*values.Email,
+		hashedPassword,
+		*values.FirstName,
+		*values.LastName,
+		h.Clock.Now(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if command.Role != "" {
+		user.Role = command.Role
 	}
+	user.PreferredLocale = command.PreferredLocale
+	if user.PreferredLocale == "" {
+		user.PreferredLocale = i18n.DefaultLocale
+	}
+
+	// Store the user and its creation event atomically
+	err = h.UnitOfWork.Do(
+		ctx, func(ctx context.Context) error {
+			// Check if user already exists
+			existingUser, err := h.UserRepository.GetByEmail(ctx, user.Email)
+			if err != nil {
+				return err
+			}
+			if existingUser != nil {
+				return utils.ErrEmailAlreadyExists
+			}
 
-	if err := h.UserRepository.Create(ctx, user); err != nil {
-		return nil, fmt.Errorf("failed to create user: %w", err)
+			if err := h.UserRepository.Create(ctx, user); err != nil {
+				return fmt.Errorf("failed to create user: %w", err)
+			}
+
+			return recordEvent(
+				ctx, h.Outbox, h.Random, events.UserCreated{
+					User:       user.ToDTO(),
+					OccurredAt: user.CreatedAt,
+				},
+			)
+		},
+	)
+	if err != nil {
+		return nil, err
 	}
 
 	userDTO := user.ToDTO()
 	return &userDTO, nil
 }
 
-// RegisterCreateUserHandler registers the creation user command handler
-func RegisterCreateUserHandler(userRepository repositories.UserRepository) error {
-	if err := mediatr.RegisterRequestHandler[CreateUserCommand, *entities.UserDTO](
+// RegisterCreateUserHandler registers the creation user command handler in m
+func RegisterCreateUserHandler(
+	m *mediator.Mediator,
+	userRepository repositories.UserRepository,
+	unitOfWork repositories.UnitOfWork,
+	outbox repositories.OutboxRepository,
+	clk clock.Clock,
+	source random.Source,
+) error {
+	if err := mediator.Register[CreateUserCommand, *entities.UserDTO](
+		m,
 		&CreateUserHandler{
 			UserRepository: userRepository,
+			UnitOfWork:     unitOfWork,
+			Outbox:         outbox,
+			Clock:          clk,
+			Random:         source,
 		},
 	); err != nil {
 		return fmt.Errorf("failed to register CreateUserHandler: %w", err)
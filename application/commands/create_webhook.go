@@ -0,0 +1,135 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/EngenMe/go-clean-architecture/application/mediator"
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/domain/events"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/random"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+)
+
+// CreateWebhookCommand is a command to subscribe a partner endpoint to events
+type CreateWebhookCommand struct {
+	URL        string   `json:"url" binding:"required,url" example:"https://partner.example.com/hooks/users"`
+	EventTypes []string `json:"eventTypes" binding:"required,min=1,dive,required" example:"user.created,user.deleted"`
+	// Secret is the HMAC signing key; a random one is generated when empty
+	Secret string `json:"secret,omitempty" binding:"omitempty,min=16" example:"a-long-shared-secret"`
+	Active *bool  `json:"active,omitempty" example:"true"`
+}
+
+// Validate checks the endpoint URL and the event types
+func (c CreateWebhookCommand) Validate() error {
+	return validateWebhook(c.URL, c.EventTypes)
+}
+
+// CreateWebhookHandler handles creation of webhook subscriptions
+type CreateWebhookHandler struct {
+	WebhookRepository repositories.GenericRepository[entities.WebhookSubscription]
+	Clock             clock.Clock
+	Random            random.Source // Source of the generated secrets
+}
+
+// Handle processes the create webhook command
+func (h *CreateWebhookHandler) Handle(
+	ctx context.Context,
+	command CreateWebhookCommand,
+) (*entities.WebhookSubscriptionDTO, error) {
+	secret := command.Secret
+	if secret == "" {
+		var err error
+		if secret, err = generateWebhookSecret(h.Random); err != nil {
+			return nil, err
+		}
+	}
+
+	now := h.Clock.Now()
+	subscription := &entities.WebhookSubscription{
+		URL:       command.URL,
+		Secret:    secret,
+		Active:    command.Active == nil || *command.Active,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	subscription.SetEventTypes(uniqueEventTypes(command.EventTypes))
+
+	if err := h.WebhookRepository.Create(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	// The secret is only disclosed once, to the admin who created it
+	subscriptionDTO := subscription.ToDTO()
+	subscriptionDTO.Secret = secret
+	return &subscriptionDTO, nil
+}
+
+// validateWebhook checks that the endpoint is an absolute http(s) URL and
+// that the event types are known types or "*"
+func validateWebhook(endpoint string, eventTypes []string) error {
+	parsed, err := url.Parse(endpoint)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return utils.NewFieldError("url", "http_url", "validation.http_url")
+	}
+
+	for i, eventType := range eventTypes {
+		if eventType != entities.WebhookAllEvents && !events.IsKnownType(eventType) {
+			return utils.NewFieldError(
+				fmt.Sprintf("eventTypes[%d]", i),
+				"event_type",
+				"validation.event_type",
+				"value", eventType,
+				"expected", strings.Join(append(events.Types(), entities.WebhookAllEvents), ", "),
+			)
+		}
+	}
+	return nil
+}
+
+// uniqueEventTypes returns the event types without duplicates, in order
+func uniqueEventTypes(eventTypes []string) []string {
+	seen := make(map[string]bool, len(eventTypes))
+	unique := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		if !seen[eventType] {
+			seen[eventType] = true
+			unique = append(unique, eventType)
+		}
+	}
+	return unique
+}
+
+// generateWebhookSecret returns a random 256-bit hex secret
+func generateWebhookSecret(source random.Source) (string, error) {
+	secret, err := random.Hex(source, 32)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return secret, nil
+}
+
+// RegisterCreateWebhookHandler registers the create webhook command handler in m
+func RegisterCreateWebhookHandler(
+	m *mediator.Mediator,
+	webhookRepository repositories.GenericRepository[entities.WebhookSubscription],
+	clk clock.Clock,
+	source random.Source,
+) error {
+	if err := mediator.Register[CreateWebhookCommand, *entities.WebhookSubscriptionDTO](
+		m,
+		&CreateWebhookHandler{
+			WebhookRepository: webhookRepository,
+			Clock:             clk,
+			Random:            source,
+		},
+	); err != nil {
+		return fmt.Errorf("failed to register CreateWebhookHandler: %w", err)
+	}
+
+	return nil
+}
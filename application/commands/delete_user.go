@@ -4,6 +4,10 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/EngenMe/go-clean-architecture/application/mediator"
+	"github.com/EngenMe/go-clean-architecture/domain/events"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/random"
 	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
 	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
 	"github.com/mehdihadeli/go-mediatr"
@@ -17,31 +21,61 @@ type DeleteUserCommand struct {
 // DeleteUserHandler handles deletion of users
 type DeleteUserHandler struct {
 	UserRepository repositories.UserRepository
+	UnitOfWork     repositories.UnitOfWork
+	Outbox         repositories.OutboxRepository
+	Clock          clock.Clock
+	Random         random.Source // Source of the event IDs
 }
 
 // Handle processes the delete user command
 func (h *DeleteUserHandler) Handle(
 	ctx context.Context,
 	command DeleteUserCommand,
-) (error, error) {
-	// Check if user exists
-	user, err := h.UserRepository.GetByID(ctx, command.ID)
-	if err != nil {
-		return nil, err
-	}
-	if user == nil {
-		return nil, utils.ErrNotFound
-	}
+) (mediatr.Unit, error) {
+	err := h.UnitOfWork.Do(
+		ctx, func(ctx context.Context) error {
+			// Check if user exists
+			user, err := h.UserRepository.GetByID(ctx, command.ID)
+			if err != nil {
+				return err
+			}
+			if user == nil {
+				return utils.ErrNotFound
+			}
 
-	err = h.UserRepository.Delete(ctx, command.ID)
-	return nil, err
+			if err := h.UserRepository.Delete(ctx, command.ID); err != nil {
+				return err
+			}
+
+			return recordEvent(
+				ctx, h.Outbox, h.Random, events.UserDeleted{
+					UserID:     user.ID,
+					Email:      user.Email.String(),
+					OccurredAt: h.Clock.Now(),
+				},
+			)
+		},
+	)
+	return mediatr.Unit{}, err
 }
 
-// RegisterDeleteUserHandler registers the delete user command handler
-func RegisterDeleteUserHandler(userRepository repositories.UserRepository) error {
-	if err := mediatr.RegisterRequestHandler[DeleteUserCommand, error](
+// RegisterDeleteUserHandler registers the delete user command handler in m
+func RegisterDeleteUserHandler(
+	m *mediator.Mediator,
+	userRepository repositories.UserRepository,
+	unitOfWork repositories.UnitOfWork,
+	outbox repositories.OutboxRepository,
+	clk clock.Clock,
+	source random.Source,
+) error {
+	if err := mediator.Register[DeleteUserCommand, mediatr.Unit](
+		m,
 		&DeleteUserHandler{
 			UserRepository: userRepository,
+			UnitOfWork:     unitOfWork,
+			Outbox:         outbox,
+			Clock:          clk,
+			Random:         source,
 		},
 	); err != nil {
 		return fmt.Errorf("failed to register DeleteUserHandler: %w", err)
@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/EngenMe/go-clean-architecture/application/mediator"
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+	"github.com/mehdihadeli/go-mediatr"
+)
+
+// DeleteWebhookCommand is a command to delete a webhook subscription and
+// its delivery log
+type DeleteWebhookCommand struct {
+	ID uint `json:"id" binding:"required" example:"1"`
+}
+
+// DeleteWebhookHandler handles deletion of webhook subscriptions
+type DeleteWebhookHandler struct {
+	WebhookRepository repositories.GenericRepository[entities.WebhookSubscription]
+}
+
+// Handle processes the delete webhook command
+func (h *DeleteWebhookHandler) Handle(
+	ctx context.Context,
+	command DeleteWebhookCommand,
+) (mediatr.Unit, error) {
+	subscription, err := h.WebhookRepository.FindByID(ctx, command.ID)
+	if err != nil {
+		return mediatr.Unit{}, err
+	}
+	if subscription == nil {
+		return mediatr.Unit{}, utils.ErrNotFound
+	}
+
+	err = h.WebhookRepository.Delete(ctx, command.ID)
+	return mediatr.Unit{}, err
+}
+
+// RegisterDeleteWebhookHandler registers the delete webhook command handler in m
+func RegisterDeleteWebhookHandler(
+	m *mediator.Mediator,
+	webhookRepository repositories.GenericRepository[entities.WebhookSubscription],
+) error {
+	if err := mediator.Register[DeleteWebhookCommand, mediatr.Unit](
+		m,
+		&DeleteWebhookHandler{
+			WebhookRepository: webhookRepository,
+		},
+	); err != nil {
+		return fmt.Errorf("failed to register DeleteWebhookHandler: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/domain/events"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/random"
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+)
+
+// recordEvent stores a domain event in the outbox. It must be called inside
+// the unit of work of the change it announces, so that both are committed
+// or rolled back together.
+func recordEvent(
+	ctx context.Context,
+	outbox repositories.OutboxRepository,
+	source random.Source,
+	event events.Event,
+) error {
+	envelope, err := events.NewEnvelope(event, source)
+	if err != nil {
+		return err
+	}
+	return outbox.Add(ctx, envelope)
+}
+
+// changedFields lists the names of the user fields that differ between two
+// versions of a user; the password is reported without its value
+func changedFields(before, after userSnapshot) []string {
+	var fields []string
+	if before.Email != after.Email {
+		fields = append(fields, "email")
+	}
+	if before.FirstName != after.FirstName {
+		fields = append(fields, "firstName")
+	}
+	if before.LastName != after.LastName {
+		fields = append(fields, "lastName")
+	}
+	if before.PreferredLocale != after.PreferredLocale {
+		fields = append(fields, "preferredLocale")
+	}
+	if before.Password != after.Password {
+		fields = append(fields, "password")
+	}
+	return fields
+}
+
+// userSnapshot captures the mutable user fields before a change
+type userSnapshot struct {
+	Email           string
+	Password        string
+	FirstName       string
+	LastName        string
+	PreferredLocale string
+}
+
+// snapshotUser captures the mutable fields of a user
+func snapshotUser(user *entities.User) userSnapshot {
+	return userSnapshot{
+		Email:           user.Email.String(),
+		Password:        user.Password.Hash(),
+		FirstName:       user.FirstName.String(),
+		LastName:        user.LastName.String(),
+		PreferredLocale: user.PreferredLocale,
+	}
+}
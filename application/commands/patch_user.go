@@ -0,0 +1,175 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/EngenMe/go-clean-architecture/application/mediator"
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/domain/events"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/random"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+)
+
+// PatchUserCommand is a command to partially update an existing user.
+// Only the fields that are set (non-nil) are validated and changed.
+type PatchUserCommand struct {
+	ID              uint    `json:"-"`
+	Email           *string `json:"email,omitempty" format:"email" maxLength:"254" example:"user@example.com"`
+	Password        *string `json:"password,omitempty" minLength:"6" maxLength:"72" example:"newpassword123"`
+	FirstName       *string `json:"firstName,omitempty" maxLength:"100" example:"John"`
+	LastName        *string `json:"lastName,omitempty" maxLength:"100" example:"Doe"`
+	PreferredLocale *string `json:"preferredLocale,omitempty" binding:"omitempty,oneof=en fr ar" example:"fr"`
+}
+
+// IsEmpty reports whether the command carries no changes
+func (c PatchUserCommand) IsEmpty() bool {
+	return c.Email == nil && c.Password == nil && c.FirstName == nil && c.LastName == nil &&
+		c.PreferredLocale == nil
+}
+
+// Validate checks the set user fields against the rules of the domain
+func (c PatchUserCommand) Validate() error {
+	_, err := c.fields().parse()
+	return err
+}
+
+// fields returns the user fields of the command
+func (c PatchUserCommand) fields() userFields {
+	return userFields{
+		Email:     c.Email,
+		Password:  c.Password,
+		FirstName: c.FirstName,
+		LastName:  c.LastName,
+	}
+}
+
+// PatchUserHandler handles partial updates of users
+type PatchUserHandler struct {
+	UserRepository repositories.UserRepository
+	UnitOfWork     repositories.UnitOfWork
+	Outbox         repositories.OutboxRepository
+	Clock          clock.Clock
+	Random         random.Source // Source of the event IDs
+}
+
+// Handle processes the patch user command
+func (h *PatchUserHandler) Handle(
+	ctx context.Context,
+	command PatchUserCommand,
+) (*entities.UserDTO, error) {
+	values, err := command.fields().parse()
+	if err != nil {
+		return nil, err
+	}
+
+	// Hash the new password, if provided, before opening the transaction
+	var hashedPassword entities.HashedPassword
+	if values.Password != nil {
+		hashedPassword, err = utils.HashPassword(ctx, *values.Password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash password: %w", err)
+		}
+	}
+
+	var user *entities.User
+	err = h.UnitOfWork.Do(
+		ctx, func(ctx context.Context) error {
+			// Check if user exists
+			var err error
+			user, err = h.UserRepository.GetByID(ctx, command.ID)
+			if err != nil {
+				return err
+			}
+			if user == nil {
+				return utils.ErrNotFound
+			}
+
+			if command.IsEmpty() {
+				return nil
+			}
+
+			before := snapshotUser(user)
+
+			// Check if email has changed and is already taken by someone else
+			if values.Email != nil && *values.Email != user.Email {
+				existingUser, err := h.UserRepository.GetByEmail(ctx, *values.Email)
+				if err != nil {
+					return err
+				}
+				if existingUser != nil && existingUser.ID != command.ID {
+					return utils.ErrEmailAlreadyExists
+				}
+				if err := user.ChangeEmail(*values.Email); err != nil {
+					return err
+				}
+			}
+
+			firstName, lastName := user.FirstName, user.LastName
+			if values.FirstName != nil {
+				firstName = *values.FirstName
+			}
+			if values.LastName != nil {
+				lastName = *values.LastName
+			}
+			if err := user.Rename(firstName, lastName); err != nil {
+				return err
+			}
+			if command.PreferredLocale != nil {
+				user.PreferredLocale = *command.PreferredLocale
+			}
+			if !hashedPassword.IsZero() {
+				if err := user.ChangePassword(hashedPassword); err != nil {
+					return err
+				}
+			}
+
+			user.UpdatedAt = h.Clock.Now()
+
+			if err := h.UserRepository.Update(ctx, user); err != nil {
+				return err
+			}
+
+			return recordEvent(
+				ctx, h.Outbox, h.Random, events.UserUpdated{
+					User:          user.ToDTO(),
+					ChangedFields: changedFields(before, snapshotUser(user)),
+					OccurredAt:    user.UpdatedAt,
+				},
+			)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	userDTO := user.ToDTO()
+	return &userDTO, nil
+}
+
+// RegisterPatchUserHandler registers the patch user command handler in m
+func RegisterPatchUserHandler(
+	m *mediator.Mediator,
+	userRepository repositories.UserRepository,
+	unitOfWork repositories.UnitOfWork,
+	outbox repositories.OutboxRepository,
+	clk clock.Clock,
+	source random.Source,
+) error {
+	if err := mediator.Register[PatchUserCommand, *entities.UserDTO](
+		m,
+		&PatchUserHandler{
+			UserRepository: userRepository,
+			UnitOfWork:     unitOfWork,
+			Outbox:         outbox,
+			Clock:          clk,
+			Random:         source,
+		},
+	); err != nil {
+		return fmt.Errorf("failed to register PatchUserHandler: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/EngenMe/go-clean-architecture/application/mediator"
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+)
+
+// ReplayWebhookDeliveryCommand is a command to send a recorded webhook
+// delivery again, e.g. after the partner fixed their endpoint
+type ReplayWebhookDeliveryCommand struct {
+	WebhookID  uint `json:"webhookId" binding:"required" example:"1"`
+	DeliveryID uint `json:"deliveryId" binding:"required" example:"1"`
+}
+
+// ReplayWebhookDeliveryHandler handles replaying of webhook deliveries
+type ReplayWebhookDeliveryHandler struct {
+	DeliveryRepository repositories.WebhookDeliveryRepository
+	Clock              clock.Clock
+}
+
+// Handle processes the replay webhook delivery command
+func (h *ReplayWebhookDeliveryHandler) Handle(
+	ctx context.Context,
+	command ReplayWebhookDeliveryCommand,
+) (*entities.WebhookDelivery, error) {
+	delivery, err := h.DeliveryRepository.FindByID(ctx, command.DeliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil || delivery.SubscriptionID != command.WebhookID {
+		return nil, utils.ErrNotFound
+	}
+
+	if err := h.DeliveryRepository.Replay(ctx, delivery.ID, h.Clock.Now()); err != nil {
+		return nil, err
+	}
+
+	return h.DeliveryRepository.FindByID(ctx, delivery.ID)
+}
+
+// RegisterReplayWebhookDeliveryHandler registers the replay webhook delivery command handler in m
+func RegisterReplayWebhookDeliveryHandler(
+	m *mediator.Mediator,
+	deliveryRepository repositories.WebhookDeliveryRepository,
+	clk clock.Clock,
+) error {
+	if err := mediator.Register[ReplayWebhookDeliveryCommand, *entities.WebhookDelivery](
+		m,
+		&ReplayWebhookDeliveryHandler{
+			DeliveryRepository: deliveryRepository,
+			Clock:              clk,
+		},
+	); err != nil {
+		return fmt.Errorf("failed to register ReplayWebhookDeliveryHandler: %w", err)
+	}
+
+	return nil
+}
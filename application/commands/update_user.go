@@ -3,27 +3,55 @@ package commands
 import (
 	"context"
 	"fmt"
-	"time"
 
+	"github.com/EngenMe/go-clean-architecture/application/mediator"
 	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/domain/events"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/random"
 	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
 	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
-	"github.com/mehdihadeli/go-mediatr"
-	"golang.org/x/crypto/bcrypt"
 )
 
 // UpdateUserCommand is a command to update an existing user
 type UpdateUserCommand struct {
-	ID        uint   `json:"id" binding:"required" example:"1"`
-	Email     string `json:"email" binding:"required,email" example:"user@example.com"`
-	Password  string `json:"password,omitempty" example:"newpassword123"`
-	FirstName string `json:"firstName" example:"John"`
-	LastName  string `json:"lastName" example:"Doe"`
+	ID    uint   `json:"id" binding:"required" example:"1"`
+	Email string `json:"email" binding:"required" format:"email" maxLength:"254" example:"user@example.com"`
+	// Password is left unchanged when empty
+	Password  string `json:"password,omitempty" minLength:"6" maxLength:"72" example:"newpassword123"`
+	FirstName string `json:"firstName" binding:"required" maxLength:"100" example:"John"`
+	LastName  string `json:"lastName" binding:"required" maxLength:"100" example:"Doe"`
+	// PreferredLocale is left unchanged when empty
+	PreferredLocale string `json:"preferredLocale,omitempty" binding:"omitempty,oneof=en fr ar" example:"fr"`
+}
+
+// Validate checks the user fields against the rules of the domain
+func (c UpdateUserCommand) Validate() error {
+	_, err := c.fields().parse()
+	return err
+}
+
+// fields returns the user fields of the command; the password is only set
+// when it changes
+func (c UpdateUserCommand) fields() userFields {
+	fields := userFields{
+		Email:     &c.Email,
+		FirstName: &c.FirstName,
+		LastName:  &c.LastName,
+	}
+	if c.Password != "" {
+		fields.Password = &c.Password
+	}
+	return fields
 }
 
 // UpdateUserHandler handles updating of users
 type UpdateUserHandler struct {
 	UserRepository repositories.UserRepository
+	UnitOfWork     repositories.UnitOfWork
+	Outbox         repositories.OutboxRepository
+	Clock          clock.Clock
+	Random         random.Source // Source of the event IDs
 }
 
 // Handle processes the update user command
@@ -31,45 +59,79 @@ func (h *UpdateUserHandler) Handle(
 	ctx context.Context,
 	command UpdateUserCommand,
 ) (*entities.UserDTO, error) {
-	// Check if user exists
-	user, err := h.UserRepository.GetByID(ctx, command.ID)
+	values, err := command.fields().parse()
 	if err != nil {
 		return nil, err
 	}
-	if user == nil {
-		return nil, utils.ErrNotFound
-	}
 
-	// Check if email has changed and is already taken by someone else
-	if user.Email != command.Email {
-		existingUser, err := h.UserRepository.GetByEmail(ctx, command.Email)
+	// Hash the new password, if provided, before opening the transaction
+	var hashedPassword entities.HashedPassword
+	if values.Password != nil {
+		hashedPassword, err = utils.HashPassword(ctx, *values.Password)
 		if err != nil {
 			return nil, err
 		}
-		if existingUser != nil && existingUser.ID != command.ID {
-			return nil, utils.ErrConflict
-		}
 	}
 
-	// Update user fields
-	user.Email = command.Email
-	user.FirstName = command.FirstName
-	user.LastName = command.LastName
-	user.UpdatedAt = time.Now()
-
-	// Update password if provided
-	if command.Password != "" {
-		hashedPassword, err := bcrypt.GenerateFromPassword(
-			[]byte(command.Password),
-			bcrypt.DefaultCost,
-		)
-		if err != nil {
-			return nil, err
-		}
-		user.Password = string(hashedPassword)
-	}
+	var user *entities.User
+	err = h.UnitOfWork.Do(
+		ctx, func(ctx context.Context) error {
+			// Check if user exists
+			var err error
+			user, err = h.UserRepository.GetByID(ctx, command.ID)
+			if err != nil {
+				return err
+			}
+			if user == nil {
+				return utils.ErrNotFound
+			}
+
+			// Check if email has changed and is already taken by someone else
+			if user.Email != *values.Email {
+				existingUser, err := h.UserRepository.GetByEmail(ctx, *values.Email)
+				if err != nil {
+					return err
+				}
+				if existingUser != nil && existingUser.ID != command.ID {
+					return utils.ErrEmailAlreadyExists
+				}
+			}
+
+			before := snapshotUser(user)
+
+			// Update user fields
+			if err := user.ChangeEmail(*values.Email); err != nil {
+				return err
+			}
+			if err := user.Rename(*values.FirstName, *values.LastName); err != nil {
+				return err
+			}
+			if command.PreferredLocale != "" {
+				user.PreferredLocale = command.PreferredLocale
+			}
+			user.UpdatedAt = h.Clock.Now()
 
-	if err := h.UserRepository.Update(ctx, user); err != nil {
+			// Update password if provided
+			if !hashedPassword.IsZero() {
+				if err := user.ChangePassword(hashedPassword); err != nil {
+					return err
+				}
+			}
+
+			if err := h.UserRepository.Update(ctx, user); err != nil {
+				return err
+			}
+
+			return recordEvent(
+				ctx, h.Outbox, h.Random, events.UserUpdated{
+					User:          user.ToDTO(),
+					ChangedFields: changedFields(before, snapshotUser(user)),
+					OccurredAt:    user.UpdatedAt,
+				},
+			)
+		},
+	)
+	if err != nil {
 		return nil, err
 	}
 
@@ -77,11 +139,23 @@ func (h *UpdateUserHandler) Handle(
 	return &userDTO, nil
 }
 
-// RegisterUpdateUserHandler registers the update user command handler
-func RegisterUpdateUserHandler(userRepository repositories.UserRepository) error {
-	if err := mediatr.RegisterRequestHandler[UpdateUserCommand, *entities.UserDTO](
+// RegisterUpdateUserHandler registers the update user command handler in m
+func RegisterUpdateUserHandler(
+	m *mediator.Mediator,
+	userRepository repositories.UserRepository,
+	unitOfWork repositories.UnitOfWork,
+	outbox repositories.OutboxRepository,
+	clk clock.Clock,
+	source random.Source,
+) error {
+	if err := mediator.Register[UpdateUserCommand, *entities.UserDTO](
+		m,
 		&UpdateUserHandler{
 			UserRepository: userRepository,
+			UnitOfWork:     unitOfWork,
+			Outbox:         outbox,
+			Clock:          clk,
+			Random:         source,
 		},
 	); err != nil {
 		return fmt.Errorf("failed to register UpdateUserHandler: %w", err)
@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/EngenMe/go-clean-architecture/application/mediator"
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+)
+
+// UpdateWebhookCommand is a command to replace a webhook subscription
+type UpdateWebhookCommand struct {
+	ID         uint     `json:"-"`
+	URL        string   `json:"url" binding:"required,url" example:"https://partner.example.com/hooks/users"`
+	EventTypes []string `json:"eventTypes" binding:"required,min=1,dive,required" example:"user.created,user.deleted"`
+	Active     *bool    `json:"active" binding:"required" example:"true"`
+	// Secret rotates the HMAC signing key when set
+	Secret string `json:"secret,omitempty" binding:"omitempty,min=16" example:"a-new-long-shared-secret"`
+}
+
+// Validate checks the endpoint URL and the event types
+func (c UpdateWebhookCommand) Validate() error {
+	return validateWebhook(c.URL, c.EventTypes)
+}
+
+// UpdateWebhookHandler handles updating of webhook subscriptions
+type UpdateWebhookHandler struct {
+	WebhookRepository repositories.GenericRepository[entities.WebhookSubscription]
+	Clock             clock.Clock
+}
+
+// Handle processes the update webhook command
+func (h *UpdateWebhookHandler) Handle(
+	ctx context.Context,
+	command UpdateWebhookCommand,
+) (*entities.WebhookSubscriptionDTO, error) {
+	subscription, err := h.WebhookRepository.FindByID(ctx, command.ID)
+	if err != nil {
+		return nil, err
+	}
+	if subscription == nil {
+		return nil, utils.ErrNotFound
+	}
+
+	subscription.URL = command.URL
+	subscription.SetEventTypes(uniqueEventTypes(command.EventTypes))
+	subscription.Active = *command.Active
+	if command.Secret != "" {
+		subscription.Secret = command.Secret
+	}
+	subscription.UpdatedAt = h.Clock.Now()
+
+	if err := h.WebhookRepository.Update(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	subscriptionDTO := subscription.ToDTO()
+	return &subscriptionDTO, nil
+}
+
+// RegisterUpdateWebhookHandler registers the update webhook command handler in m
+func RegisterUpdateWebhookHandler(
+	m *mediator.Mediator,
+	webhookRepository repositories.GenericRepository[entities.WebhookSubscription],
+	clk clock.Clock,
+) error {
+	if err := mediator.Register[UpdateWebhookCommand, *entities.WebhookSubscriptionDTO](
+		m,
+		&UpdateWebhookHandler{
+			WebhookRepository: webhookRepository,
+			Clock:             clk,
+		},
+	); err != nil {
+		return fmt.Errorf("failed to register UpdateWebhookHandler: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
+)
+
+// userFields are the user fields a command sets, nil when left unchanged
+type userFields struct {
+	Email     *string
+	Password  *string
+	FirstName *string
+	LastName  *string
+}
+
+// userValues are the values of the user fields a command sets
+type userValues struct {
+	Email     *entities.Email
+	Password  *string // Plain text, checked by entities.ValidatePassword
+	FirstName *entities.PersonName
+	LastName  *entities.PersonName
+}
+
+// parse validates and normalizes the set fields with the rules of the
+// domain, reporting every invalid field at once
+func (f userFields) parse() (userValues, error) {
+	var values userValues
+	var fields utils.FieldErrors
+
+	if f.Email != nil {
+		email, err := entities.NewEmail(*f.Email)
+		fields.Check("email", err)
+		values.Email = &email
+	}
+	if f.Password != nil {
+		fields.Check("password", entities.ValidatePassword(*f.Password))
+		values.Password = f.Password
+	}
+	if f.FirstName != nil {
+		firstName, err := entities.NewPersonName(*f.FirstName)
+		fields.Check("firstName", err)
+		values.FirstName = &firstName
+	}
+	if f.LastName != nil {
+		lastName, err := entities.NewPersonName(*f.LastName)
+		fields.Check("lastName", err)
+		values.LastName = &lastName
+	}
+
+	return values, fields.Err()
+}
@@ -0,0 +1,133 @@
+// Package mediator dispatches commands and queries to their handlers
+// through the pipeline behaviors, and notifications to their subscribers.
+// It uses the handler and behavior interfaces of mediatr, whose registry
+// is global, but each Mediator holds its own handlers so that several
+// applications can coexist in a process, e.g. in tests.
+package mediator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/mehdihadeli/go-mediatr"
+)
+
+// Mediator holds the request handlers and pipeline behaviors of an
+// application
+type Mediator struct {
+	mu          sync.RWMutex
+	handlers    map[reflect.Type]any
+	subscribers map[reflect.Type][]func(ctx context.Context, notification any) error
+	behaviors   []mediatr.PipelineBehavior
+}
+
+// New creates a mediator running behaviors around every request,
+// outermost first
+func New(behaviors ...mediatr.PipelineBehavior) *Mediator {
+	return &Mediator{
+		handlers:    make(map[reflect.Type]any),
+		subscribers: make(map[reflect.Type][]func(ctx context.Context, notification any) error),
+		behaviors:   behaviors,
+	}
+}
+
+// Register registers the handler of TRequest; each request type has a
+// single handler
+func Register[TRequest any, TResponse any](
+	m *Mediator,
+	handler mediatr.RequestHandler[TRequest, TResponse],
+) error {
+	requestType := reflect.TypeFor[TRequest]()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exists := m.handlers[requestType]; exists {
+		return fmt.Errorf("a handler is already registered for %s", requestType)
+	}
+	m.handlers[requestType] = handler
+	return nil
+}
+
+// Registered reports whether a handler is registered for TRequest
+func Registered[TRequest any](m *Mediator) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, exists := m.handlers[reflect.TypeFor[TRequest]()]
+	return exists
+}
+
+// Send runs the behaviors and the handler of request
+func Send[TRequest any, TResponse any](
+	ctx context.Context,
+	m *Mediator,
+	request TRequest,
+) (TResponse, error) {
+	var response TResponse
+
+	m.mu.RLock()
+	registration, exists := m.handlers[reflect.TypeFor[TRequest]()]
+	m.mu.RUnlock()
+	if !exists {
+		return response, fmt.Errorf("no handler for request %T", request)
+	}
+	handler, ok := registration.(mediatr.RequestHandler[TRequest, TResponse])
+	if !ok {
+		return response, fmt.Errorf("handler for request %T has another response type", request)
+	}
+
+	// Chain the behaviors around the handler, the first one outermost
+	next := func(ctx context.Context) (interface{}, error) {
+		return handler.Handle(ctx, request)
+	}
+	for i := len(m.behaviors) - 1; i >= 0; i-- {
+		behavior, inner := m.behaviors[i], next
+		next = func(ctx context.Context) (interface{}, error) {
+			return behavior.Handle(ctx, request, inner)
+		}
+	}
+
+	result, err := next(ctx)
+	if err != nil {
+		return response, err
+	}
+	if result != nil {
+		response = result.(TResponse)
+	}
+	return response, nil
+}
+
+// Subscribe adds a handler of TNotification; a notification type may
+// have several handlers, which are notified in the order they subscribed
+func Subscribe[TNotification any](
+	m *Mediator,
+	handler mediatr.NotificationHandler[TNotification],
+) {
+	notificationType := reflect.TypeFor[TNotification]()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.subscribers[notificationType] = append(
+		m.subscribers[notificationType],
+		func(ctx context.Context, notification any) error {
+			return handler.Handle(ctx, notification.(TNotification))
+		},
+	)
+}
+
+// Publish notifies the handlers subscribed to the dynamic type of
+// notification, stopping at the first error. Notifications without
+// subscribers are dropped; they do not go through the behaviors.
+func (m *Mediator) Publish(ctx context.Context, notification any) error {
+	m.mu.RLock()
+	subscribers := m.subscribers[reflect.TypeOf(notification)]
+	m.mu.RUnlock()
+
+	for _, notify := range subscribers {
+		if err := notify(ctx, notification); err != nil {
+			return err
+		}
+	}
+	return nil
+}
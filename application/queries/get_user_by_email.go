@@ -4,15 +4,21 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/EngenMe/go-clean-architecture/application/mediator"
 	"github.com/EngenMe/go-clean-architecture/domain/entities"
 	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
 	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
-	"github.com/mehdihadeli/go-mediatr"
 )
 
 // GetUserByEmailQuery is a query to get a user by email
 type GetUserByEmailQuery struct {
-	Email string `json:"email" binding:"required,email" example:"user@example.com"`
+	Email string `json:"email" binding:"required" example:"user@example.com"`
+}
+
+// Validate checks the email against the rules of the domain
+func (q GetUserByEmailQuery) Validate() error {
+	_, err := entities.NewEmail(q.Email)
+	return utils.NewRuleFieldError("email", err)
 }
 
 // GetUserByEmailHandler handles retrieving a user by email
@@ -25,7 +31,12 @@ func (h *GetUserByEmailHandler) Handle(
 	ctx context.Context,
 	query GetUserByEmailQuery,
 ) (*entities.UserDTO, error) {
-	user, err := h.UserRepository.GetByEmail(ctx, query.Email)
+	email, err := entities.NewEmail(query.Email)
+	if err != nil {
+		return nil, utils.NewRuleFieldError("email", err)
+	}
+
+	user, err := h.UserRepository.GetByEmail(ctx, email)
 	if err != nil {
 		return nil, err
 	}
@@ -37,9 +48,13 @@ func (h *GetUserByEmailHandler) Handle(
 	return &userDTO, nil
 }
 
-// RegisterGetUserByEmailHandler registers the get user by email query handler
-func RegisterGetUserByEmailHandler(userRepository repositories.UserRepository) error {
-	if err := mediatr.RegisterRequestHandler[GetUserByEmailQuery, *entities.UserDTO](
+// RegisterGetUserByEmailHandler registers the get user by email query handler in m
+func RegisterGetUserByEmailHandler(
+	m *mediator.Mediator,
+	userRepository repositories.UserRepository,
+) error {
+	if err := mediator.Register[GetUserByEmailQuery, *entities.UserDTO](
+		m,
 		&GetUserByEmailHandler{
 			UserRepository: userRepository,
 		},
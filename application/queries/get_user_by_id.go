@@ -4,10 +4,10 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/EngenMe/go-clean-architecture/application/mediator"
 	"github.com/EngenMe/go-clean-architecture/domain/entities"
 	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
 	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
-	"github.com/mehdihadeli/go-mediatr"
 )
 
 // GetUserByIDQuery is a query to get a user by ID
@@ -37,9 +37,13 @@ func (h *GetUserByIDHandler) Handle(
 	return &userDTO, nil
 }
 
-// RegisterGetUserByIDHandler registers the get user by ID query handler
-func RegisterGetUserByIDHandler(userRepository repositories.UserRepository) error {
-	if err := mediatr.RegisterRequestHandler[GetUserByIDQuery, *entities.UserDTO](
+// RegisterGetUserByIDHandler registers the get user by ID query handler in m
+func RegisterGetUserByIDHandler(
+	m *mediator.Mediator,
+	userRepository repositories.UserRepository,
+) error {
+	if err := mediator.Register[GetUserByIDQuery, *entities.UserDTO](
+		m,
 		&GetUserByIDHandler{
 			UserRepository: userRepository,
 		},
@@ -4,9 +4,9 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/EngenMe/go-clean-architecture/application/mediator"
 	"github.com/EngenMe/go-clean-architecture/domain/entities"
 	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
-	"github.com/mehdihadeli/go-mediatr"
 )
 
 // GetUsersQuery is a query to get all users
@@ -36,9 +36,13 @@ func (h *GetUsersHandler) Handle(
 	return userDTOs, nil
 }
 
-// RegisterGetUsersHandler registers the get all users query handler
-func RegisterGetUsersHandler(userRepository repositories.UserRepository) error {
-	if err := mediatr.RegisterRequestHandler[GetUsersQuery, []entities.UserDTO](
+// RegisterGetUsersHandler registers the get all users query handler in m
+func RegisterGetUsersHandler(
+	m *mediator.Mediator,
+	userRepository repositories.UserRepository,
+) error {
+	if err := mediator.Register[GetUsersQuery, []entities.UserDTO](
+		m,
 		&GetUsersHandler{
 			UserRepository: userRepository,
 		},
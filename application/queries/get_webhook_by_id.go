@@ -0,0 +1,55 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/EngenMe/go-clean-architecture/application/mediator"
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+)
+
+// GetWebhookByIDQuery is a query to get a webhook subscription by ID
+type GetWebhookByIDQuery struct {
+	ID uint `json:"id" binding:"required" example:"1"`
+}
+
+// GetWebhookByIDHandler handles retrieving a webhook subscription by ID
+type GetWebhookByIDHandler struct {
+	WebhookRepository repositories.GenericRepository[entities.WebhookSubscription]
+}
+
+// Handle processes the get webhook by ID query
+func (h *GetWebhookByIDHandler) Handle(
+	ctx context.Context,
+	query GetWebhookByIDQuery,
+) (*entities.WebhookSubscriptionDTO, error) {
+	subscription, err := h.WebhookRepository.FindByID(ctx, query.ID)
+	if err != nil {
+		return nil, err
+	}
+	if subscription == nil {
+		return nil, utils.ErrNotFound
+	}
+
+	subscriptionDTO := subscription.ToDTO()
+	return &subscriptionDTO, nil
+}
+
+// RegisterGetWebhookByIDHandler registers the get webhook by ID query handler in m
+func RegisterGetWebhookByIDHandler(
+	m *mediator.Mediator,
+	webhookRepository repositories.GenericRepository[entities.WebhookSubscription],
+) error {
+	if err := mediator.Register[GetWebhookByIDQuery, *entities.WebhookSubscriptionDTO](
+		m,
+		&GetWebhookByIDHandler{
+			WebhookRepository: webhookRepository,
+		},
+	); err != nil {
+		return fmt.Errorf("failed to register GetWebhookByIDHandler: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/EngenMe/go-clean-architecture/application/mediator"
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+)
+
+// GetWebhookDeliveriesQuery is a query to get the delivery log of a webhook
+type GetWebhookDeliveriesQuery struct {
+	WebhookID uint `json:"webhookId" binding:"required" example:"1"`
+	Limit     int  `json:"limit" example:"50"`
+}
+
+// GetWebhookDeliveriesHandler handles retrieving the delivery log of a webhook
+type GetWebhookDeliveriesHandler struct {
+	WebhookRepository  repositories.GenericRepository[entities.WebhookSubscription]
+	DeliveryRepository repositories.WebhookDeliveryRepository
+}
+
+// Handle processes the get webhook deliveries query
+func (h *GetWebhookDeliveriesHandler) Handle(
+	ctx context.Context,
+	query GetWebhookDeliveriesQuery,
+) ([]entities.WebhookDelivery, error) {
+	subscription, err := h.WebhookRepository.FindByID(ctx, query.WebhookID)
+	if err != nil {
+		return nil, err
+	}
+	if subscription == nil {
+		return nil, utils.ErrNotFound
+	}
+
+	limit := query.Limit
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+
+	return h.DeliveryRepository.FindBySubscription(ctx, subscription.ID, limit)
+}
+
+// RegisterGetWebhookDeliveriesHandler registers the get webhook deliveries query handler in m
+func RegisterGetWebhookDeliveriesHandler(
+	m *mediator.Mediator,
+	webhookRepository repositories.GenericRepository[entities.WebhookSubscription],
+	deliveryRepository repositories.WebhookDeliveryRepository,
+) error {
+	if err := mediator.Register[GetWebhookDeliveriesQuery, []entities.WebhookDelivery](
+		m,
+		&GetWebhookDeliveriesHandler{
+			WebhookRepository:  webhookRepository,
+			DeliveryRepository: deliveryRepository,
+		},
+	); err != nil {
+		return fmt.Errorf("failed to register GetWebhookDeliveriesHandler: %w", err)
+	}
+
+	return nil
+}
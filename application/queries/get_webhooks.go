@@ -0,0 +1,53 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/EngenMe/go-clean-architecture/application/mediator"
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+)
+
+// GetWebhooksQuery is a query to get all webhook subscriptions
+type GetWebhooksQuery struct{}
+
+// GetWebhooksHandler handles retrieving all webhook subscriptions
+type GetWebhooksHandler struct {
+	WebhookRepository repositories.GenericRepository[entities.WebhookSubscription]
+}
+
+// Handle processes the get all webhooks query
+func (h *GetWebhooksHandler) Handle(
+	ctx context.Context,
+	_ GetWebhooksQuery,
+) ([]entities.WebhookSubscriptionDTO, error) {
+	subscriptions, err := h.WebhookRepository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptionDTOs := make([]entities.WebhookSubscriptionDTO, len(subscriptions))
+	for i, subscription := range subscriptions {
+		subscriptionDTOs[i] = subscription.ToDTO()
+	}
+
+	return subscriptionDTOs, nil
+}
+
+// RegisterGetWebhooksHandler registers the get all webhooks query handler in m
+func RegisterGetWebhooksHandler(
+	m *mediator.Mediator,
+	webhookRepository repositories.GenericRepository[entities.WebhookSubscription],
+) error {
+	if err := mediator.Register[GetWebhooksQuery, []entities.WebhookSubscriptionDTO](
+		m,
+		&GetWebhooksHandler{
+			WebhookRepository: webhookRepository,
+		},
+	); err != nil {
+		return fmt.Errorf("failed to register GetWebhooksHandler: %w", err)
+	}
+
+	return nil
+}
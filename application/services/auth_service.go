@@ -5,25 +5,26 @@ import (
 	"fmt"
 
 	"github.com/EngenMe/go-clean-architecture/application/commands"
+	"github.com/EngenMe/go-clean-architecture/application/mediator"
 	"github.com/EngenMe/go-clean-architecture/domain/entities"
 	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
 	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
-	"github.com/mehdihadeli/go-mediatr"
-	"golang.org/x/crypto/bcrypt"
 )
 
 // LoginRequest represents login credentials
 type LoginRequest struct {
-	Email    string `json:"email" binding:"required,email" example:"user@example.com"`
+	Email    string `json:"email" binding:"required" format:"email" example:"user@example.com"`
 	Password string `json:"password" binding:"required" example:"password123"`
 }
 
 // SignUpRequest represents sign-up data
 type SignUpRequest struct {
-	Email     string `json:"email" binding:"required,email" example:"user@example.com"`
-	Password  string `json:"password" binding:"required,min=6" example:"password123"`
-	FirstName string `json:"firstName" binding:"required" example:"John"`
-	LastName  string `json:"lastName" binding:"required" example:"Doe"`
+	Email     string `json:"email" binding:"required" format:"email" maxLength:"254" example:"user@example.com"`
+	Password  string `json:"password" binding:"required" minLength:"6" maxLength:"72" example:"password123"`
+	FirstName string `json:"firstName" binding:"required" maxLength:"100" example:"John"`
+	LastName  string `json:"lastName" binding:"required" maxLength:"100" example:"Doe"`
+	// PreferredLocale defaults to the language negotiated from Accept-Language
+	PreferredLocale string `json:"preferredLocale,omitempty" binding:"omitempty,oneof=en fr ar" example:"fr"`
 }
 
 // AuthResponse represents the response to a successful authentication
@@ -32,15 +33,46 @@ type AuthResponse struct {
 	User  entities.UserDTO `json:"user"`
 }
 
+// errInvalidCredentials does not tell whether the email or the password
+// was wrong
+var errInvalidCredentials = utils.NewLocalizedError(utils.ErrUnauthorized, "error.invalid_credentials")
+
+// AuthMetrics counts the outcomes of logins and sign-ups
+type AuthMetrics interface {
+	LoginSucceeded()
+	LoginFailed()
+	SignedUp()
+}
+
+// TokenIssuer issues the access tokens of authenticated users
+type TokenIssuer interface {
+	GenerateToken(user *entities.User) (string, error)
+}
+
 // AuthService provides authentication functionality
 type AuthService struct {
+	mediator       *mediator.Mediator
 	userRepository repositories.UserRepository
+	tokens         TokenIssuer
+	metrics        AuthMetrics
 }
 
-// NewAuthService creates a new authentication service
-func NewAuthService(userRepository repositories.UserRepository) *AuthService {
+// NewAuthService creates a new authentication service sending its
+// commands through m; metrics may be nil
+func NewAuthService(
+	m *mediator.Mediator,
+	userRepository repositories.UserRepository,
+	tokens TokenIssuer,
+	metrics AuthMetrics,
+) *AuthService {
+	if metrics == nil {
+		metrics = nopAuthMetrics{}
+	}
 	return &AuthService{
+		mediator:       m,
 		userRepository: userRepository,
+		tokens:         tokens,
+		metrics:        metrics,
 	}
 }
 
@@ -49,30 +81,33 @@ func (s *AuthService) Login(
 	ctx context.Context,
 	request LoginRequest,
 ) (*AuthResponse, error) {
-	// Find user by email
-	user, err := s.userRepository.GetByEmail(ctx, request.Email)
+	// Find user by email, as normalized when the user signed up
+	email, err := entities.NewEmail(request.Email)
+	if err != nil {
+		return nil, utils.NewRuleFieldError("email", err)
+	}
+	user, err := s.userRepository.GetByEmail(ctx, email)
 	if err != nil {
 		return nil, err
 	}
 	if user == nil {
-		return nil, utils.ErrUnauthorized
+		s.metrics.LoginFailed()
+		return nil, errInvalidCredentials
 	}
 
 	// Verify password
-	err = bcrypt.CompareHashAndPassword(
-		[]byte(user.Password),
-		[]byte(request.Password),
-	)
-	if err != nil {
-		return nil, utils.ErrUnauthorized
+	if err := utils.ComparePassword(ctx, user.Password, request.Password); err != nil {
+		s.metrics.LoginFailed()
+		return nil, errInvalidCredentials
 	}
 
 	// Generate JWT token
-	token, err := utils.GenerateToken(user)
+	token, err := s.tokens.GenerateToken(user)
 	if err != nil {
 		return nil, err
 	}
 
+	s.metrics.LoginSucceeded()
 	return &AuthResponse{
 		Token: token,
 		User:  user.ToDTO(),
@@ -86,29 +121,32 @@ func (s *AuthService) SignUp(
 ) (*AuthResponse, error) {
 	// Create user command
 	command := commands.CreateUserCommand{
-		Email:     request.Email,
-		Password:  request.Password,
-		FirstName: request.FirstName,
-		LastName:  request.LastName,
+		Email:           request.Email,
+		Password:        request.Password,
+		FirstName:       request.FirstName,
+		LastName:        request.LastName,
+		PreferredLocale: request.PreferredLocale,
 	}
 
 	// Execute command via mediatr
-	result, err := mediatr.Send[commands.CreateUserCommand, *entities.UserDTO](
+	result, err := mediator.Send[commands.CreateUserCommand, *entities.UserDTO](
 		ctx,
+		s.mediator,
 		command,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
+	s.metrics.SignedUp()
 
-	// Find the newly created user to get full entity with ID
-	user, err := s.userRepository.GetByEmail(ctx, request.Email)
+	// Find the newly created user to get full entity
+	user, err := s.userRepository.GetByID(ctx, result.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve user: %w", err)
 	}
 
 	// Generate JWT token
-	token, err := utils.GenerateToken(user)
+	token, err := s.tokens.GenerateToken(user)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate token: %w", err)
 	}
@@ -119,7 +157,37 @@ func (s *AuthService) SignUp(
 	}, nil
 }
 
+// IssueToken generates a new JWT token for the user with the given ID,
+// e.g. for a replayed sign-up whose stored response has no token
+func (s *AuthService) IssueToken(ctx context.Context, userID uint) (string, error) {
+	user, err := s.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if user == nil {
+		return "", utils.ErrNotFound
+	}
+
+	token, err := s.tokens.GenerateToken(user)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return token, nil
+}
+
 // RegisterAuthService registers the auth service
-func RegisterAuthService(userRepository repositories.UserRepository) *AuthService {
-	return NewAuthService(userRepository)
+func RegisterAuthService(
+	m *mediator.Mediator,
+	userRepository repositories.UserRepository,
+	tokens TokenIssuer,
+	metrics AuthMetrics,
+) *AuthService {
+	return NewAuthService(m, userRepository, tokens, metrics)
 }
+
+// nopAuthMetrics implements AuthMetrics interface by discarding the counts
+type nopAuthMetrics struct{}
+
+func (nopAuthMetrics) LoginSucceeded() {}
+func (nopAuthMetrics) LoginFailed()    {}
+func (nopAuthMetrics) SignedUp()       {}
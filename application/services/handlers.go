@@ -0,0 +1,41 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/EngenMe/go-clean-architecture/application/behaviors"
+	"github.com/EngenMe/go-clean-architecture/application/commands"
+	"github.com/EngenMe/go-clean-architecture/application/mediator"
+	"github.com/EngenMe/go-clean-architecture/application/queries"
+)
+
+// CheckHandlers returns an error when the handler of a command or query
+// sent by the services is not registered in m
+func CheckHandlers(m *mediator.Mediator) error {
+	return errors.Join(
+		checkHandler[commands.CreateUserCommand](m),
+		checkHandler[commands.UpdateUserCommand](m),
+		checkHandler[commands.PatchUserCommand](m),
+		checkHandler[commands.DeleteUserCommand](m),
+		checkHandler[queries.GetUserByIDQuery](m),
+		checkHandler[queries.GetUserByEmailQuery](m),
+		checkHandler[queries.GetUsersQuery](m),
+		checkHandler[commands.CreateWebhookCommand](m),
+		checkHandler[commands.UpdateWebhookCommand](m),
+		checkHandler[commands.DeleteWebhookCommand](m),
+		checkHandler[commands.ReplayWebhookDeliveryCommand](m),
+		checkHandler[queries.GetWebhookByIDQuery](m),
+		checkHandler[queries.GetWebhooksQuery](m),
+		checkHandler[queries.GetWebhookDeliveriesQuery](m),
+	)
+}
+
+// checkHandler returns an error when no handler of TRequest is registered
+func checkHandler[TRequest any](m *mediator.Mediator) error {
+	if mediator.Registered[TRequest](m) {
+		return nil
+	}
+	var request TRequest
+	return fmt.Errorf("no handler for %s", behaviors.RequestName(request))
+}
@@ -3,12 +3,15 @@ package services
 import (
 	"context"
 	"errors"
-	"log"
 
 	"github.com/EngenMe/go-clean-architecture/application/commands"
+	"github.com/EngenMe/go-clean-architecture/application/mediator"
 	"github.com/EngenMe/go-clean-architecture/application/queries"
 	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
 	"github.com/EngenMe/go-clean-architecture/infrastructure/database"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/memory"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/random"
 	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
 	"github.com/mehdihadeli/go-mediatr"
 	"gorm.io/gorm"
@@ -16,13 +19,14 @@ import (
 
 // UserService provides user-related functionality
 type UserService struct {
-	userRepository repositories.GenericRepository[entities.User]
+	mediator *mediator.Mediator
 }
 
-// NewUserService creates a new user service
-func NewUserService(userRepository repositories.GenericRepository[entities.User]) *UserService {
+// NewUserService creates a new user service sending its commands and
+// queries through m
+func NewUserService(m *mediator.Mediator) *UserService {
 	return &UserService{
-		userRepository: userRepository,
+		mediator: m,
 	}
 }
 
@@ -31,8 +35,9 @@ func (s *UserService) CreateUser(
 	ctx context.Context,
 	command commands.CreateUserCommand,
 ) (*entities.UserDTO, error) {
-	return mediatr.Send[commands.CreateUserCommand, *entities.UserDTO](
+	return mediator.Send[commands.CreateUserCommand, *entities.UserDTO](
 		ctx,
+		s.mediator,
 		command,
 	)
 }
@@ -42,8 +47,9 @@ func (s *UserService) GetUserByID(
 	ctx context.Context,
 	id uint,
 ) (*entities.UserDTO, error) {
-	return mediatr.Send[queries.GetUserByIDQuery, *entities.UserDTO](
+	return mediator.Send[queries.GetUserByIDQuery, *entities.UserDTO](
 		ctx,
+		s.mediator,
 		queries.GetUserByIDQuery{ID: id},
 	)
 }
@@ -53,8 +59,9 @@ func (s *UserService) GetUserByEmail(
 	ctx context.Context,
 	email string,
 ) (*entities.UserDTO, error) {
-	return mediatr.Send[queries.GetUserByEmailQuery, *entities.UserDTO](
+	return mediator.Send[queries.GetUserByEmailQuery, *entities.UserDTO](
 		ctx,
+		s.mediator,
 		queries.GetUserByEmailQuery{Email: email},
 	)
 }
@@ -64,8 +71,9 @@ func (s *UserService) GetAllUsers(ctx context.Context) (
 	[]entities.UserDTO,
 	error,
 ) {
-	return mediatr.Send[queries.GetUsersQuery, []entities.UserDTO](
+	return mediator.Send[queries.GetUsersQuery, []entities.UserDTO](
 		ctx,
+		s.mediator,
 		queries.GetUsersQuery{},
 	)
 }
@@ -75,55 +83,102 @@ func (s *UserService) UpdateUser(
 	ctx context.Context,
 	command commands.UpdateUserCommand,
 ) (*entities.UserDTO, error) {
-	return mediatr.Send[commands.UpdateUserCommand, *entities.UserDTO](
+	return mediator.Send[commands.UpdateUserCommand, *entities.UserDTO](
 		ctx,
+		s.mediator,
+		command,
+	)
+}
+
+// PatchUser partially updates an existing user
+func (s *UserService) PatchUser(
+	ctx context.Context,
+	command commands.PatchUserCommand,
+) (*entities.UserDTO, error) {
+	return mediator.Send[commands.PatchUserCommand, *entities.UserDTO](
+		ctx,
+		s.mediator,
 		command,
 	)
 }
 
 // DeleteUser deletes a user
 func (s *UserService) DeleteUser(ctx context.Context, id uint) error {
-	// Expect (error, error) from the handler
-	resp, err := mediatr.Send[commands.DeleteUserCommand, error](
+	_, err := mediator.Send[commands.DeleteUserCommand, mediatr.Unit](
 		ctx,
+		s.mediator,
 		commands.DeleteUserCommand{ID: id},
 	)
-	// Since resp is the first error (typically nil), return the second error
-	if err != nil {
-		return err
-	}
-	return resp // resp is the first error, which is nil unless the handler sets it
+	return err
 }
 
-// RegisterUserService registers the user service and all its handlers
-func RegisterUserService(userRepository repositories.GenericRepository[entities.User]) *UserService {
+// RegisterUserService registers the handlers of the user service in m
+func RegisterUserService(
+	m *mediator.Mediator,
+	userRepository repositories.GenericRepository[entities.User],
+	unitOfWork repositories.UnitOfWork,
+	outbox repositories.OutboxRepository,
+	clk clock.Clock,
+	source random.Source,
+) (*UserService, error) {
 	// Create custom repository adapter if needed for existing handlers,
 	// This adapter allows existing handlers to use the GenericRepository
 	userRepositoryAdapter := NewUserRepositoryAdapter(userRepository)
 
 	// Register command handlers
-	if err := commands.RegisterCreateUserHandler(userRepositoryAdapter); err != nil {
-		log.Fatalf("Failed to register CreateUserHandler: %v", err)
+	if err := commands.RegisterCreateUserHandler(
+		m,
+		userRepositoryAdapter,
+		unitOfWork,
+		outbox,
+		clk,
+		source,
+	); err != nil {
+		return nil, err
+	}
+	if err := commands.RegisterUpdateUserHandler(
+		m,
+		userRepositoryAdapter,
+		unitOfWork,
+		outbox,
+		clk,
+		source,
+	); err != nil {
+		return nil, err
 	}
-	if err := commands.RegisterUpdateUserHandler(userRepositoryAdapter); err != nil {
-		log.Fatalf("Failed to register UpdateUserHandler: %v", err)
+	if err := commands.RegisterPatchUserHandler(
+		m,
+		userRepositoryAdapter,
+		unitOfWork,
+		outbox,
+		clk,
+		source,
+	); err != nil {
+		return nil, err
 	}
-	if err := commands.RegisterDeleteUserHandler(userRepositoryAdapter); err != nil {
-		log.Fatalf("Failed to register DeleteUserHandler: %v", err)
+	if err := commands.RegisterDeleteUserHandler(
+		m,
+		userRepositoryAdapter,
+		unitOfWork,
+		outbox,
+		clk,
+		source,
+	); err != nil {
+		return nil, err
 	}
 
 	// Register query handlers
-	if err := queries.RegisterGetUserByIDHandler(userRepositoryAdapter); err != nil {
-		log.Fatalf("Failed to register GetUserByIDHandler: %v", err)
+	if err := queries.RegisterGetUserByIDHandler(m, userRepositoryAdapter); err != nil {
+		return nil, err
 	}
-	if err := queries.RegisterGetUserByEmailHandler(userRepositoryAdapter); err != nil {
-		log.Fatalf("Failed to register GetUserByEmailHandler: %v", err)
+	if err := queries.RegisterGetUserByEmailHandler(m, userRepositoryAdapter); err != nil {
+		return nil, err
 	}
-	if err := queries.RegisterGetUsersHandler(userRepositoryAdapter); err != nil {
-		log.Fatalf("Failed to register GetUsersHandler: %v", err)
+	if err := queries.RegisterGetUsersHandler(m, userRepositoryAdapter); err != nil {
+		return nil, err
 	}
 
-	return NewUserService(userRepository)
+	return NewUserService(m), nil
 }
 
 // UserRepositoryAdapter adapts GenericRepository to legacy UserRepository interface
@@ -157,27 +212,35 @@ func (a *UserRepositoryAdapter) GetByID(
 // GetByEmail implements UserRepository.GetByEmail
 func (a *UserRepositoryAdapter) GetByEmail(
 	ctx context.Context,
-	email string,
+	email entities.Email,
 ) (*entities.User, error) {
 	// This special case needs custom implementation as it's not part of the generic repository
-	// Cast to access the underlying db
-	pgRepo, ok := a.genericRepo.(*database.GenericPostgresRepository[entities.User])
-	if !ok {
-		return nil, errors.New("failed to cast repository for GetByEmail operation")
-	}
-
-	var user entities.User
-	result := pgRepo.GetDB().WithContext(ctx).Where(
-		"email = ?",
-		email,
-	).First(&user)
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, nil // No user found
+	// Cast to access the underlying storage
+	switch repo := a.genericRepo.(type) {
+	case *database.GenericPostgresRepository[entities.User]:
+		var user entities.User
+		// Stored emails are lowercased, and LOWER(email) is the expression
+		// of the unique index
+		result := database.Conn(ctx, repo.GetDB()).Where(
+			"LOWER(email) = ?",
+			email.String(),
+		).First(&user)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return nil, nil // No user found
+			}
+			return nil, database.TranslateError(result.Error)
 		}
-		return nil, result.Error
+		return &user, nil
+	case *memory.GenericMemoryRepository[entities.User]:
+		return repo.FindFirst(
+			ctx, func(user *entities.User) bool {
+				return user.Email == email
+			},
+		)
+	default:
+		return nil, errors.New("failed to cast repository for GetByEmail operation")
 	}
-	return &user, nil
 }
 
 // GetAll implements UserRepository.GetAll
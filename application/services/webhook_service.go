@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+
+	"github.com/EngenMe/go-clean-architecture/application/commands"
+	"github.com/EngenMe/go-clean-architecture/application/mediator"
+	"github.com/EngenMe/go-clean-architecture/application/queries"
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/random"
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+	"github.com/mehdihadeli/go-mediatr"
+)
+
+// WebhookService provides webhook subscription management
+type WebhookService struct {
+	mediator *mediator.Mediator
+}
+
+// NewWebhookService creates a new webhook service sending its commands
+// and queries through m
+func NewWebhookService(m *mediator.Mediator) *WebhookService {
+	return &WebhookService{
+		mediator: m,
+	}
+}
+
+// CreateWebhook creates a new webhook subscription
+func (s *WebhookService) CreateWebhook(
+	ctx context.Context,
+	command commands.CreateWebhookCommand,
+) (*entities.WebhookSubscriptionDTO, error) {
+	return mediator.Send[commands.CreateWebhookCommand, *entities.WebhookSubscriptionDTO](
+		ctx,
+		s.mediator,
+		command,
+	)
+}
+
+// GetWebhookByID gets a webhook subscription by ID
+func (s *WebhookService) GetWebhookByID(
+	ctx context.Context,
+	id uint,
+) (*entities.WebhookSubscriptionDTO, error) {
+	return mediator.Send[queries.GetWebhookByIDQuery, *entities.WebhookSubscriptionDTO](
+		ctx,
+		s.mediator,
+		queries.GetWebhookByIDQuery{ID: id},
+	)
+}
+
+// GetAllWebhooks gets all webhook subscriptions
+func (s *WebhookService) GetAllWebhooks(ctx context.Context) (
+	[]entities.WebhookSubscriptionDTO,
+	error,
+) {
+	return mediator.Send[queries.GetWebhooksQuery, []entities.WebhookSubscriptionDTO](
+		ctx,
+		s.mediator,
+		queries.GetWebhooksQuery{},
+	)
+}
+
+// UpdateWebhook replaces a webhook subscription
+func (s *WebhookService) UpdateWebhook(
+	ctx context.Context,
+	command commands.UpdateWebhookCommand,
+) (*entities.WebhookSubscriptionDTO, error) {
+	return mediator.Send[commands.UpdateWebhookCommand, *entities.WebhookSubscriptionDTO](
+		ctx,
+		s.mediator,
+		command,
+	)
+}
+
+// DeleteWebhook deletes a webhook subscription
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id uint) error {
+	_, err := mediator.Send[commands.DeleteWebhookCommand, mediatr.Unit](
+		ctx,
+		s.mediator,
+		commands.DeleteWebhookCommand{ID: id},
+	)
+	return err
+}
+
+// GetWebhookDeliveries gets the latest deliveries of a webhook subscription
+func (s *WebhookService) GetWebhookDeliveries(
+	ctx context.Context,
+	webhookID uint,
+	limit int,
+) ([]entities.WebhookDelivery, error) {
+	return mediator.Send[queries.GetWebhookDeliveriesQuery, []entities.WebhookDelivery](
+		ctx,
+		s.mediator,
+		queries.GetWebhookDeliveriesQuery{WebhookID: webhookID, Limit: limit},
+	)
+}
+
+// ReplayWebhookDelivery schedules a delivery to be sent again
+func (s *WebhookService) ReplayWebhookDelivery(
+	ctx context.Context,
+	webhookID uint,
+	deliveryID uint,
+) (*entities.WebhookDelivery, error) {
+	return mediator.Send[commands.ReplayWebhookDeliveryCommand, *entities.WebhookDelivery](
+		ctx,
+		s.mediator,
+		commands.ReplayWebhookDeliveryCommand{
+			WebhookID:  webhookID,
+			DeliveryID: deliveryID,
+		},
+	)
+}
+
+// RegisterWebhookService registers the handlers of the webhook service in m
+func RegisterWebhookService(
+	m *mediator.Mediator,
+	webhookRepository repositories.GenericRepository[entities.WebhookSubscription],
+	deliveryRepository repositories.WebhookDeliveryRepository,
+	clk clock.Clock,
+	source random.Source,
+) (*WebhookService, error) {
+	// Register command handlers
+	if err := commands.RegisterCreateWebhookHandler(
+		m,
+		webhookRepository,
+		clk,
+		source,
+	); err != nil {
+		return nil, err
+	}
+	if err := commands.RegisterUpdateWebhookHandler(m, webhookRepository, clk); err != nil {
+		return nil, err
+	}
+	if err := commands.RegisterDeleteWebhookHandler(m, webhookRepository); err != nil {
+		return nil, err
+	}
+	if err := commands.RegisterReplayWebhookDeliveryHandler(
+		m,
+		deliveryRepository,
+		clk,
+	); err != nil {
+		return nil, err
+	}
+
+	// Register query handlers
+	if err := queries.RegisterGetWebhooksHandler(m, webhookRepository); err != nil {
+		return nil, err
+	}
+	if err := queries.RegisterGetWebhookByIDHandler(m, webhookRepository); err != nil {
+		return nil, err
+	}
+	if err := queries.RegisterGetWebhookDeliveriesHandler(
+		m,
+		webhookRepository,
+		deliveryRepository,
+	); err != nil {
+		return nil, err
+	}
+
+	return NewWebhookService(m), nil
+}
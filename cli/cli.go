@@ -0,0 +1,128 @@
+// Package cli implements the management command line of the API binary:
+// serving HTTP, running migrations, seeding data and administering users.
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/EngenMe/go-clean-architecture/infrastructure/config"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/logging"
+)
+
+// ErrUsage reports invalid command line usage; the usage has already been printed
+var ErrUsage = errors.New("invalid usage")
+
+// logLevel is the level of the JSON logs, changed when the configuration
+// is reloaded
+var logLevel slog.LevelVar
+
+// command is a CLI subcommand
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, cfg *config.Config, args []string) error
+}
+
+// subcommands lists the available subcommands
+var subcommands = []command{
+	{"serve", "Start the HTTP server (default)", runServe},
+	{"migrate", "Apply, revert or list database migrations (up|down [steps]|status)", runMigrate},
+	{"seed", "Create users from a YAML or JSON fixture file", runSeed},
+	{"user", "Manage users (create|reset-password)", runUser},
+	{"token", "Issue a JWT for a user, for debugging (issue)", runToken},
+	{"config", "Print the effective configuration, with secrets redacted", runConfig},
+}
+
+// Run executes the subcommand named by the first argument that is not a
+// configuration flag, defaulting to serve
+func Run(args []string) error {
+	// Load configuration, logging as JSON until LOG_LEVEL is known
+	logging.Setup(os.Stderr, slog.LevelInfo)
+	cfg, args, err := config.Load(args)
+	if errors.Is(err, flag.ErrHelp) {
+		printUsage(os.Stdout)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	name := "serve"
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
+	}
+	if name == "help" {
+		printUsage(os.Stdout)
+		return nil
+	}
+
+	// Fail fast on invalid settings, before touching any storage
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	if err := setupLogging(cfg); err != nil {
+		return err
+	}
+
+	for _, cmd := range subcommands {
+		if cmd.name == name {
+			err := cmd.run(context.Background(), cfg, args)
+			if errors.Is(err, flag.ErrHelp) {
+				return nil
+			}
+			return err
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", name)
+	printUsage(os.Stderr)
+	return ErrUsage
+}
+
+// setupLogging writes JSON logs to stderr at the configured level
+func setupLogging(cfg *config.Config) error {
+	level, err := logging.ParseLevel(cfg.Log.Level)
+	if err != nil {
+		return fmt.Errorf("invalid LOG_LEVEL: %w", err)
+	}
+	logLevel.Set(level)
+	logging.Setup(os.Stderr, &logLevel)
+	return nil
+}
+
+// printUsage writes the list of subcommands
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: go-clean-architecture [-config file] [-<setting> value]... <command> [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range subcommands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Settings are read from the -config YAML file (or CONFIG_FILE), then from")
+	fmt.Fprintln(w, "environment variables, then from flags named after the variables, e.g.")
+	fmt.Fprintf(w, "-%s for DB_HOST.\n", config.FlagName("DB_HOST"))
+}
+
+// newFlagSet creates a flag set that reports usage errors instead of exiting
+func newFlagSet(name, usage string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s\n", usage)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// usageError prints the usage of a flag set and returns ErrUsage
+func usageError(flags *flag.FlagSet, format string, args ...any) error {
+	fmt.Fprintf(flags.Output(), format+"\n", args...)
+	flags.Usage()
+	return ErrUsage
+}
@@ -0,0 +1,19 @@
+package cli
+
+import (
+	"context"
+	"os"
+
+	"github.com/EngenMe/go-clean-architecture/infrastructure/config"
+)
+
+// runConfig prints the effective configuration as YAML, with its secrets
+// redacted, so that it can be checked or used as a configuration file
+func runConfig(ctx context.Context, cfg *config.Config, args []string) error {
+	flags := newFlagSet("config", "config")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	return cfg.Dump(os.Stdout)
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/config"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/database"
+)
+
+// runMigrate applies, reverts, lists or checks the embedded database
+// migrations
+func runMigrate(ctx context.Context, cfg *config.Config, args []string) error {
+	flags := newFlagSet("migrate", "migrate up | down [steps] | status | check")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return usageError(flags, "Missing migrate action")
+	}
+
+	db, err := database.NewDatabaseConnection(cfg.Database, clock.System)
+	if err != nil {
+		return err
+	}
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	switch action := flags.Arg(0); action {
+	case "up":
+		count, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		slog.Info("Applied migrations", slog.Int("count", count))
+
+	case "down":
+		steps := 1
+		if flags.NArg() > 1 {
+			steps, err = strconv.Atoi(flags.Arg(1))
+			if err != nil || steps < 1 {
+				return usageError(flags, "Invalid number of steps %q", flags.Arg(1))
+			}
+		}
+		count, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		slog.Info("Reverted migrations", slog.Int("count", count))
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%03d_%-40s %s\n", status.Version, status.Name, state)
+		}
+
+	case "check":
+		unchecked, err := migrator.Check(ctx)
+		if err != nil {
+			return err
+		}
+		if len(unchecked) == 0 {
+			slog.Info("Pending migrations can be applied")
+		}
+		for _, migration := range unchecked {
+			slog.Warn(
+				"Migration checked when applied, as earlier migrations are pending",
+				slog.String("migration", fmt.Sprintf("%03d_%s", migration.Version, migration.Name)),
+			)
+		}
+
+	default:
+		return usageError(flags, "Unknown migrate action %q", action)
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// PasswordEnv names the environment variable the user commands read the
+// password from when no -password-file is given
+const PasswordEnv = "USER_PASSWORD"
+
+// readPassword returns the password of the user commands, read from file
+// when it is not empty, else from PasswordEnv, else from stdin: without
+// echo and twice when stdin is a terminal, or as its first line otherwise.
+// Passwords are never taken from flags, which other users can list.
+func readPassword(file string) (string, error) {
+	if file != "" {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("failed to read the password file: %w", err)
+		}
+		return strings.TrimRight(string(content), "\r\n"), nil
+	}
+	if password, ok := os.LookupEnv(PasswordEnv); ok {
+		return password, nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", fmt.Errorf("failed to read the password from stdin: %w", err)
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	password, err := promptPassword(fd, "Password: ")
+	if err != nil {
+		return "", err
+	}
+	confirmation, err := promptPassword(fd, "Confirm password: ")
+	if err != nil {
+		return "", err
+	}
+	if password != confirmation {
+		return "", errors.New("the passwords do not match")
+	}
+	return password, nil
+}
+
+// promptPassword prompts on stderr and reads a line of the terminal without echo
+func promptPassword(fd int, prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	password, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the password: %w", err)
+	}
+	return string(password), nil
+}
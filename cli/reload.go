@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"reflect"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/app"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/config"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/logging"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/ratelimit"
+)
+
+// reloader applies the reloadable settings while the server runs: the log
+// level, the rate limit policies, the CORS origins and the JWT keyring.
+// A configuration that fails to load or validate is rejected as a whole
+// and the running one is kept.
+type reloader struct {
+	app       *app.App
+	current   *config.Config // Reloaded settings over the startup ones
+	lastError string
+	restart   []string // Changed settings waiting for a restart
+}
+
+// run reloads the configuration on SIGHUP and, with a positive watch
+// interval, whenever its sources changed, until ctx is canceled
+func (r *reloader) run(ctx context.Context) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	var watch <-chan time.Time
+	if interval := r.current.Watch.Interval; interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		watch = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+			slog.Info("SIGHUP received, reloading configuration")
+			r.reload(true)
+		case <-watch:
+			r.reload(false)
+		}
+	}
+}
+
+// reload loads the configuration and applies what changed. Explicit
+// reloads report every outcome; watches only report changes, and each
+// distinct error once.
+func (r *reloader) reload(explicit bool) {
+	next, policies, err := r.load()
+	if err != nil {
+		if explicit || err.Error() != r.lastError {
+			slog.Error(
+				"Rejected configuration, keeping the running one",
+				slog.String("error", err.Error()),
+			)
+		}
+		r.lastError = err.Error()
+		return
+	}
+	r.lastError = ""
+
+	reloadable, restart := config.Changes(r.current, next)
+	if r.rateLimited() && !reflect.DeepEqual(policies, r.app.RateLimitPolicies()) &&
+		next.RateLimit.PoliciesFile == r.current.RateLimit.PoliciesFile {
+		reloadable = append(reloadable, "RATE_LIMIT_CONFIG")
+	}
+	reported := slices.Equal(restart, r.restart)
+	r.restart = restart
+	if len(reloadable) == 0 && (len(restart) == 0 || reported && !explicit) {
+		if explicit {
+			slog.Info("Configuration unchanged")
+		}
+		return
+	}
+
+	// Each component is swapped atomically; the level was validated
+	level, _ := logging.ParseLevel(next.Log.Level)
+	logLevel.Set(level)
+	r.current = r.current.Reloaded(next)
+	r.app.Reconfigure(r.current, policies)
+
+	if len(reloadable) > 0 {
+		slog.Info("Configuration reloaded", slog.Any("settings", reloadable))
+	}
+	if len(restart) > 0 {
+		slog.Warn(
+			"Changed settings only apply after a restart",
+			slog.Any("settings", restart),
+		)
+	}
+}
+
+// load loads and validates the configuration and its rate limit policies
+func (r *reloader) load() (*config.Config, []ratelimit.Policy, error) {
+	next, err := r.current.Reload()
+	if err != nil {
+		return nil, nil, err
+	}
+	if !r.rateLimited() {
+		return next, nil, nil
+	}
+	// API keys only change with a restart
+	apiKeys := len(r.app.Config().RateLimit.APIKeys) > 0
+	policies, err := ratelimit.LoadPolicies(next.RateLimit.PoliciesFile, apiKeys)
+	if err != nil {
+		return nil, nil, err
+	}
+	// The user access only changes with a restart too
+	if err := app.CheckPolicies(r.app.Config(), policies); err != nil {
+		return nil, nil, err
+	}
+	return next, policies, nil
+}
+
+// rateLimited reports whether rate limiting was enabled on startup, which
+// a reload cannot change
+func (r *reloader) rateLimited() bool {
+	return r.app.Config().RateLimit.Enabled
+}
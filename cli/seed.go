@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/EngenMe/go-clean-architecture/app"
+	"github.com/EngenMe/go-clean-architecture/application/commands"
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/config"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// seedFixture is the content of a seed file. JSON files are accepted as
+// well, since YAML is a superset of JSON.
+type seedFixture struct {
+	Users []seedUser `yaml:"users" json:"users"`
+}
+
+// seedUser is a user entry of a seed file
+type seedUser struct {
+	Email     string `yaml:"email" json:"email"`
+	Password  string `yaml:"password" json:"password"`
+	FirstName string `yaml:"firstName" json:"firstName"`
+	LastName  string `yaml:"lastName" json:"lastName"`
+	Admin     bool   `yaml:"admin" json:"admin"`
+}
+
+// runSeed creates the users of a fixture file, skipping existing ones
+func runSeed(ctx context.Context, cfg *config.Config, args []string) error {
+	flags := newFlagSet("seed", "seed <file.yaml|file.json>")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return usageError(flags, "Expected exactly one fixture file")
+	}
+
+	content, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("failed to read fixture file: %w", err)
+	}
+
+	var fixture seedFixture
+	if err := yaml.Unmarshal(content, &fixture); err != nil {
+		return fmt.Errorf("failed to parse fixture file: %w", err)
+	}
+
+	a, err := app.New(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	created, skipped := 0, 0
+	for i, user := range fixture.Users {
+		command := commands.CreateUserCommand{
+			Email:     user.Email,
+			Password:  user.Password,
+			FirstName: user.FirstName,
+			LastName:  user.LastName,
+			Role:      entities.RoleUser,
+		}
+		if user.Admin {
+			command.Role = entities.RoleAdmin
+		}
+
+		_, err := a.UserService().CreateUser(ctx, command)
+		switch {
+		case errors.Is(err, utils.ErrEmailAlreadyExists):
+			slog.Info("Skipping existing user", slog.String("email", user.Email))
+			skipped++
+		case err != nil:
+			return fmt.Errorf("failed to seed user #%d (%s): %w", i+1, user.Email, err)
+		default:
+			slog.Info(
+				"Created user",
+				slog.String("email", user.Email),
+				slog.String("role", string(command.Role)),
+			)
+			created++
+		}
+	}
+
+	slog.Info("Seeded users", slog.Int("created", created), slog.Int("skipped", skipped))
+	return nil
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/app"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/config"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/tracing"
+	"github.com/gin-gonic/gin"
+)
+
+// runServe starts the HTTP server and blocks until SIGINT or SIGTERM
+func runServe(ctx context.Context, cfg *config.Config, args []string) error {
+	flags := newFlagSet("serve", "serve [-port port]")
+	port := flags.Int("port", cfg.Server.Port, "HTTP listen port")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	// Set Gin mode based on environment
+	if cfg.Env == "production" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	shutdownTracing, err := tracing.Setup(
+		ctx, tracing.Options{
+			Exporter:    cfg.Tracing.Exporter,
+			Endpoint:    cfg.Tracing.OTLPEndpoint,
+			ServiceName: cfg.Tracing.ServiceName,
+			SampleRatio: cfg.Tracing.SampleRatio,
+			Writer:      os.Stdout,
+		},
+	)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// Flush the spans of the last requests
+		flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			slog.Error("Failed to flush spans", slog.String("error", err.Error()))
+		}
+	}()
+
+	// Requests are logged by the routes' middlewares and Gin's own debug
+	// output goes to the JSON log
+	gin.DebugPrintFunc = func(format string, values ...interface{}) {
+		slog.Debug(strings.TrimSpace(fmt.Sprintf(format, values...)))
+	}
+
+	a, err := app.New(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	// Deliver domain events from the outbox and send webhooks
+	a.Start(ctx)
+
+	srv := &http.Server{
+		Addr:    fmt.Sprintf(":%d", *port),
+		Handler: a.Handler(),
+	}
+
+	// Apply configuration changes until the server stops
+	reloader := &reloader{app: a, current: a.Config()}
+	reloadCtx, stopReloading := context.WithCancel(ctx)
+	defer stopReloading()
+	go reloader.run(reloadCtx)
+
+	// Start server in a goroutine
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("Server is running", slog.Int("port", *port))
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(
+			err,
+			http.ErrServerClosed,
+		) {
+			serverErr <- err
+		}
+	}()
+
+	// Wait for interrupt signal to gracefully shut down
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	select {
+	case err := <-serverErr:
+		return fmt.Errorf("failed to start server: %w", err)
+	case <-quit:
+	}
+	stopReloading()
+
+	// Report unready and keep serving while load balancers notice it and
+	// stop sending new requests
+	a.Drain()
+	drainDelay := cfg.Server.ShutdownDrainDelay
+	slog.Info("Draining traffic", slog.Duration("delay", drainDelay))
+	select {
+	case <-time.After(drainDelay):
+	case <-quit:
+		slog.Warn("Second signal received, skipping the drain delay")
+	}
+	slog.Info("Shutting down server")
+
+	// Let in-flight requests complete, up to the shutdown timeout
+	shutdownCtx, cancel := context.WithTimeout(ctx, cfg.Server.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("server forced to shutdown: %w", err)
+	}
+
+	slog.Info("Server exiting")
+	return nil
+}
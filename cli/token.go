@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/EngenMe/go-clean-architecture/app"
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/config"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
+)
+
+// runToken dispatches the token actions
+func runToken(ctx context.Context, cfg *config.Config, args []string) error {
+	if len(args) == 0 || args[0] != "issue" {
+		fmt.Println("Usage: token issue -email email")
+		return ErrUsage
+	}
+
+	flags := newFlagSet("token issue", "token issue -email email")
+	email := flags.String("email", "", "Email of the user to issue the token for")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *email == "" {
+		return usageError(flags, "Missing -email")
+	}
+
+	address, err := entities.NewEmail(*email)
+	if err != nil {
+		return usageError(flags, "Invalid -email %q", *email)
+	}
+
+	a, err := app.New(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	user, err := a.UserRepository().GetByEmail(ctx, address)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return fmt.Errorf("user %s: %w", *email, utils.ErrNotFound)
+	}
+
+	token, err := a.JWT().GenerateToken(user)
+	if err != nil {
+		return fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	// Print only the token so that it can be captured by scripts
+	fmt.Println(token)
+	return nil
+}
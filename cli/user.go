@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/EngenMe/go-clean-architecture/app"
+	"github.com/EngenMe/go-clean-architecture/application/commands"
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/config"
+)
+
+// runUser dispatches the user management actions
+func runUser(ctx context.Context, cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		fmt.Println("Usage: user create|reset-password [flags]")
+		return ErrUsage
+	}
+
+	switch action := args[0]; action {
+	case "create":
+		return runUserCreate(ctx, cfg, args[1:])
+	case "reset-password":
+		return runUserResetPassword(ctx, cfg, args[1:])
+	default:
+		fmt.Printf("Unknown user action %q\n", action)
+		fmt.Println("Usage: user create|reset-password [flags]")
+		return ErrUsage
+	}
+}
+
+// runUserCreate creates a user, optionally with the admin role
+func runUserCreate(ctx context.Context, cfg *config.Config, args []string) error {
+	flags := newFlagSet(
+		"user create",
+		"user create -email email [-password-file file] -first-name name -last-name name [-admin]",
+	)
+	email := flags.String("email", "", "User email")
+	passwordFile := flags.String("password-file", "", "File holding the password (default $"+PasswordEnv+" or stdin)")
+	firstName := flags.String("first-name", "", "User first name")
+	lastName := flags.String("last-name", "", "User last name")
+	admin := flags.Bool("admin", false, "Grant the admin role")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	password, err := readPassword(*passwordFile)
+	if err != nil {
+		return err
+	}
+
+	command := commands.CreateUserCommand{
+		Email:     *email,
+		Password:  password,
+		FirstName: *firstName,
+		LastName:  *lastName,
+		Role:      entities.RoleUser,
+	}
+	if *admin {
+		command.Role = entities.RoleAdmin
+	}
+
+	a, err := app.New(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	user, err := a.UserService().CreateUser(ctx, command)
+	if err != nil {
+		return err
+	}
+
+	slog.Info(
+		"Created user",
+		slog.Uint64("user_id", uint64(user.ID)),
+		slog.String("email", user.Email),
+		slog.String("role", string(user.Role)),
+	)
+	return nil
+}
+
+// runUserResetPassword sets a new password for the user with the given email
+func runUserResetPassword(ctx context.Context, cfg *config.Config, args []string) error {
+	flags := newFlagSet(
+		"user reset-password",
+		"user reset-password -email email [-password-file file]",
+	)
+	email := flags.String("email", "", "User email")
+	passwordFile := flags.String("password-file", "", "File holding the new password (default $"+PasswordEnv+" or stdin)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *email == "" {
+		return usageError(flags, "Missing -email")
+	}
+	password, err := readPassword(*passwordFile)
+	if err != nil {
+		return err
+	}
+	if password == "" {
+		return usageError(flags, "Missing password")
+	}
+
+	command := commands.PatchUserCommand{Password: &password}
+
+	a, err := app.New(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	user, err := a.UserService().GetUserByEmail(ctx, *email)
+	if err != nil {
+		return err
+	}
+
+	command.ID = user.ID
+	if _, err := a.UserService().PatchUser(ctx, command); err != nil {
+		return err
+	}
+
+	slog.Info(
+		"Password reset",
+		slog.Uint64("user_id", uint64(user.ID)),
+		slog.String("email", user.Email),
+	)
+	return nil
+}
@@ -0,0 +1,27 @@
+package entities
+
+import (
+	"time"
+)
+
+// IdempotencyRecord stores the first response to a request sent with an
+// Idempotency-Key, so that retries get the same response
+type IdempotencyRecord struct {
+	Key          string    `gorm:"column:idempotency_key;primaryKey"` // Scoped key: caller, route and client key
+	RequestHash  string    `gorm:"not null"`                          // Hash of the request payload
+	StatusCode   int       `gorm:"not null;default:0"`                // 0 while the first request is in progress
+	ContentType  string    `gorm:"not null;default:''"`
+	ResponseBody []byte    `gorm:"not null"`
+	CreatedAt    time.Time `gorm:"not null"`
+	ExpiresAt    time.Time `gorm:"index;not null"`
+}
+
+// TableName specifies the table name for the IdempotencyRecord entity
+func (IdempotencyRecord) TableName() string {
+	return "idempotency_keys"
+}
+
+// IsCompleted reports whether the response of the first request is stored
+func (r IdempotencyRecord) IsCompleted() bool {
+	return r.StatusCode != 0
+}
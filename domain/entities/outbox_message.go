@@ -0,0 +1,26 @@
+package entities
+
+import (
+	"time"
+)
+
+// OutboxMessage is a domain event waiting to be delivered to publishers.
+// It is written in the same transaction as the change it announces.
+type OutboxMessage struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	EventID       string     `json:"eventId" gorm:"uniqueIndex;not null"`
+	EventType     string     `json:"eventType" gorm:"not null"`
+	AggregateID   uint       `json:"aggregateId" gorm:"not null"`
+	Payload       string     `json:"payload" gorm:"type:text;not null"`
+	OccurredAt    time.Time  `json:"occurredAt" gorm:"not null"`
+	Attempts      int        `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt time.Time  `json:"nextAttemptAt" gorm:"index;not null"`
+	LastError     string     `json:"lastError,omitempty"`
+	DeliveredAt   *time.Time `json:"deliveredAt,omitempty" gorm:"index"`
+	CreatedAt     time.Time  `json:"createdAt"`
+}
+
+// TableName specifies the table name for the OutboxMessage entity
+func (OutboxMessage) TableName() string {
+	return "outbox_messages"
+}
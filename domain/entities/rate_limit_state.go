@@ -0,0 +1,17 @@
+package entities
+
+import (
+	"time"
+)
+
+// RateLimitState stores the algorithm state of one rate limited client
+type RateLimitState struct {
+	Key       string    `gorm:"column:rate_limit_key;primaryKey"` // Policy and client key
+	State     []byte    `gorm:"not null"`                         // Encoded by the rate limit algorithm
+	ExpiresAt time.Time `gorm:"index;not null"`
+}
+
+// TableName specifies the table name for the RateLimitState entity
+func (RateLimitState) TableName() string {
+	return "rate_limits"
+}
@@ -0,0 +1,19 @@
+package entities
+
+import "strconv"
+
+// RuleError reports a value breaking a rule of the domain. Rule names the
+// broken rule as the validation rules of the API do: "required", "email",
+// "min", "max" or "printable"; Param is the bound of "min" and "max".
+type RuleError struct {
+	Rule  string
+	Param int
+}
+
+// Error names the broken rule, e.g. "max=100"
+func (e *RuleError) Error() string {
+	if e.Rule == "min" || e.Rule == "max" {
+		return "invalid value: " + e.Rule + "=" + strconv.Itoa(e.Param)
+	}
+	return "invalid value: " + e.Rule
+}
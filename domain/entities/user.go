@@ -1,18 +1,92 @@
 package entities
 
 import (
+	"errors"
 	"time"
 )
 
+// Role is the authorization role of a user
+type Role string
+
+// User roles
+const (
+	RoleUser  Role = "user"
+	RoleAdmin Role = "admin"
+)
+
+// IsValid reports whether the role is a known role
+func (r Role) IsValid() bool {
+	return r == RoleUser || r == RoleAdmin
+}
+
 // User represents a user entity in the system
 type User struct {
-	ID        uint      `json:"id" gorm:"primaryKey" example:"1"`
-	Email     string    `json:"email" gorm:"uniqueIndex;not null" example:"user@example.com"`
-	Password  string    `json:"-" gorm:"not null"` // Password is never exposed in JSON responses
-	FirstName string    `json:"firstName" gorm:"not null" example:"John"`
-	LastName  string    `json:"lastName" gorm:"not null" example:"Doe"`
-	CreatedAt time.Time `json:"createdAt" example:"2025-04-27T12:00:00Z"`
-	UpdatedAt time.Time `json:"updatedAt" example:"2025-04-27T12:00:00Z"`
+	ID        uint           `json:"id" gorm:"primaryKey" example:"1"`
+	Email     Email          `json:"email" gorm:"uniqueIndex;not null" example:"user@example.com"`
+	Password  HashedPassword `json:"-" gorm:"not null"` // Password is never exposed in JSON responses
+	FirstName PersonName     `json:"firstName" gorm:"not null" example:"John"`
+	LastName  PersonName     `json:"lastName" gorm:"not null" example:"Doe"`
+	Role      Role           `json:"role" gorm:"not null;default:user" example:"user"`
+	// PreferredLocale is the language of the emails sent to the user
+	PreferredLocale string    `json:"preferredLocale" gorm:"not null;default:en" example:"en"`
+	CreatedAt       time.Time `json:"createdAt" example:"2025-04-27T12:00:00Z"`
+	UpdatedAt       time.Time `json:"updatedAt" example:"2025-04-27T12:00:00Z"`
+}
+
+// errIncompleteUser is returned when a user would lack a required value;
+// values are only missing when a value object was not built by its
+// constructor
+var errIncompleteUser = errors.New("user is missing a required value")
+
+// NewUser creates a user with the user role, which the caller may change
+// along with the preferred locale
+func NewUser(
+	email Email,
+	password HashedPassword,
+	firstName, lastName PersonName,
+	now time.Time,
+) (*User, error) {
+	if email.IsZero() || password.IsZero() || firstName.IsZero() || lastName.IsZero() {
+		return nil, errIncompleteUser
+	}
+	return &User{
+		Email:     email,
+		Password:  password,
+		FirstName: firstName,
+		LastName:  lastName,
+		Role:      RoleUser,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// ChangeEmail changes the email of the user; checking that no other user
+// has it is up to the caller
+func (u *User) ChangeEmail(email Email) error {
+	if email.IsZero() {
+		return errIncompleteUser
+	}
+	u.Email = email
+	return nil
+}
+
+// ChangePassword replaces the password of the user
+func (u *User) ChangePassword(password HashedPassword) error {
+	if password.IsZero() {
+		return errIncompleteUser
+	}
+	u.Password = password
+	return nil
+}
+
+// Rename changes the first and last names of the user
+func (u *User) Rename(firstName, lastName PersonName) error {
+	if firstName.IsZero() || lastName.IsZero() {
+		return errIncompleteUser
+	}
+	u.FirstName = firstName
+	u.LastName = lastName
+	return nil
 }
 
 // GetID returns the ID of the user
@@ -21,10 +95,15 @@ func (u User) GetID() uint {
 }
 
 // SetID sets the ID of the user
-func (u User) SetID(id uint) {
+func (u *User) SetID(id uint) {
 	u.ID = id
 }
 
+// IsAdmin reports whether the user has the admin role
+func (u User) IsAdmin() bool {
+	return u.Role == RoleAdmin
+}
+
 // TableName specifies the table name for the User entity
 func (User) TableName() string {
 	return "users"
@@ -32,22 +111,32 @@ func (User) TableName() string {
 
 // UserDTO is a data transfer object for User entity
 type UserDTO struct {
-	ID        uint      `json:"id" example:"1"`
-	Email     string    `json:"email" example:"user@example.com"`
-	FirstName string    `json:"firstName" example:"John"`
-	LastName  string    `json:"lastName" example:"Doe"`
-	CreatedAt time.Time `json:"createdAt" example:"2025-04-27T12:00:00Z"`
-	UpdatedAt time.Time `json:"updatedAt" example:"2025-04-27T12:00:00Z"`
+	ID              uint      `json:"id" example:"1"`
+	Email           string    `json:"email" example:"user@example.com"`
+	FirstName       string    `json:"firstName" example:"John"`
+	LastName        string    `json:"lastName" example:"Doe"`
+	Role            Role      `json:"role" example:"user"`
+	PreferredLocale string    `json:"preferredLocale" example:"en"`
+	CreatedAt       time.Time `json:"createdAt" example:"2025-04-27T12:00:00Z"`
+	UpdatedAt       time.Time `json:"updatedAt" example:"2025-04-27T12:00:00Z"`
+}
+
+// UserLookupDTO is what a public lookup by email returns: the registered
+// address, without the details of the user
+type UserLookupDTO struct {
+	Email string `json:"email" example:"user@example.com"`
 }
 
 // ToDTO converts a User entity to UserDTO
 func (u User) ToDTO() UserDTO {
 	return UserDTO{
-		ID:        u.ID,
-		Email:     u.Email,
-		FirstName: u.FirstName,
-		LastName:  u.LastName,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
+		ID:              u.ID,
+		Email:           u.Email.String(),
+		FirstName:       u.FirstName.String(),
+		LastName:        u.LastName.String(),
+		Role:            u.Role,
+		PreferredLocale: u.PreferredLocale,
+		CreatedAt:       u.CreatedAt,
+		UpdatedAt:       u.UpdatedAt,
 	}
 }
@@ -0,0 +1,104 @@
+package entities_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+)
+
+// userValues are the values a user is created from
+type userValues struct {
+	email     entities.Email
+	password  entities.HashedPassword
+	firstName entities.PersonName
+	lastName  entities.PersonName
+}
+
+// validUserValues returns values built by their constructors
+func validUserValues(t *testing.T) userValues {
+	t.Helper()
+	email, err := entities.NewEmail("Jane@Example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	password, err := entities.NewHashedPassword("hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	firstName, err := entities.NewPersonName("Jane")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lastName, err := entities.NewPersonName("Doe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return userValues{email, password, firstName, lastName}
+}
+
+func TestNewUser(t *testing.T) {
+	now := time.Date(2030, time.January, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		change  func(v *userValues)
+		wantErr bool
+	}{
+		{"valid", func(*userValues) {}, false},
+		{"no email", func(v *userValues) { v.email = entities.Email{} }, true},
+		{"no password", func(v *userValues) { v.password = entities.HashedPassword{} }, true},
+		{"no first name", func(v *userValues) { v.firstName = entities.PersonName{} }, true},
+		{"no last name", func(v *userValues) { v.lastName = entities.PersonName{} }, true},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				v := validUserValues(t)
+				tt.change(&v)
+				user, err := entities.NewUser(v.email, v.password, v.firstName, v.lastName, now)
+				if tt.wantErr {
+					if err == nil || user != nil {
+						t.Errorf("expected an incomplete user to be refused, got %+v", user)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("failed to create the user: %v", err)
+				}
+				if user.Email.String() != "jane@example.com" ||
+					user.Password.Hash() != "hash" ||
+					user.FirstName.String() != "Jane" ||
+					user.LastName.String() != "Doe" {
+					t.Errorf("expected the given values, got %+v", user)
+				}
+				if user.Role != entities.RoleUser || user.IsAdmin() {
+					t.Errorf("expected the user role, got %q", user.Role)
+				}
+				if user.ID != 0 || !user.CreatedAt.Equal(now) || !user.UpdatedAt.Equal(now) {
+					t.Errorf("expected a new user created at %s, got %+v", now, user)
+				}
+			},
+		)
+	}
+}
+
+func TestUserChangesRefuseMissingValues(t *testing.T) {
+	v := validUserValues(t)
+	user, err := entities.NewUser(v.email, v.password, v.firstName, v.lastName, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := user.ChangeEmail(entities.Email{}); err == nil {
+		t.Error("expected an empty email to be refused")
+	}
+	if err := user.ChangePassword(entities.HashedPassword{}); err == nil {
+		t.Error("expected an empty password to be refused")
+	}
+	if err := user.Rename(v.firstName, entities.PersonName{}); err == nil {
+		t.Error("expected an empty last name to be refused")
+	}
+	if user.Email != v.email || user.Password != v.password || user.LastName != v.lastName {
+		t.Errorf("expected refused changes to leave the user as is, got %+v", user)
+	}
+}
@@ -0,0 +1,226 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"net/mail"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+)
+
+// Bounds of the user values
+const (
+	MaxEmailLength      = 254 // Bytes, the longest address SMTP accepts
+	MaxPersonNameLength = 100 // Characters
+	MinPasswordLength   = 6   // Bytes
+	MaxPasswordLength   = 72  // Bytes, beyond which bcrypt ignores the rest
+)
+
+// Email is a valid email address, trimmed and lowercased so that addresses
+// differing only by case are the same. Internationalized domains are kept
+// in their ASCII (punycode) form, as DNS knows them. The zero Email is no
+// address.
+type Email struct {
+	address string
+}
+
+// NewEmail validates and normalizes an email address
+func NewEmail(raw string) (Email, error) {
+	address := strings.TrimSpace(raw)
+	if address == "" {
+		return Email{}, &RuleError{Rule: "required"}
+	}
+	if !utf8.ValidString(address) {
+		return Email{}, &RuleError{Rule: "email"}
+	}
+	if at := strings.LastIndexByte(address, '@'); at >= 0 && !isASCII(address[at+1:]) {
+		domain, err := idna.Lookup.ToASCII(address[at+1:])
+		if err != nil {
+			return Email{}, &RuleError{Rule: "email"}
+		}
+		address = address[:at+1] + domain
+	}
+	address = strings.ToLower(address)
+	if len(address) > MaxEmailLength {
+		return Email{}, &RuleError{Rule: "max", Param: MaxEmailLength}
+	}
+	// Reject display names and comments: only a bare address is an email
+	parsed, err := mail.ParseAddress(address)
+	if err != nil || parsed.Name != "" || parsed.Address != address {
+		return Email{}, &RuleError{Rule: "email"}
+	}
+	return Email{address: address}, nil
+}
+
+// String returns the address
+func (e Email) String() string {
+	return e.address
+}
+
+// IsZero reports whether e is no address
+func (e Email) IsZero() bool {
+	return e.address == ""
+}
+
+// MarshalText encodes the email as its address
+func (e Email) MarshalText() ([]byte, error) {
+	return []byte(e.address), nil
+}
+
+// UnmarshalText decodes and validates an address
+func (e *Email) UnmarshalText(text []byte) error {
+	email, err := NewEmail(string(text))
+	if err != nil {
+		return err
+	}
+	*e = email
+	return nil
+}
+
+// Scan reads the email from a database column, which only holds addresses
+// that were valid when stored
+func (e *Email) Scan(src any) error {
+	address, err := scanString(src)
+	e.address = address
+	return err
+}
+
+// Value stores the email as its address
+func (e Email) Value() (driver.Value, error) {
+	return e.address, nil
+}
+
+// PersonName is the first or last name of a person: trimmed, non-empty, at
+// most MaxPersonNameLength characters and free of control characters
+type PersonName struct {
+	name string
+}
+
+// NewPersonName validates and trims a name
+func NewPersonName(raw string) (PersonName, error) {
+	name := strings.TrimSpace(raw)
+	if name == "" {
+		return PersonName{}, &RuleError{Rule: "required"}
+	}
+	if utf8.RuneCountInString(name) > MaxPersonNameLength {
+		return PersonName{}, &RuleError{Rule: "max", Param: MaxPersonNameLength}
+	}
+	if !utf8.ValidString(name) || strings.IndexFunc(name, unicode.IsControl) >= 0 {
+		return PersonName{}, &RuleError{Rule: "printable"}
+	}
+	return PersonName{name: name}, nil
+}
+
+// String returns the name
+func (n PersonName) String() string {
+	return n.name
+}
+
+// IsZero reports whether n is no name
+func (n PersonName) IsZero() bool {
+	return n.name == ""
+}
+
+// MarshalText encodes the name as is
+func (n PersonName) MarshalText() ([]byte, error) {
+	return []byte(n.name), nil
+}
+
+// UnmarshalText decodes and validates a name
+func (n *PersonName) UnmarshalText(text []byte) error {
+	name, err := NewPersonName(string(text))
+	if err != nil {
+		return err
+	}
+	*n = name
+	return nil
+}
+
+// Scan reads the name from a database column
+func (n *PersonName) Scan(src any) error {
+	name, err := scanString(src)
+	n.name = name
+	return err
+}
+
+// Value stores the name as is
+func (n PersonName) Value() (driver.Value, error) {
+	return n.name, nil
+}
+
+// ValidatePassword checks that a plain text password can be hashed: it
+// must be MinPasswordLength to MaxPasswordLength bytes long
+func ValidatePassword(plain string) error {
+	switch {
+	case plain == "":
+		return &RuleError{Rule: "required"}
+	case len(plain) < MinPasswordLength:
+		return &RuleError{Rule: "min", Param: MinPasswordLength}
+	case len(plain) > MaxPasswordLength:
+		return &RuleError{Rule: "max", Param: MaxPasswordLength}
+	}
+	return nil
+}
+
+// HashedPassword is the hash of a password; the plain text password is
+// never kept. The zero HashedPassword is no password.
+type HashedPassword struct {
+	hash string
+}
+
+// NewHashedPassword wraps the hash of a password
+func NewHashedPassword(hash string) (HashedPassword, error) {
+	if hash == "" {
+		return HashedPassword{}, &RuleError{Rule: "required"}
+	}
+	return HashedPassword{hash: hash}, nil
+}
+
+// Hash returns the hash
+func (p HashedPassword) Hash() string {
+	return p.hash
+}
+
+// IsZero reports whether p is no password
+func (p HashedPassword) IsZero() bool {
+	return p.hash == ""
+}
+
+// Scan reads the hash from a database column
+func (p *HashedPassword) Scan(src any) error {
+	hash, err := scanString(src)
+	p.hash = hash
+	return err
+}
+
+// Value stores the hash
+func (p HashedPassword) Value() (driver.Value, error) {
+	return p.hash, nil
+}
+
+// isASCII reports whether s only holds ASCII characters
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// scanString reads a text column
+func scanString(src any) (string, error) {
+	switch value := src.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return value, nil
+	case []byte:
+		return string(value), nil
+	default:
+		return "", fmt.Errorf("cannot scan %T into a string", src)
+	}
+}
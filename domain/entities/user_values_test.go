@@ -0,0 +1,168 @@
+package entities_test
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+)
+
+// broken returns the rule err breaks, e.g. "max=100", or "" for no error
+func broken(t *testing.T, err error) string {
+	t.Helper()
+	if err == nil {
+		return ""
+	}
+	var ruleErr *entities.RuleError
+	if !errors.As(err, &ruleErr) {
+		t.Fatalf("expected a rule error, got %v", err)
+	}
+	if ruleErr.Rule == "min" || ruleErr.Rule == "max" {
+		return ruleErr.Rule + "=" + strconv.Itoa(ruleErr.Param)
+	}
+	return ruleErr.Rule
+}
+
+// emailOfLength returns a valid address of n bytes, n being at least 16
+func emailOfLength(n int) string {
+	const domain = "@example.com"
+	local := n - len(domain)
+	return strings.Repeat("a", local) + domain
+}
+
+func TestNewEmail(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string // Normalized address
+		rule string // Broken rule
+	}{
+		{"plain", "jane@example.com", "jane@example.com", ""},
+		{"trimmed", "  jane@example.com\t", "jane@example.com", ""},
+		{"lowercased", "Jane.Doe@Example.COM", "jane.doe@example.com", ""},
+		{"subaddress", "jane+news@example.com", "jane+news@example.com", ""},
+		{"idna domain", "jane@bücher.example", "jane@xn--bcher-kva.example", ""},
+		{"idna uppercase domain", "Jane@BÜCHER.example", "jane@xn--bcher-kva.example", ""},
+		{"punycode domain", "jane@xn--bcher-kva.example", "jane@xn--bcher-kva.example", ""},
+		{"invalid idna domain", "jane@bü cher.example", "", "email"},
+		{"invalid utf-8 domain", "jane@\xffexample.com", "", "email"},
+		{"invalid utf-8 local part", "ja\xffne@example.com", "", "email"},
+		{"longest", emailOfLength(entities.MaxEmailLength), emailOfLength(entities.MaxEmailLength), ""},
+		{"too long", emailOfLength(entities.MaxEmailLength + 1), "", "max=254"},
+		{"empty", "", "", "required"},
+		{"whitespace only", " \t\n", "", "required"},
+		{"no at sign", "jane.example.com", "", "email"},
+		{"no domain", "jane@", "", "email"},
+		{"no local part", "@example.com", "", "email"},
+		{"display name", "Jane <jane@example.com>", "", "email"},
+		{"comment", "jane@example.com (Jane)", "", "email"},
+		{"two addresses", "jane@example.com, john@example.com", "", "email"},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				email, err := entities.NewEmail(tt.raw)
+				if rule := broken(t, err); rule != tt.rule {
+					t.Fatalf("expected rule %q, got %q", tt.rule, rule)
+				}
+				if email.String() != tt.want {
+					t.Errorf("expected %q, got %q", tt.want, email.String())
+				}
+				if email.IsZero() != (tt.want == "") {
+					t.Errorf("expected IsZero to be %t", tt.want == "")
+				}
+			},
+		)
+	}
+}
+
+func TestNewPersonName(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+		rule string
+	}{
+		{"plain", "Jane", "Jane", ""},
+		{"trimmed", "  Jane \t", "Jane", ""},
+		{"inner spaces", "Mary Jane", "Mary Jane", ""},
+		{"accents", "Zoë", "Zoë", ""},
+		{"non-latin", "李小龙", "李小龙", ""},
+		{"longest in characters", strings.Repeat("é", 100), strings.Repeat("é", 100), ""},
+		{"too long", strings.Repeat("a", 101), "", "max=100"},
+		{"too long in characters", strings.Repeat("é", 101), "", "max=100"},
+		{"empty", "", "", "required"},
+		{"spaces only", "   ", "", "required"},
+		{"whitespace only", " \t\r\n", "", "required"},
+		{"unicode spaces only", "  　", "", "required"},
+		{"control character", "Ja\x00ne", "", "printable"},
+		{"inner newline", "Jane\nDoe", "", "printable"},
+		{"invalid utf-8", "Ja\xffne", "", "printable"},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				name, err := entities.NewPersonName(tt.raw)
+				if rule := broken(t, err); rule != tt.rule {
+					t.Fatalf("expected rule %q, got %q", tt.rule, rule)
+				}
+				if name.String() != tt.want {
+					t.Errorf("expected %q, got %q", tt.want, name.String())
+				}
+			},
+		)
+	}
+}
+
+func TestValidatePassword(t *testing.T) {
+	tests := []struct {
+		name  string
+		plain string
+		rule  string
+	}{
+		{"shortest", "secret", ""},
+		{"longest", strings.Repeat("a", 72), ""},
+		{"longest in bytes", strings.Repeat("é", 36), ""},
+		{"spaces count", "      ", ""},
+		{"empty", "", "required"},
+		{"too short", "12345", "min=6"},
+		{"counted in bytes", "ééé", ""},
+		{"too long", strings.Repeat("a", 73), "max=72"},
+		{"too long in bytes", strings.Repeat("é", 37), "max=72"},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				if rule := broken(t, entities.ValidatePassword(tt.plain)); rule != tt.rule {
+					t.Errorf("expected rule %q, got %q", tt.rule, rule)
+				}
+			},
+		)
+	}
+}
+
+func TestNewHashedPassword(t *testing.T) {
+	tests := []struct {
+		name string
+		hash string
+		rule string
+	}{
+		{"hash", "$2a$10$abcdefghijklmnopqrstuv", ""},
+		{"empty", "", "required"},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				password, err := entities.NewHashedPassword(tt.hash)
+				if rule := broken(t, err); rule != tt.rule {
+					t.Fatalf("expected rule %q, got %q", tt.rule, rule)
+				}
+				if password.Hash() != tt.hash || password.IsZero() != (tt.hash == "") {
+					t.Errorf("expected the hash %q, got %q", tt.hash, password.Hash())
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,116 @@
+package entities
+
+import (
+	"strings"
+	"time"
+)
+
+// WebhookAllEvents subscribes a webhook to every event type
+const WebhookAllEvents = "*"
+
+// WebhookSubscription is a partner endpoint notified of domain events
+type WebhookSubscription struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	URL        string    `json:"url" gorm:"not null"`
+	EventTypes string    `json:"eventTypes" gorm:"not null"` // Comma-separated event types
+	Secret     string    `json:"-" gorm:"not null"`          // HMAC key, never exposed after creation
+	Active     bool      `json:"active" gorm:"not null"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// GetID returns the ID of the subscription
+func (s WebhookSubscription) GetID() uint {
+	return s.ID
+}
+
+// SetID sets the ID of the subscription
+func (s *WebhookSubscription) SetID(id uint) {
+	s.ID = id
+}
+
+// TableName specifies the table name for the WebhookSubscription entity
+func (WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// EventTypeList returns the subscribed event types
+func (s WebhookSubscription) EventTypeList() []string {
+	if s.EventTypes == "" {
+		return []string{}
+	}
+	return strings.Split(s.EventTypes, ",")
+}
+
+// SetEventTypes sets the subscribed event types
+func (s *WebhookSubscription) SetEventTypes(eventTypes []string) {
+	s.EventTypes = strings.Join(eventTypes, ",")
+}
+
+// Subscribes reports whether the subscription wants events of the given type
+func (s WebhookSubscription) Subscribes(eventType string) bool {
+	for _, subscribed := range s.EventTypeList() {
+		if subscribed == eventType || subscribed == WebhookAllEvents {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookSubscriptionDTO is a data transfer object for WebhookSubscription entity
+type WebhookSubscriptionDTO struct {
+	ID         uint     `json:"id" example:"1"`
+	URL        string   `json:"url" example:"https://partner.example.com/hooks/users"`
+	EventTypes []string `json:"eventTypes" example:"user.created,user.deleted"`
+	// Secret is only returned when the subscription is created
+	Secret    string    `json:"secret,omitempty" example:"9f86d081884c7d659a2feaa0c55ad015"`
+	Active    bool      `json:"active" example:"true"`
+	CreatedAt time.Time `json:"createdAt" example:"2025-04-27T12:00:00Z"`
+	UpdatedAt time.Time `json:"updatedAt" example:"2025-04-27T12:00:00Z"`
+}
+
+// ToDTO converts a WebhookSubscription entity to WebhookSubscriptionDTO,
+// leaving out the secret
+func (s WebhookSubscription) ToDTO() WebhookSubscriptionDTO {
+	return WebhookSubscriptionDTO{
+		ID:         s.ID,
+		URL:        s.URL,
+		EventTypes: s.EventTypeList(),
+		Active:     s.Active,
+		CreatedAt:  s.CreatedAt,
+		UpdatedAt:  s.UpdatedAt,
+	}
+}
+
+// WebhookDeliveryStatus is the state of a webhook delivery
+type WebhookDeliveryStatus string
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery records the delivery of one event to one subscription,
+// including its retries
+type WebhookDelivery struct {
+	ID             uint                  `json:"id" gorm:"primaryKey" example:"1"`
+	SubscriptionID uint                  `json:"subscriptionId" gorm:"not null" example:"1"`
+	EventID        string                `json:"eventId" gorm:"not null" example:"569ee450510d637b74bf24625d1b4bb1"`
+	EventType      string                `json:"eventType" gorm:"not null" example:"user.created"`
+	Payload        string                `json:"-" gorm:"type:text;not null"`
+	Status         WebhookDeliveryStatus `json:"status" gorm:"not null;default:pending" example:"succeeded"`
+	Attempts       int                   `json:"attempts" gorm:"not null;default:0" example:"1"`
+	NextAttemptAt  time.Time             `json:"nextAttemptAt" gorm:"not null"`
+	ResponseStatus int                   `json:"responseStatus,omitempty" example:"200"`
+	LastError      string                `json:"lastError,omitempty"`
+	DeliveredAt    *time.Time            `json:"deliveredAt,omitempty"`
+	CreatedAt      time.Time             `json:"createdAt" example:"2025-04-27T12:00:00Z"`
+	UpdatedAt      time.Time             `json:"updatedAt" example:"2025-04-27T12:00:00Z"`
+}
+
+// TableName specifies the table name for the WebhookDelivery entity
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
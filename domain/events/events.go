@@ -0,0 +1,95 @@
+// Package events defines the domain events raised by the application layer
+// and their serialized envelope.
+package events
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// Event is a domain event announcing a change that already happened
+type Event interface {
+	// EventType returns the stable name of the event, e.g. "user.created"
+	EventType() string
+	// AggregateID returns the ID of the entity the event is about
+	AggregateID() uint
+	// OccurredOn returns when the change happened
+	OccurredOn() time.Time
+}
+
+// Envelope is the serialized form of an event, as stored in the outbox
+// and handed to publishers
+type Envelope struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	AggregateID uint            `json:"aggregateId"`
+	OccurredAt  time.Time       `json:"occurredAt"`
+	Payload     json.RawMessage `json:"payload"`
+}
+
+// NewEnvelope serializes an event into an envelope with a unique ID, read
+// from source, that consumers can use to discard redeliveries
+func NewEnvelope(event Event, source io.Reader) (Envelope, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return Envelope{}, fmt.Errorf("failed to encode %s event: %w", event.EventType(), err)
+	}
+
+	id := make([]byte, 16)
+	if _, err := io.ReadFull(source, id); err != nil {
+		return Envelope{}, fmt.Errorf("failed to generate event ID: %w", err)
+	}
+
+	return Envelope{
+		ID:          hex.EncodeToString(id),
+		Type:        event.EventType(),
+		AggregateID: event.AggregateID(),
+		OccurredAt:  event.OccurredOn().UTC(),
+		Payload:     payload,
+	}, nil
+}
+
+// decoders maps event types to functions decoding their payload
+var decoders = map[string]func(payload []byte) (Event, error){
+	UserCreatedType: decodeAs[UserCreated],
+	UserUpdatedType: decodeAs[UserUpdated],
+	UserDeletedType: decodeAs[UserDeleted],
+}
+
+// Types returns the names of all known event types, sorted
+func Types() []string {
+	types := make([]string, 0, len(decoders))
+	for eventType := range decoders {
+		types = append(types, eventType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// IsKnownType reports whether eventType names a known event type
+func IsKnownType(eventType string) bool {
+	_, ok := decoders[eventType]
+	return ok
+}
+
+// Decode restores the concrete event carried by an envelope
+func Decode(envelope Envelope) (Event, error) {
+	decode, ok := decoders[envelope.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown event type %q", envelope.Type)
+	}
+	return decode(envelope.Payload)
+}
+
+// decodeAs decodes a payload into the event type T
+func decodeAs[T Event](payload []byte) (Event, error) {
+	var event T
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("failed to decode %s event: %w", event.EventType(), err)
+	}
+	return event, nil
+}
@@ -0,0 +1,61 @@
+package events
+
+import (
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+)
+
+// User event types
+const (
+	UserCreatedType = "user.created"
+	UserUpdatedType = "user.updated"
+	UserDeletedType = "user.deleted"
+)
+
+// UserCreated is raised when a new user has been created
+type UserCreated struct {
+	User       entities.UserDTO `json:"user"`
+	OccurredAt time.Time        `json:"occurredAt"`
+}
+
+// EventType returns the event type name
+func (e UserCreated) EventType() string { return UserCreatedType }
+
+// AggregateID returns the ID of the created user
+func (e UserCreated) AggregateID() uint { return e.User.ID }
+
+// OccurredOn returns when the user was created
+func (e UserCreated) OccurredOn() time.Time { return e.OccurredAt }
+
+// UserUpdated is raised when an existing user has been changed
+type UserUpdated struct {
+	User          entities.UserDTO `json:"user"`
+	ChangedFields []string         `json:"changedFields"`
+	OccurredAt    time.Time        `json:"occurredAt"`
+}
+
+// EventType returns the event type name
+func (e UserUpdated) EventType() string { return UserUpdatedType }
+
+// AggregateID returns the ID of the updated user
+func (e UserUpdated) AggregateID() uint { return e.User.ID }
+
+// OccurredOn returns when the user was updated
+func (e UserUpdated) OccurredOn() time.Time { return e.OccurredAt }
+
+// UserDeleted is raised when a user has been deleted
+type UserDeleted struct {
+	UserID     uint      `json:"userId"`
+	Email      string    `json:"email"`
+	OccurredAt time.Time `json:"occurredAt"`
+}
+
+// EventType returns the event type name
+func (e UserDeleted) EventType() string { return UserDeletedType }
+
+// AggregateID returns the ID of the deleted user
+func (e UserDeleted) AggregateID() uint { return e.UserID }
+
+// OccurredOn returns when the user was deleted
+func (e UserDeleted) OccurredOn() time.Time { return e.OccurredAt }
@@ -0,0 +1,788 @@
+package e2e_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/EngenMe/go-clean-architecture/app"
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/config"
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+)
+
+func TestSignUpAndLogin(t *testing.T) {
+	a := newAPI(t)
+	email := uniqueEmail()
+
+	token, user := a.signUp(email)
+	if token == "" {
+		t.Fatal("expected a token after sign-up")
+	}
+	if user.ID == 0 || user.Email != email || user.Role != entities.RoleUser {
+		t.Fatalf("unexpected user after sign-up: %+v", user)
+	}
+
+	// The sign-up token authenticates the user
+	var fetched entities.UserDTO
+	a.do(
+		request{
+			method: http.MethodGet,
+			path:   fmt.Sprintf("/api/v1/users/%d", user.ID),
+			token:  token,
+		},
+	).expect(t, http.StatusOK, &fetched)
+	if fetched.Email != email {
+		t.Fatalf("expected user %s, got %s", email, fetched.Email)
+	}
+
+	// So does the login token
+	loginToken := a.login(email)
+	a.do(
+		request{
+			method: http.MethodGet,
+			path:   fmt.Sprintf("/api/v1/users/%d", user.ID),
+			token:  loginToken,
+		},
+	).expect(t, http.StatusOK, nil)
+
+	// The same email cannot sign up twice
+	a.do(
+		request{
+			method: http.MethodPost,
+			path:   "/api/v1/auth/signup",
+			body: map[string]string{
+				"email":     email,
+				"password":  password,
+				"firstName": "Jane",
+				"lastName":  "Doe",
+			},
+		},
+	).expectProblem(t, http.StatusConflict, "email_already_exists")
+}
+
+// recordingIdempotencyStore keeps a copy of the responses it stores
+type recordingIdempotencyStore struct {
+	repositories.IdempotencyStore
+	mu     sync.Mutex
+	bodies [][]byte
+}
+
+// Complete records the body and stores it
+func (s *recordingIdempotencyStore) Complete(
+	ctx context.Context,
+	key string,
+	statusCode int,
+	contentType string,
+	body []byte,
+) error {
+	s.mu.Lock()
+	s.bodies = append(s.bodies, append([]byte(nil), body...))
+	s.mu.Unlock()
+	return s.IdempotencyStore.Complete(ctx, key, statusCode, contentType, body)
+}
+
+func TestSignUpRetry(t *testing.T) {
+	storage := app.MemoryStorage(clock.System)
+	store := &recordingIdempotencyStore{IdempotencyStore: storage.Idempotency}
+	storage.Idempotency = store
+	a := newAPI(t, app.WithStorage(storage))
+
+	for _, path := range []string{"/api/v1/auth/signup", "/api/v1/signup"} {
+		signUp := withIdempotencyKey(
+			request{method: http.MethodPost, path: path, body: signUpBody(uniqueEmail())},
+			"sign-up",
+		)
+		var first, retry struct {
+			Token string           `json:"token"`
+			User  entities.UserDTO `json:"user"`
+		}
+		a.do(signUp).expect(t, http.StatusCreated, &first)
+
+		// A retried sign-up gets the same user and a working token
+		replayed := a.do(signUp)
+		replayed.expect(t, http.StatusCreated, &retry)
+		if replayed.header.Get("Idempotent-Replayed") != "true" {
+			t.Errorf("%s: expected the retry to be replayed", path)
+		}
+		if retry.User.ID != first.User.ID || retry.User.Email != first.User.Email || retry.Token == "" {
+			t.Fatalf("%s: expected user %+v with a token, got %+v", path, first.User, retry)
+		}
+		a.do(
+			request{
+				method: http.MethodGet,
+				path:   fmt.Sprintf("/api/v1/users/%d", retry.User.ID),
+				token:  retry.Token,
+			},
+		).expect(t, http.StatusOK, nil)
+
+		// Tokens are not stored
+		store.mu.Lock()
+		for _, body := range store.bodies {
+			if bytes.Contains(body, []byte(first.Token)) {
+				t.Errorf("%s: expected the stored response to hold no token, got %s", path, body)
+			}
+		}
+		store.mu.Unlock()
+	}
+}
+
+func TestLoginFailures(t *testing.T) {
+	a := newAPI(t)
+	email := uniqueEmail()
+	a.signUp(email)
+
+	tests := []struct {
+		name        string
+		credentials map[string]string
+	}{
+		{"wrong password", map[string]string{"email": email, "password": "wrong-password"}},
+		{"unknown email", map[string]string{"email": uniqueEmail(), "password": password}},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				problem := a.do(
+					request{
+						method: http.MethodPost,
+						path:   "/api/v1/auth/login",
+						body:   tt.credentials,
+					},
+				).expectProblem(t, http.StatusUnauthorized, "unauthorized")
+
+				// Both failures look the same, so that emails cannot be probed
+				if problem.Detail != "invalid email or password" {
+					t.Errorf("unexpected detail %q", problem.Detail)
+				}
+			},
+		)
+	}
+}
+
+func TestAuthenticationFailures(t *testing.T) {
+	a := newAPI(t)
+	_, user := a.signUp(uniqueEmail())
+	path := fmt.Sprintf("/api/v1/users/%d", user.ID)
+
+	// A token signed with another secret
+	other := newAPI(
+		t, app.WithConfig(
+			func(cfg *config.Config) {
+				cfg.JWT.Secret = strings.Repeat("o", config.MinSecretLength)
+			},
+		),
+	)
+	otherToken, _ := other.signUp(uniqueEmail())
+
+	tests := []struct {
+		name   string
+		header string
+	}{
+		{"missing token", ""},
+		{"not a bearer token", "Basic dXNlcjpwYXNzd29yZA=="},
+		{"malformed token", "Bearer not-a-jwt"},
+		{"token signed with another secret", "Bearer " + otherToken},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				header := map[string]string{}
+				if tt.header != "" {
+					header["Authorization"] = tt.header
+				}
+				a.do(request{method: http.MethodGet, path: path, header: header}).
+					expectProblem(t, http.StatusUnauthorized, "unauthorized")
+			},
+		)
+	}
+}
+
+func TestAuthorizationFailures(t *testing.T) {
+	a := newAPI(t)
+	token, _ := a.signUp(uniqueEmail())
+
+	// Creating and listing users, looking them up by email, webhooks and
+	// admin metrics are restricted to admins by default
+	tests := []request{
+		{
+			method: http.MethodPost,
+			path:   "/api/v1/users",
+			body: map[string]string{
+				"email":     uniqueEmail(),
+				"password":  password,
+				"firstName": "John",
+				"lastName":  "Doe",
+			},
+		},
+		{method: http.MethodGet, path: "/api/v1/users"},
+		{method: http.MethodGet, path: "/api/v1/users/email/someone@example.com"},
+		{method: http.MethodGet, path: "/api/v1/webhooks"},
+		{method: http.MethodGet, path: "/api/v1/admin/metrics/requests"},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.method+" "+tt.path, func(t *testing.T) {
+				tt.token = token
+				a.do(tt).expectProblem(t, http.StatusForbidden, "forbidden")
+			},
+		)
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	a := newAPI(t)
+
+	tests := []struct {
+		name   string
+		body   any
+		fields []string
+	}{
+		{
+			name: "invalid fields",
+			body: map[string]string{
+				"email":     "not-an-email",
+				"password":  "short",
+				"firstName": "Jane",
+				"lastName":  "Doe",
+			},
+			fields: []string{"email", "password"},
+		},
+		{
+			name:   "missing fields",
+			body:   map[string]string{"email": uniqueEmail(), "password": password},
+			fields: []string{"firstName", "lastName"},
+		},
+		{
+			name: "unsupported locale",
+			body: map[string]string{
+				"email":           uniqueEmail(),
+				"password":        password,
+				"firstName":       "Jane",
+				"lastName":        "Doe",
+				"preferredLocale": "de",
+			},
+			fields: []string{"preferredLocale"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				problem := a.do(
+					request{
+						method: http.MethodPost,
+						path:   "/api/v1/auth/signup",
+						body:   tt.body,
+					},
+				).expectProblem(t, http.StatusBadRequest, "validation_failed")
+
+				fields := make([]string, 0, len(problem.Errors))
+				for _, fieldError := range problem.Errors {
+					fields = append(fields, fieldError.Field)
+				}
+				if strings.Join(fields, ",") != strings.Join(tt.fields, ",") {
+					t.Errorf("expected errors on %v, got %v", tt.fields, fields)
+				}
+			},
+		)
+	}
+
+	t.Run(
+		"malformed JSON", func(t *testing.T) {
+			a.do(
+				request{
+					method: http.MethodPost,
+					path:   "/api/v1/auth/signup",
+					body:   `{"email":`,
+				},
+			).expectProblem(t, http.StatusBadRequest, "bad_request")
+		},
+	)
+}
+
+func TestUserValues(t *testing.T) {
+	a := newAPI(t)
+
+	// Emails are trimmed and lowercased, names trimmed
+	email := uniqueEmail()
+	var auth struct {
+		User entities.UserDTO `json:"user"`
+	}
+	a.do(
+		request{
+			method: http.MethodPost,
+			path:   "/api/v1/auth/signup",
+			body: map[string]string{
+				"email":     "  " + strings.ToUpper(email) + " ",
+				"password":  password,
+				"firstName": " Jane ",
+				"lastName":  "Doe",
+			},
+		},
+	).expect(t, http.StatusCreated, &auth)
+	if auth.User.Email != email || auth.User.FirstName != "Jane" {
+		t.Fatalf("expected normalized values, got %+v", auth.User)
+	}
+	a.login(strings.ToUpper(email))
+
+	// The same rules apply to every command changing a user
+	token := a.login(email)
+	path := fmt.Sprintf("/api/v1/users/%d", auth.User.ID)
+	tests := []struct {
+		name    string
+		request request
+		field   string
+	}{
+		{
+			name: "blank name on update",
+			request: request{
+				method: http.MethodPut,
+				path:   path,
+				body: map[string]any{
+					"id":        auth.User.ID,
+					"email":     email,
+					"firstName": "   ",
+					"lastName":  "Doe",
+				},
+			},
+			field: "firstName",
+		},
+		{
+			name: "control character on patch",
+			request: request{
+				method: http.MethodPatch,
+				path:   path,
+				body:   `{"lastName":"Do\u0007e"}`,
+				header: map[string]string{"Content-Type": "application/merge-patch+json"},
+			},
+			field: "lastName",
+		},
+		{
+			name: "password too long for bcrypt on patch",
+			request: request{
+				method: http.MethodPatch,
+				path:   path,
+				body:   fmt.Sprintf(`{"password":%q}`, strings.Repeat("p", 73)),
+				header: map[string]string{"Content-Type": "application/merge-patch+json"},
+			},
+			field: "password",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				tt.request.token = token
+				problem := a.do(tt.request).expectProblem(t, http.StatusBadRequest, "validation_failed")
+				if len(problem.Errors) != 1 || problem.Errors[0].Field != tt.field {
+					t.Errorf("expected an error on %s, got %+v", tt.field, problem.Errors)
+				}
+			},
+		)
+	}
+}
+
+func TestEmailIdentity(t *testing.T) {
+	a := newAPI(t)
+	adminToken, _ := a.createAdmin()
+	email := uniqueEmail()
+	_, user := a.signUp(email)
+
+	// Emails that only differ by case are the same identity
+	shouted := strings.ToUpper(email)
+	a.do(
+		request{
+			method: http.MethodPost,
+			path:   "/api/v1/auth/signup",
+			body:   signUpBody(shouted),
+		},
+	).expectProblem(t, http.StatusConflict, "email_already_exists")
+	a.login(shouted)
+
+	var fetched entities.UserDTO
+	a.do(request{method: http.MethodGet, path: "/api/v1/users/email/" + shouted, token: adminToken}).
+		expect(t, http.StatusOK, &fetched)
+	if fetched.ID != user.ID {
+		t.Fatalf("expected user %d by email, got %d", user.ID, fetched.ID)
+	}
+
+	// Internationalized domains are stored in their punycode form
+	_, international := a.signUp(fmt.Sprintf("user%d@Bücher.example", emailCount.Add(1)))
+	local, domain, _ := strings.Cut(international.Email, "@")
+	if domain != "xn--bcher-kva.example" {
+		t.Fatalf("expected a punycode domain, got %s", international.Email)
+	}
+	a.login(local + "@bücher.example")
+}
+
+func TestUserCRUD(t *testing.T) {
+	a := newAPI(t)
+	adminToken, admin := a.createAdmin()
+
+	// Create
+	email := uniqueEmail()
+	var created entities.UserDTO
+	a.do(
+		request{
+			method: http.MethodPost,
+			path:   "/api/v1/users",
+			token:  adminToken,
+			body: map[string]string{
+				"email":     email,
+				"password":  password,
+				"firstName": "John",
+				"lastName":  "Doe",
+			},
+		},
+	).expect(t, http.StatusCreated, &created)
+	if created.Email != email || created.Role != entities.RoleUser {
+		t.Fatalf("unexpected created user: %+v", created)
+	}
+	path := fmt.Sprintf("/api/v1/users/%d", created.ID)
+
+	// Read, by ID, by email and in the list
+	var fetched entities.UserDTO
+	a.do(request{method: http.MethodGet, path: path, token: adminToken}).
+		expect(t, http.StatusOK, &fetched)
+	if fetched.ID != created.ID || fetched.FirstName != "John" {
+		t.Fatalf("unexpected user: %+v", fetched)
+	}
+	a.do(request{method: http.MethodGet, path: "/api/v1/users/email/" + email, token: adminToken}).
+		expect(t, http.StatusOK, &fetched)
+	if fetched.ID != created.ID {
+		t.Fatalf("expected user %d by email, got %d", created.ID, fetched.ID)
+	}
+	var users []entities.UserDTO
+	a.do(request{method: http.MethodGet, path: "/api/v1/users", token: adminToken}).
+		expect(t, http.StatusOK, &users)
+	if !containsUser(users, created.ID) {
+		t.Fatalf("expected user %d in the list %+v", created.ID, users)
+	}
+
+	// Update
+	var updated entities.UserDTO
+	a.do(
+		request{
+			method: http.MethodPut,
+			path:   path,
+			token:  adminToken,
+			body: map[string]any{
+				"id":        created.ID,
+				"email":     email,
+				"firstName": "Johnny",
+				"lastName":  "Doe",
+			},
+		},
+	).expect(t, http.StatusOK, &updated)
+	if updated.FirstName != "Johnny" {
+		t.Fatalf("expected the first name to be updated: %+v", updated)
+	}
+
+	// The email of another user is refused by both updates
+	a.do(
+		request{
+			method: http.MethodPut,
+			path:   path,
+			token:  adminToken,
+			body: map[string]any{
+				"id":        created.ID,
+				"email":     admin.Email,
+				"firstName": "Johnny",
+				"lastName":  "Doe",
+			},
+		},
+	).expectProblem(t, http.StatusConflict, "email_already_exists")
+	a.do(mergePatch(path, adminToken, fmt.Sprintf(`{"email":%q}`, admin.Email))).
+		expectProblem(t, http.StatusConflict, "email_already_exists")
+
+	// Patch
+	var patched entities.UserDTO
+	a.do(
+		request{
+			method: http.MethodPatch,
+			path:   path,
+			token:  adminToken,
+			body:   `{"lastName":"Smith"}`,
+			header: map[string]string{"Content-Type": "application/merge-patch+json"},
+		},
+	).expect(t, http.StatusOK, &patched)
+	if patched.FirstName != "Johnny" || patched.LastName != "Smith" {
+		t.Fatalf("expected only the last name to be patched: %+v", patched)
+	}
+
+	// Delete
+	a.do(request{method: http.MethodDelete, path: path, token: adminToken}).
+		expect(t, http.StatusNoContent, nil)
+	a.do(request{method: http.MethodGet, path: path, token: adminToken}).
+		expectProblem(t, http.StatusNotFound, "not_found")
+}
+
+func TestUpdateUserIDMismatch(t *testing.T) {
+	a := newAPI(t)
+	adminToken, _ := a.createAdmin()
+	token, user := a.signUp(uniqueEmail())
+	_, other := a.signUp(uniqueEmail())
+	path := fmt.Sprintf("/api/v1/users/%d", user.ID)
+
+	tests := []struct {
+		name string
+		path string
+		id   uint
+	}{
+		{"body ID of another user", path, other.ID},
+		{"missing body ID", path, 0},
+		{"invalid path ID", "/api/v1/users/abc", user.ID},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				body := map[string]any{
+					"email":     user.Email,
+					"firstName": "Changed",
+					"lastName":  "Name",
+				}
+				if tt.id != 0 {
+					body["id"] = tt.id
+				}
+				res := a.do(request{method: http.MethodPut, path: tt.path, token: token, body: body})
+				if res.status != http.StatusBadRequest {
+					t.Fatalf("expected status 400, got %d: %s", res.status, res.body)
+				}
+			},
+		)
+	}
+
+	// Neither user was changed
+	for _, u := range []entities.UserDTO{user, other} {
+		var fetched entities.UserDTO
+		a.do(
+			request{
+				method: http.MethodGet,
+				path:   fmt.Sprintf("/api/v1/users/%d", u.ID),
+				token:  adminToken,
+			},
+		).expect(t, http.StatusOK, &fetched)
+		if fetched.FirstName != u.FirstName || fetched.LastName != u.LastName {
+			t.Errorf("user %d was changed: %+v", u.ID, fetched)
+		}
+	}
+}
+
+func TestUserAccess(t *testing.T) {
+	a := newAPI(t)
+	adminToken, _ := a.createAdmin()
+	token, user := a.signUp(uniqueEmail())
+	otherToken, _ := a.signUp(uniqueEmail())
+	path := fmt.Sprintf("/api/v1/users/%d", user.ID)
+
+	update := func(token, firstName string) response {
+		return a.do(
+			request{
+				method: http.MethodPut,
+				path:   path,
+				token:  token,
+				body: map[string]any{
+					"id":        user.ID,
+					"email":     user.Email,
+					"firstName": firstName,
+					"lastName":  "Doe",
+				},
+			},
+		)
+	}
+	patch := func(token, lastName string) response {
+		return a.do(mergePatch(path, token, fmt.Sprintf(`{"lastName":%q}`, lastName)))
+	}
+
+	get := func(token string) response {
+		return a.do(request{method: http.MethodGet, path: path, token: token})
+	}
+	remove := func(token string) response {
+		return a.do(request{method: http.MethodDelete, path: path, token: token})
+	}
+
+	// Another user may not access the user, nor may users list everyone
+	get(otherToken).expectProblem(t, http.StatusForbidden, "forbidden")
+	update(otherToken, "Hijacked").expectProblem(t, http.StatusForbidden, "forbidden")
+	patch(otherToken, "Hijacked").expectProblem(t, http.StatusForbidden, "forbidden")
+	remove(otherToken).expectProblem(t, http.StatusForbidden, "forbidden")
+	a.do(request{method: http.MethodGet, path: "/api/v1/users", token: token}).
+		expectProblem(t, http.StatusForbidden, "forbidden")
+
+	// The user themselves and admins may
+	var fetched entities.UserDTO
+	get(token).expect(t, http.StatusOK, &fetched)
+	get(adminToken).expect(t, http.StatusOK, &fetched)
+	if fetched.ID != user.ID {
+		t.Fatalf("expected user %d, got %+v", user.ID, fetched)
+	}
+	var updated entities.UserDTO
+	update(token, "Self").expect(t, http.StatusOK, &updated)
+	patch(adminToken, "Admin").expect(t, http.StatusOK, &updated)
+	if updated.FirstName != "Self" || updated.LastName != "Admin" {
+		t.Fatalf("expected the user and an admin to change the user: %+v", updated)
+	}
+	var users []entities.UserDTO
+	a.do(request{method: http.MethodGet, path: "/api/v1/users", token: adminToken}).
+		expect(t, http.StatusOK, &users)
+	if !containsUser(users, user.ID) {
+		t.Fatalf("expected user %d in the list %+v", user.ID, users)
+	}
+	remove(token).expect(t, http.StatusNoContent, nil)
+}
+
+func TestPublicUserLookup(t *testing.T) {
+	a := newAPI(
+		t, app.WithConfig(
+			func(cfg *config.Config) {
+				cfg.RateLimit.Enabled = true
+				cfg.Access.UserLookup = "public"
+			},
+		),
+	)
+	adminToken, _ := a.createAdmin()
+	_, user := a.signUp(uniqueEmail())
+	lookup := func(email, token string) response {
+		return a.do(request{method: http.MethodGet, path: "/api/v1/users/email/" + email, token: token})
+	}
+
+	// Anyone, admins included, only learns that the address is registered
+	for _, token := range []string{"", adminToken} {
+		var found map[string]any
+		lookup(strings.ToUpper(user.Email), token).expect(t, http.StatusOK, &found)
+		if len(found) != 1 || found["email"] != user.Email {
+			t.Errorf("expected only the email %s, got %v", user.Email, found)
+		}
+	}
+	lookup(uniqueEmail(), "").expectProblem(t, http.StatusNotFound, "not_found")
+}
+
+func TestPublicUserLookupRequiresRateLimit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ratelimits.yaml")
+	policies := `policies:
+  - name: login
+    routes: ["POST /api/v1/auth/login"]
+    algorithm: token_bucket
+    limit: 5
+    period: 1m
+    key: ip
+`
+	if err := os.WriteFile(path, []byte(policies), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		configure func(cfg *config.Config)
+		wantErr   string
+	}{
+		{
+			"rate limiting disabled",
+			func(cfg *config.Config) { cfg.RateLimit.Enabled = false },
+			"USER_LOOKUP_ACCESS=public requires RATE_LIMIT_ENABLED=true",
+		},
+		{
+			"lookup not limited",
+			func(cfg *config.Config) { cfg.RateLimit.PoliciesFile = path },
+			"requires a rate limit policy covering",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				a, err := app.New(
+					context.Background(),
+					nil,
+					app.WithMailer(nil),
+					app.WithConfig(
+						func(cfg *config.Config) {
+							cfg.Storage = "memory"
+							cfg.RateLimit.Store = "memory"
+							cfg.JWT.Secret = testSecret
+							cfg.Access.UserLookup = "public"
+						},
+					),
+					app.WithConfig(tt.configure),
+				)
+				if err == nil {
+					a.Close()
+					t.Fatal("expected the application to be refused")
+				}
+				if !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected an error containing %q, got %v", tt.wantErr, err)
+				}
+			},
+		)
+	}
+}
+
+func TestDeleteUser(t *testing.T) {
+	a := newAPI(t)
+	token, _ := a.createAdmin()
+	_, user := a.signUp(uniqueEmail())
+	path := fmt.Sprintf("/api/v1/users/%d", user.ID)
+
+	res := a.do(request{method: http.MethodDelete, path: path, token: token})
+	res.expect(t, http.StatusNoContent, nil)
+	if len(res.body) != 0 {
+		t.Errorf("expected no content, got %q", res.body)
+	}
+
+	// The user is gone
+	a.do(request{method: http.MethodDelete, path: path, token: token}).
+		expectProblem(t, http.StatusNotFound, "not_found")
+	a.do(
+		request{
+			method: http.MethodPost,
+			path:   "/api/v1/auth/login",
+			body:   map[string]string{"email": user.Email, "password": password},
+		},
+	).expectProblem(t, http.StatusUnauthorized, "unauthorized")
+
+	// Invalid IDs are rejected before reaching the handler
+	a.do(request{method: http.MethodDelete, path: "/api/v1/users/abc", token: token}).
+		expectProblem(t, http.StatusBadRequest, "bad_request")
+}
+
+func TestUnknownRoute(t *testing.T) {
+	a := newAPI(t)
+
+	problem := a.do(request{method: http.MethodGet, path: "/api/v1/unknown"}).
+		expectProblem(t, http.StatusNotFound, "not_found")
+	if problem.Instance != "/api/v1/unknown" {
+		t.Errorf("expected the instance to be the path, got %q", problem.Instance)
+	}
+}
+
+// containsUser reports whether users contains the user with the given ID
+func containsUser(users []entities.UserDTO, id uint) bool {
+	for _, user := range users {
+		if user.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func TestReadinessHidesCheckErrors(t *testing.T) {
+	a := newAPI(t)
+	a.app.Drain()
+
+	var report struct {
+		Status string           `json:"status"`
+		Checks []map[string]any `json:"checks"`
+	}
+	a.do(request{method: http.MethodGet, path: "/readyz"}).expect(t, http.StatusServiceUnavailable, &report)
+	if report.Status != "down" || len(report.Checks) != 1 {
+		t.Fatalf("expected a single failing check, got %+v", report)
+	}
+	for key := range report.Checks[0] {
+		if key != "name" && key != "status" {
+			t.Errorf("expected only the name and status of the check, got %q", key)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package e2e_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/app"
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/config"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/random"
+)
+
+// epoch is the time fake clocks start at
+var epoch = time.Date(2030, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+func TestTimestampsFollowTheClock(t *testing.T) {
+	clk := clock.NewFake(epoch)
+	a := newAPI(t, app.WithClock(clk))
+
+	token, user := a.signUp(uniqueEmail())
+	if !user.CreatedAt.Equal(epoch) || !user.UpdatedAt.Equal(epoch) {
+		t.Errorf("expected the user to be created at %s, got %+v", epoch, user)
+	}
+
+	// Updates are timestamped by the clock too, not by the system time
+	clk.Advance(time.Hour)
+	var patched entities.UserDTO
+	a.do(mergePatch(fmt.Sprintf("/api/v1/users/%d", user.ID), token, `{"lastName":"Later"}`)).
+		expect(t, http.StatusOK, &patched)
+	if !patched.CreatedAt.Equal(epoch) || !patched.UpdatedAt.Equal(epoch.Add(time.Hour)) {
+		t.Errorf(
+			"expected the user to be created at %s and updated at %s, got %+v",
+			epoch,
+			epoch.Add(time.Hour),
+			patched,
+		)
+	}
+}
+
+func TestTokenExpiry(t *testing.T) {
+	clk := clock.NewFake(epoch)
+	a := newAPI(
+		t,
+		app.WithClock(clk),
+		app.WithConfig(
+			func(cfg *config.Config) {
+				cfg.JWT.ExpirationHours = 1
+			},
+		),
+	)
+	token, user := a.signUp(uniqueEmail())
+	r := request{
+		method: http.MethodGet,
+		path:   fmt.Sprintf("/api/v1/users/%d", user.ID),
+		token:  token,
+	}
+
+	clk.Advance(time.Hour - time.Second)
+	a.do(r).expect(t, http.StatusOK, nil)
+
+	clk.Advance(time.Second)
+	a.do(r).expectProblem(t, http.StatusUnauthorized, "unauthorized")
+}
+
+func TestIdempotencyKeyExpiry(t *testing.T) {
+	clk := clock.NewFake(epoch)
+	a := newAPI(
+		t,
+		app.WithClock(clk),
+		app.WithConfig(
+			func(cfg *config.Config) {
+				cfg.Idempotency.TTL = time.Hour
+			},
+		),
+	)
+	adminToken, _ := a.createAdmin()
+	createUser := func(email string) response {
+		return a.do(
+			request{
+				method: http.MethodPost,
+				path:   "/api/v1/users",
+				token:  adminToken,
+				body:   signUpBody(email),
+				header: map[string]string{"Idempotency-Key": "create-user"},
+			},
+		)
+	}
+	createUser(uniqueEmail()).expect(t, http.StatusCreated, nil)
+
+	// The key cannot be reused for another payload until it expires
+	clk.Advance(time.Hour - time.Second)
+	createUser(uniqueEmail()).expectProblem(t, http.StatusUnprocessableEntity, "idempotency_key_reused")
+
+	clk.Advance(time.Second)
+	createUser(uniqueEmail()).expect(t, http.StatusCreated, nil)
+}
+
+func TestRateLimitWindow(t *testing.T) {
+	clk := clock.NewFake(epoch)
+	a := newAPI(
+		t,
+		app.WithClock(clk),
+		app.WithConfig(
+			func(cfg *config.Config) {
+				cfg.RateLimit.Enabled = true
+			},
+		),
+	)
+	login := request{
+		method: http.MethodPost,
+		path:   "/api/v1/auth/login",
+		body:   map[string]string{"email": uniqueEmail(), "password": password},
+	}
+
+	// The default login policy allows 5 attempts per minute
+	for range 5 {
+		a.do(login).expectProblem(t, http.StatusUnauthorized, "unauthorized")
+	}
+	res := a.do(login)
+	res.expectProblem(t, http.StatusTooManyRequests, "too_many_requests")
+	if res.header.Get("Retry-After") == "" {
+		t.Error("expected a Retry-After header")
+	}
+
+	// Attempts are allowed again once the bucket refilled
+	clk.Advance(time.Minute)
+	a.do(login).expectProblem(t, http.StatusUnauthorized, "unauthorized")
+}
+
+func TestRateLimitByAPIKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ratelimits.yaml")
+	policies := `policies:
+  - name: login
+    routes: ["POST /api/v1/auth/login"]
+    algorithm: token_bucket
+    limit: 2
+    period: 1m
+    key: api_key
+`
+	if err := os.WriteFile(path, []byte(policies), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	a := newAPI(
+		t,
+		app.WithClock(clock.NewFake(epoch)),
+		app.WithConfig(
+			func(cfg *config.Config) {
+				cfg.RateLimit.Enabled = true
+				cfg.RateLimit.PoliciesFile = path
+				cfg.RateLimit.APIKeys = []string{"partner:partner-key-0123456789", "mobile:mobile-key-0123456789"}
+			},
+		),
+	)
+	login := func(apiKey string) response {
+		return a.do(
+			request{
+				method: http.MethodPost,
+				path:   "/api/v1/auth/login",
+				body:   map[string]string{"email": uniqueEmail(), "password": password},
+				header: map[string]string{"X-API-Key": apiKey},
+			},
+		)
+	}
+
+	// Each client has its own quota
+	for range 2 {
+		login("partner-key-0123456789").expectProblem(t, http.StatusUnauthorized, "unauthorized")
+	}
+	login("partner-key-0123456789").expectProblem(t, http.StatusTooManyRequests, "too_many_requests")
+	login("mobile-key-0123456789").expectProblem(t, http.StatusUnauthorized, "unauthorized")
+}
+
+func TestSeededRandomSource(t *testing.T) {
+	// Applications reading the same sequence generate the same secrets
+	var secrets []string
+	for range 2 {
+		a := newAPI(t, app.WithRandom(random.NewSeeded(42)))
+		adminToken, _ := a.createAdmin()
+
+		var webhook entities.WebhookSubscriptionDTO
+		a.do(
+			request{
+				method: http.MethodPost,
+				path:   "/api/v1/webhooks",
+				token:  adminToken,
+				body: map[string]any{
+					"url":        "https://partner.example.com/hooks/users",
+					"eventTypes": []string{"user.created"},
+				},
+			},
+		).expect(t, http.StatusCreated, &webhook)
+		secrets = append(secrets, webhook.Secret)
+	}
+
+	if secrets[0] == "" || secrets[0] != secrets[1] {
+		t.Errorf("expected the same generated secret twice, got %q", secrets)
+	}
+}
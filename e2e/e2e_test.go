@@ -0,0 +1,249 @@
+// Package e2e_test runs the whole API in-process, with in-memory storage,
+// and exercises it over HTTP as a client would.
+package e2e_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/app"
+	"github.com/EngenMe/go-clean-architecture/application/commands"
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/config"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// testSecret signs the tokens of the test applications
+var testSecret = strings.Repeat("s", config.MinSecretLength)
+
+// password is the password of every test user
+const password = "Passw0rd!x"
+
+func TestMain(m *testing.M) {
+	// Keep the test output readable; set E2E_LOGS=1 to see the JSON logs
+	gin.SetMode(gin.TestMode)
+	if os.Getenv("E2E_LOGS") == "" {
+		slog.SetDefault(slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	}
+	os.Exit(m.Run())
+}
+
+// api is an application served over HTTP for the duration of a test
+type api struct {
+	t      *testing.T
+	app    *app.App
+	server *httptest.Server
+}
+
+// newAPI builds an application with in-memory storage, no emails and no
+// rate limiting, which opts may override, and serves it until the test ends
+func newAPI(t *testing.T, opts ...app.Option) *api {
+	t.Helper()
+
+	defaults := []app.Option{
+		app.WithMailer(nil),
+		app.WithConfig(
+			func(cfg *config.Config) {
+				cfg.Storage = "memory"
+				cfg.RateLimit.Store = "memory"
+				cfg.JWT.Secret = testSecret
+				cfg.RateLimit.Enabled = false
+			},
+		),
+	}
+	a, err := app.New(context.Background(), nil, append(defaults, opts...)...)
+	if err != nil {
+		t.Fatalf("failed to build the application: %v", err)
+	}
+	server := httptest.NewServer(a.Handler())
+	t.Cleanup(
+		func() {
+			server.Close()
+			if err := a.Close(); err != nil {
+				t.Errorf("failed to close the application: %v", err)
+			}
+		},
+	)
+	return &api{t: t, app: a, server: server}
+}
+
+// response is a response read in full
+type response struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// request describes a request sent by do
+type request struct {
+	method string
+	path   string
+	token  string // Bearer token, if any
+	body   any    // Encoded as JSON unless a string
+	header map[string]string
+}
+
+// do sends a request and reads its response
+func (a *api) do(r request) response {
+	a.t.Helper()
+
+	var body io.Reader
+	contentType := ""
+	switch value := r.body.(type) {
+	case nil:
+	case string:
+		body, contentType = strings.NewReader(value), "application/json"
+	default:
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			a.t.Fatalf("failed to encode the request body: %v", err)
+		}
+		body, contentType = bytes.NewReader(encoded), "application/json"
+	}
+
+	req, err := http.NewRequest(r.method, a.server.URL+r.path, body)
+	if err != nil {
+		a.t.Fatalf("failed to create the request: %v", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+	for name, value := range r.header {
+		req.Header.Set(name, value)
+	}
+
+	res, err := a.server.Client().Do(req)
+	if err != nil {
+		a.t.Fatalf("%s %s failed: %v", r.method, r.path, err)
+	}
+	defer res.Body.Close()
+	content, err := io.ReadAll(res.Body)
+	if err != nil {
+		a.t.Fatalf("failed to read the response of %s %s: %v", r.method, r.path, err)
+	}
+	return response{status: res.StatusCode, header: res.Header, body: content}
+}
+
+// expect fails the test unless the response has the given status, and
+// decodes its body into v unless nil
+func (r response) expect(t *testing.T, status int, v any) {
+	t.Helper()
+	if r.status != status {
+		t.Fatalf("expected status %d, got %d: %s", status, r.status, r.body)
+	}
+	if v != nil {
+		if err := json.Unmarshal(r.body, v); err != nil {
+			t.Fatalf("failed to decode %s: %v", r.body, err)
+		}
+	}
+}
+
+// expectProblem fails the test unless the response is a problem with the
+// given status and code, and returns it
+func (r response) expectProblem(t *testing.T, status int, code string) utils.Problem {
+	t.Helper()
+	var problem utils.Problem
+	r.expect(t, status, &problem)
+	if contentType := r.header.Get("Content-Type"); !strings.HasPrefix(contentType, utils.ProblemContentType) {
+		t.Errorf("expected a problem response, got Content-Type %q", contentType)
+	}
+	if problem.Code != code || problem.Status != status {
+		t.Fatalf("expected problem %d %s, got %d %s: %s", status, code, problem.Status, problem.Code, r.body)
+	}
+	return problem
+}
+
+// emailCount makes the emails of test users unique
+var emailCount atomic.Int64
+
+// uniqueEmail returns an email no other test user has
+func uniqueEmail() string {
+	return fmt.Sprintf("user%d@example.com", emailCount.Add(1))
+}
+
+// signUp registers a user through the API and returns its token and
+// representation
+func (a *api) signUp(email string) (string, entities.UserDTO) {
+	a.t.Helper()
+	var auth struct {
+		Token string           `json:"token"`
+		User  entities.UserDTO `json:"user"`
+	}
+	a.do(
+		request{
+			method: http.MethodPost,
+			path:   "/api/v1/auth/signup",
+			body: map[string]string{
+				"email":     email,
+				"password":  password,
+				"firstName": "Jane",
+				"lastName":  "Doe",
+			},
+		},
+	).expect(a.t, http.StatusCreated, &auth)
+	return auth.Token, auth.User
+}
+
+// createAdmin creates an admin as the management CLI does, since the API
+// cannot grant roles, and returns its token
+func (a *api) createAdmin() (string, entities.UserDTO) {
+	a.t.Helper()
+	ctx := context.Background()
+	email := uniqueEmail()
+	admin, err := a.app.UserService().CreateUser(
+		ctx, commands.CreateUserCommand{
+			Email:     email,
+			Password:  password,
+			FirstName: "Ada",
+			LastName:  "Admin",
+			Role:      entities.RoleAdmin,
+		},
+	)
+	if err != nil {
+		a.t.Fatalf("failed to create an admin: %v", err)
+	}
+	return a.login(email), *admin
+}
+
+// login authenticates a user and returns its token
+func (a *api) login(email string) string {
+	a.t.Helper()
+	var auth struct {
+		Token string `json:"token"`
+	}
+	a.do(
+		request{
+			method: http.MethodPost,
+			path:   "/api/v1/auth/login",
+			body:   map[string]string{"email": email, "password": password},
+		},
+	).expect(a.t, http.StatusOK, &auth)
+	return auth.Token
+}
+
+// eventually retries check until it succeeds or a few seconds passed
+func eventually(t *testing.T, what string, check func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !check() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
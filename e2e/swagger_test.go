@@ -0,0 +1,1163 @@
+package e2e_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/app"
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/config"
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+)
+
+// swaggerSpec is the part of docs/swagger.json the responses are checked
+// against
+type swaggerSpec struct {
+	Paths       map[string]map[string]operation `json:"paths"`
+	Definitions map[string]*schema              `json:"definitions"`
+}
+
+// operation is a documented method of a path
+type operation struct {
+	Responses map[string]struct {
+		Schema *schema `json:"schema"`
+	} `json:"responses"`
+}
+
+// schema is the subset of JSON Schema swag generates
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Properties           map[string]*schema `json:"properties"`
+	AdditionalProperties *schema            `json:"additionalProperties"`
+	Items                *schema            `json:"items"`
+	AllOf                []*schema          `json:"allOf"`
+	Enum                 []any              `json:"enum"`
+}
+
+// loadSwagger reads the generated Swagger documentation
+func loadSwagger(t *testing.T) *swaggerSpec {
+	t.Helper()
+	content, err := os.ReadFile("../docs/swagger.json")
+	if err != nil {
+		t.Fatalf("failed to read the Swagger documentation: %v", err)
+	}
+	var spec swaggerSpec
+	if err := json.Unmarshal(content, &spec); err != nil {
+		t.Fatalf("failed to decode the Swagger documentation: %v", err)
+	}
+	return &spec
+}
+
+// validate returns where value does not match s, if anywhere. Properties
+// are optional, but undocumented properties are reported.
+func (spec *swaggerSpec) validate(s *schema, value any, at string) []string {
+	if s.Ref != "" {
+		name := strings.TrimPrefix(s.Ref, "#/definitions/")
+		definition, ok := spec.Definitions[name]
+		if !ok {
+			return []string{fmt.Sprintf("%s: undefined %s", at, s.Ref)}
+		}
+		return spec.validate(definition, value, at)
+	}
+
+	var mismatches []string
+	for _, part := range s.AllOf {
+		mismatches = append(mismatches, spec.validate(part, value, at)...)
+	}
+	if s.Type == "" {
+		return mismatches
+	}
+
+	mismatch := func(format string, args ...any) []string {
+		return append(mismatches, at+": "+fmt.Sprintf(format, args...))
+	}
+	switch s.Type {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return mismatch("expected an object, got %T", value)
+		}
+		for name, property := range object {
+			propertySchema, ok := s.Properties[name]
+			if !ok {
+				propertySchema = s.AdditionalProperties
+			}
+			if propertySchema == nil {
+				mismatches = mismatch("undocumented property %q", name)
+				continue
+			}
+			mismatches = append(mismatches, spec.validate(propertySchema, property, at+"."+name)...)
+		}
+	case "array":
+		array, ok := value.([]any)
+		if !ok {
+			return mismatch("expected an array, got %T", value)
+		}
+		for i, item := range array {
+			mismatches = append(mismatches, spec.validate(s.Items, item, fmt.Sprintf("%s[%d]", at, i))...)
+		}
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			return mismatch("expected a string, got %T", value)
+		}
+		if len(s.Enum) > 0 && !containsValue(s.Enum, text) {
+			return mismatch("%q is not one of %v", text, s.Enum)
+		}
+	case "integer":
+		number, ok := value.(float64)
+		if !ok || number != float64(int64(number)) {
+			return mismatch("expected an integer, got %v", value)
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return mismatch("expected a number, got %T", value)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return mismatch("expected a boolean, got %T", value)
+		}
+	default:
+		return mismatch("unsupported schema type %q", s.Type)
+	}
+	return mismatches
+}
+
+// containsValue reports whether values contains value
+func containsValue(values []any, value any) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// exchange is a request expected to get a documented response
+type exchange struct {
+	method string
+	path   string // As documented
+	status int
+	send   func(f *fixture) response
+}
+
+// fixture holds the applications and resources the exchanges use
+type fixture struct {
+	t          *testing.T
+	api        *api // Runs its workers, so that webhooks are delivered
+	limited    *api // Rate limits requests
+	broken     *api // Cannot list users
+	drained    *api // Is shutting down
+	adminToken string
+	userToken  string
+	user       entities.UserDTO
+	webhookID  uint
+	deliveryID uint
+}
+
+// newFixture starts the applications and creates a user, an admin and a
+// webhook that received a delivery
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	f := &fixture{t: t}
+
+	f.api = newAPI(
+		t, app.WithConfig(
+			func(cfg *config.Config) {
+				cfg.Outbox.PollInterval = 10 * time.Millisecond
+				cfg.Webhooks.PollInterval = 10 * time.Millisecond
+			},
+		),
+	)
+	f.api.app.Start(context.Background())
+	f.adminToken, _ = f.api.createAdmin()
+	f.userToken, f.user = f.api.signUp(uniqueEmail())
+
+	receiver := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			},
+		),
+	)
+	t.Cleanup(receiver.Close)
+	f.webhookID = f.createWebhook(receiver.URL)
+
+	// Signing up a user publishes a user.created event to the webhook
+	f.api.signUp(uniqueEmail())
+	eventually(
+		t, "a webhook delivery", func() bool {
+			var deliveries []entities.WebhookDelivery
+			f.api.do(
+				request{
+					method: http.MethodGet,
+					path:   fmt.Sprintf("/api/v1/webhooks/%d/deliveries", f.webhookID),
+					token:  f.adminToken,
+				},
+			).expect(t, http.StatusOK, &deliveries)
+			if len(deliveries) == 0 {
+				return false
+			}
+			f.deliveryID = deliveries[0].ID
+			return true
+		},
+	)
+
+	f.limited = newAPI(
+		t, app.WithConfig(
+			func(cfg *config.Config) {
+				cfg.RateLimit.Enabled = true
+			},
+		),
+	)
+
+	storage := app.MemoryStorage(clock.System)
+	storage.Users = failingUsers{storage.Users}
+	f.broken = newAPI(t, app.WithStorage(storage))
+
+	f.drained = newAPI(t)
+	f.drained.app.Drain()
+	return f
+}
+
+// createWebhook subscribes url to user.created events and returns the ID
+// of the subscription
+func (f *fixture) createWebhook(url string) uint {
+	f.t.Helper()
+	var webhook entities.WebhookSubscriptionDTO
+	f.api.do(
+		request{
+			method: http.MethodPost,
+			path:   "/api/v1/webhooks",
+			token:  f.adminToken,
+			body: map[string]any{
+				"url":        url,
+				"eventTypes": []string{"user.created"},
+			},
+		},
+	).expect(f.t, http.StatusCreated, &webhook)
+	return webhook.ID
+}
+
+// brokenToken returns an admin token the broken application accepts
+func (f *fixture) brokenToken() string {
+	f.t.Helper()
+	email, err := entities.NewEmail("broken@example.com")
+	if err != nil {
+		f.t.Fatalf("failed to create an email: %v", err)
+	}
+	token, err := f.broken.app.JWT().GenerateToken(
+		&entities.User{ID: 1, Email: email, Role: entities.RoleAdmin},
+	)
+	if err != nil {
+		f.t.Fatalf("failed to generate a token: %v", err)
+	}
+	return token
+}
+
+// failingUsers is a user repository whose listing fails
+type failingUsers struct {
+	repositories.GenericRepository[entities.User]
+}
+
+// FindAll always fails
+func (failingUsers) FindAll(context.Context) ([]*entities.User, error) {
+	return nil, errors.New("storage unavailable")
+}
+
+// until repeats r on a until it gets status, e.g. to exceed a rate limit
+func until(a *api, r request, status int) response {
+	a.t.Helper()
+	var res response
+	for range 100 {
+		if res = a.do(r); res.status == status {
+			break
+		}
+	}
+	return res
+}
+
+// signUpBody returns a valid sign-up or user creation payload
+func signUpBody(email string) map[string]string {
+	return map[string]string{
+		"email":     email,
+		"password":  password,
+		"firstName": "Jane",
+		"lastName":  "Doe",
+	}
+}
+
+// withIdempotencyKey returns r sent with key, as a retried request would be
+func withIdempotencyKey(r request, key string) request {
+	r.header = map[string]string{"Idempotency-Key": key}
+	return r
+}
+
+// reusedIdempotencyKey sends r twice with the same Idempotency-Key but a
+// different email, which must be rejected the second time
+func reusedIdempotencyKey(a *api, r request) response {
+	a.t.Helper()
+	key := "retry-" + uniqueEmail()
+	r.body = signUpBody(uniqueEmail())
+	a.do(withIdempotencyKey(r, key))
+	r.body = signUpBody(uniqueEmail())
+	return a.do(withIdempotencyKey(r, key))
+}
+
+// mergePatch returns a JSON merge patch request
+func mergePatch(path, token, body string) request {
+	return request{
+		method: http.MethodPatch,
+		path:   path,
+		token:  token,
+		body:   body,
+		header: map[string]string{"Content-Type": "application/merge-patch+json"},
+	}
+}
+
+// exchanges returns a request for every documented response
+func exchanges() []exchange {
+	const missingID = 999999
+	userPath := func(id any) string { return fmt.Sprintf("/api/v1/users/%v", id) }
+	webhookPath := func(id any) string { return fmt.Sprintf("/api/v1/webhooks/%v", id) }
+	webhookBody := map[string]any{
+		"url":        "https://partner.example.com/hooks/users",
+		"eventTypes": []string{"user.deleted"},
+		"active":     true,
+	}
+	get := func(path, token string) request {
+		return request{method: http.MethodGet, path: path, token: token}
+	}
+
+	return []exchange{
+		// Admin
+		{
+			http.MethodGet, "/api/v1/admin/metrics/requests", http.StatusOK,
+			func(f *fixture) response {
+				return f.api.do(get("/api/v1/admin/metrics/requests", f.adminToken))
+			},
+		},
+		{
+			http.MethodGet, "/api/v1/admin/metrics/requests", http.StatusUnauthorized,
+			func(f *fixture) response { return f.api.do(get("/api/v1/admin/metrics/requests", "")) },
+		},
+		{
+			http.MethodGet, "/api/v1/admin/metrics/requests", http.StatusForbidden,
+			func(f *fixture) response {
+				return f.api.do(get("/api/v1/admin/metrics/requests", f.userToken))
+			},
+		},
+
+		// Authentication
+		{
+			http.MethodPost, "/api/v1/auth/login", http.StatusOK,
+			func(f *fixture) response {
+				return f.api.do(
+					request{
+						method: http.MethodPost,
+						path:   "/api/v1/auth/login",
+						body:   map[string]string{"email": f.user.Email, "password": password},
+					},
+				)
+			},
+		},
+		{
+			http.MethodPost, "/api/v1/auth/login", http.StatusBadRequest,
+			func(f *fixture) response {
+				return f.api.do(
+					request{
+						method: http.MethodPost,
+						path:   "/api/v1/auth/login",
+						body:   map[string]string{"email": "not-an-email"},
+					},
+				)
+			},
+		},
+		{
+			http.MethodPost, "/api/v1/auth/login", http.StatusUnauthorized,
+			func(f *fixture) response {
+				return f.api.do(
+					request{
+						method: http.MethodPost,
+						path:   "/api/v1/auth/login",
+						body:   map[string]string{"email": f.user.Email, "password": "wrong-password"},
+					},
+				)
+			},
+		},
+		{
+			http.MethodPost, "/api/v1/auth/login", http.StatusTooManyRequests,
+			func(f *fixture) response {
+				return until(
+					f.limited,
+					request{
+						method: http.MethodPost,
+						path:   "/api/v1/auth/login",
+						body:   map[string]string{"email": uniqueEmail(), "password": password},
+					},
+					http.StatusTooManyRequests,
+				)
+			},
+		},
+		{
+			http.MethodPost, "/api/v1/auth/signup", http.StatusCreated,
+			func(f *fixture) response {
+				return f.api.do(
+					request{
+						method: http.MethodPost,
+						path:   "/api/v1/auth/signup",
+						body:   signUpBody(uniqueEmail()),
+					},
+				)
+			},
+		},
+		{
+			http.MethodPost, "/api/v1/auth/signup", http.StatusBadRequest,
+			func(f *fixture) response {
+				return f.api.do(
+					request{
+						method: http.MethodPost,
+						path:   "/api/v1/auth/signup",
+						body:   signUpBody("not-an-email"),
+					},
+				)
+			},
+		},
+		{
+			http.MethodPost, "/api/v1/auth/signup", http.StatusConflict,
+			func(f *fixture) response {
+				return f.api.do(
+					request{
+						method: http.MethodPost,
+						path:   "/api/v1/auth/signup",
+						body:   signUpBody(f.user.Email),
+					},
+				)
+			},
+		},
+		{
+			http.MethodPost, "/api/v1/auth/signup", http.StatusUnprocessableEntity,
+			func(f *fixture) response {
+				return reusedIdempotencyKey(
+					f.api,
+					request{method: http.MethodPost, path: "/api/v1/auth/signup"},
+				)
+			},
+		},
+		{
+			http.MethodPost, "/api/v1/auth/signup", http.StatusTooManyRequests,
+			func(f *fixture) response {
+				return until(
+					f.limited,
+					request{
+						method: http.MethodPost,
+						path:   "/api/v1/auth/signup",
+						body:   signUpBody("not-an-email"),
+					},
+					http.StatusTooManyRequests,
+				)
+			},
+		},
+
+		// Users
+		{
+			http.MethodGet, "/api/v1/users", http.StatusOK,
+			func(f *fixture) response { return f.api.do(get("/api/v1/users", f.adminToken)) },
+		},
+		{
+			http.MethodGet, "/api/v1/users", http.StatusUnauthorized,
+			func(f *fixture) response { return f.api.do(get("/api/v1/users", "")) },
+		},
+		{
+			http.MethodGet, "/api/v1/users", http.StatusForbidden,
+			func(f *fixture) response { return f.api.do(get("/api/v1/users", f.userToken)) },
+		},
+		{
+			http.MethodGet, "/api/v1/users", http.StatusInternalServerError,
+			func(f *fixture) response { return f.broken.do(get("/api/v1/users", f.brokenToken())) },
+		},
+		{
+			http.MethodPost, "/api/v1/users", http.StatusCreated,
+			func(f *fixture) response {
+				return f.api.do(
+					request{
+						method: http.MethodPost,
+						path:   "/api/v1/users",
+						token:  f.adminToken,
+						body:   signUpBody(uniqueEmail()),
+					},
+				)
+			},
+		},
+		{
+			http.MethodPost, "/api/v1/users", http.StatusBadRequest,
+			func(f *fixture) response {
+				return f.api.do(
+					request{
+						method: http.MethodPost,
+						path:   "/api/v1/users",
+						token:  f.adminToken,
+						body:   map[string]string{"email": uniqueEmail()},
+					},
+				)
+			},
+		},
+		{
+			http.MethodPost, "/api/v1/users", http.StatusUnauthorized,
+			func(f *fixture) response {
+				return f.api.do(
+					request{
+						method: http.MethodPost,
+						path:   "/api/v1/users",
+						body:   signUpBody(uniqueEmail()),
+					},
+				)
+			},
+		},
+		{
+			http.MethodPost, "/api/v1/users", http.StatusForbidden,
+			func(f *fixture) response {
+				return f.api.do(
+					request{
+						method: http.MethodPost,
+						path:   "/api/v1/users",
+						token:  f.userToken,
+						body:   signUpBody(uniqueEmail()),
+					},
+				)
+			},
+		},
+		{
+			http.MethodPost, "/api/v1/users", http.StatusConflict,
+			func(f *fixture) response {
+				return f.api.do(
+					request{
+						method: http.MethodPost,
+						path:   "/api/v1/users",
+						token:  f.adminToken,
+						body:   signUpBody(f.user.Email),
+					},
+				)
+			},
+		},
+		{
+			http.MethodPost, "/api/v1/users", http.StatusUnprocessableEntity,
+			func(f *fixture) response {
+				return reusedIdempotencyKey(
+					f.api,
+					request{method: http.MethodPost, path: "/api/v1/users", token: f.adminToken},
+				)
+			},
+		},
+		{
+			http.MethodGet, "/api/v1/users/email/{email}", http.StatusOK,
+			func(f *fixture) response {
+				return f.api.do(get("/api/v1/users/email/"+f.user.Email, f.adminToken))
+			},
+		},
+		{
+			http.MethodGet, "/api/v1/users/email/{email}", http.StatusBadRequest,
+			func(f *fixture) response {
+				return f.api.do(get("/api/v1/users/email/not-an-email", f.adminToken))
+			},
+		},
+		{
+			http.MethodGet, "/api/v1/users/email/{email}", http.StatusUnauthorized,
+			func(f *fixture) response {
+				return f.api.do(get("/api/v1/users/email/"+f.user.Email, ""))
+			},
+		},
+		{
+			http.MethodGet, "/api/v1/users/email/{email}", http.StatusForbidden,
+			func(f *fixture) response {
+				return f.api.do(get("/api/v1/users/email/"+f.user.Email, f.userToken))
+			},
+		},
+		{
+			http.MethodGet, "/api/v1/users/email/{email}", http.StatusNotFound,
+			func(f *fixture) response {
+				return f.api.do(get("/api/v1/users/email/"+uniqueEmail(), f.adminToken))
+			},
+		},
+		{
+			http.MethodGet, "/api/v1/users/email/{email}", http.StatusTooManyRequests,
+			func(f *fixture) response {
+				return until(
+					f.limited,
+					get("/api/v1/users/email/"+uniqueEmail(), ""),
+					http.StatusTooManyRequests,
+				)
+			},
+		},
+		{
+			http.MethodGet, "/api/v1/users/{id}", http.StatusOK,
+			func(f *fixture) response { return f.api.do(get(userPath(f.user.ID), f.userToken)) },
+		},
+		{
+			http.MethodGet, "/api/v1/users/{id}", http.StatusBadRequest,
+			func(f *fixture) response { return f.api.do(get(userPath("abc"), f.userToken)) },
+		},
+		{
+			http.MethodGet, "/api/v1/users/{id}", http.StatusUnauthorized,
+			func(f *fixture) response { return f.api.do(get(userPath(f.user.ID), "")) },
+		},
+		{
+			http.MethodGet, "/api/v1/users/{id}", http.StatusForbidden,
+			func(f *fixture) response { return f.api.do(get(userPath(missingID), f.userToken)) },
+		},
+		{
+			http.MethodGet, "/api/v1/users/{id}", http.StatusNotFound,
+			func(f *fixture) response { return f.api.do(get(userPath(missingID), f.adminToken)) },
+		},
+		{
+			http.MethodPut, "/api/v1/users/{id}", http.StatusOK,
+			func(f *fixture) response {
+				token, user := f.api.signUp(uniqueEmail())
+				return f.api.do(
+					request{
+						method: http.MethodPut,
+						path:   userPath(user.ID),
+						token:  token,
+						body: map[string]any{
+							"id":        user.ID,
+							"email":     user.Email,
+							"firstName": "Updated",
+							"lastName":  "Doe",
+						},
+					},
+				)
+			},
+		},
+		{
+			http.MethodPut, "/api/v1/users/{id}", http.StatusBadRequest,
+			func(f *fixture) response {
+				return f.api.do(
+					request{
+						method: http.MethodPut,
+						path:   userPath(f.user.ID),
+						token:  f.userToken,
+						body:   map[string]any{"id": f.user.ID, "email": "not-an-email"},
+					},
+				)
+			},
+		},
+		{
+			http.MethodPut, "/api/v1/users/{id}", http.StatusUnauthorized,
+			func(f *fixture) response {
+				return f.api.do(
+					request{
+						method: http.MethodPut,
+						path:   userPath(f.user.ID),
+						body:   map[string]any{"id": f.user.ID, "email": f.user.Email},
+					},
+				)
+			},
+		},
+		{
+			http.MethodPut, "/api/v1/users/{id}", http.StatusForbidden,
+			func(f *fixture) response {
+				_, other := f.api.signUp(uniqueEmail())
+				return f.api.do(
+					request{
+						method: http.MethodPut,
+						path:   userPath(other.ID),
+						token:  f.userToken,
+						body: map[string]any{
+							"id":        other.ID,
+							"email":     other.Email,
+							"firstName": "Updated",
+							"lastName":  "Doe",
+						},
+					},
+				)
+			},
+		},
+		{
+			http.MethodPut, "/api/v1/users/{id}", http.StatusConflict,
+			func(f *fixture) response {
+				token, user := f.api.signUp(uniqueEmail())
+				return f.api.do(
+					request{
+						method: http.MethodPut,
+						path:   userPath(user.ID),
+						token:  token,
+						body: map[string]any{
+							"id":        user.ID,
+							"email":     f.user.Email,
+							"firstName": "Updated",
+							"lastName":  "Doe",
+						},
+					},
+				)
+			},
+		},
+		{
+			http.MethodPut, "/api/v1/users/{id}", http.StatusNotFound,
+			func(f *fixture) response {
+				return f.api.do(
+					request{
+						method: http.MethodPut,
+						path:   userPath(missingID),
+						token:  f.adminToken,
+						body: map[string]any{
+							"id":        missingID,
+							"email":     uniqueEmail(),
+							"firstName": "Jane",
+							"lastName":  "Doe",
+						},
+					},
+				)
+			},
+		},
+		{
+			http.MethodDelete, "/api/v1/users/{id}", http.StatusNoContent,
+			func(f *fixture) response {
+				_, user := f.api.signUp(uniqueEmail())
+				return f.api.do(
+					request{method: http.MethodDelete, path: userPath(user.ID), token: f.adminToken},
+				)
+			},
+		},
+		{
+			http.MethodDelete, "/api/v1/users/{id}", http.StatusBadRequest,
+			func(f *fixture) response {
+				return f.api.do(
+					request{method: http.MethodDelete, path: userPath("abc"), token: f.adminToken},
+				)
+			},
+		},
+		{
+			http.MethodDelete, "/api/v1/users/{id}", http.StatusUnauthorized,
+			func(f *fixture) response {
+				return f.api.do(request{method: http.MethodDelete, path: userPath(f.user.ID)})
+			},
+		},
+		{
+			http.MethodDelete, "/api/v1/users/{id}", http.StatusForbidden,
+			func(f *fixture) response {
+				_, other := f.api.signUp(uniqueEmail())
+				return f.api.do(
+					request{method: http.MethodDelete, path: userPath(other.ID), token: f.userToken},
+				)
+			},
+		},
+		{
+			http.MethodDelete, "/api/v1/users/{id}", http.StatusNotFound,
+			func(f *fixture) response {
+				return f.api.do(
+					request{method: http.MethodDelete, path: userPath(missingID), token: f.adminToken},
+				)
+			},
+		},
+		{
+			http.MethodPatch, "/api/v1/users/{id}", http.StatusOK,
+			func(f *fixture) response {
+				return f.api.do(mergePatch(userPath(f.user.ID), f.userToken, `{"lastName":"Patched"}`))
+			},
+		},
+		{
+			http.MethodPatch, "/api/v1/users/{id}", http.StatusBadRequest,
+			func(f *fixture) response {
+				return f.api.do(mergePatch(userPath(f.user.ID), f.userToken, `{"email":"not-an-email"}`))
+			},
+		},
+		{
+			http.MethodPatch, "/api/v1/users/{id}", http.StatusUnauthorized,
+			func(f *fixture) response {
+				return f.api.do(mergePatch(userPath(f.user.ID), "", `{"lastName":"Patched"}`))
+			},
+		},
+		{
+			http.MethodPatch, "/api/v1/users/{id}", http.StatusForbidden,
+			func(f *fixture) response {
+				_, other := f.api.signUp(uniqueEmail())
+				return f.api.do(mergePatch(userPath(other.ID), f.userToken, `{"lastName":"Patched"}`))
+			},
+		},
+		{
+			http.MethodPatch, "/api/v1/users/{id}", http.StatusNotFound,
+			func(f *fixture) response {
+				return f.api.do(mergePatch(userPath(missingID), f.adminToken, `{"lastName":"Patched"}`))
+			},
+		},
+		{
+			http.MethodPatch, "/api/v1/users/{id}", http.StatusConflict,
+			func(f *fixture) response {
+				_, other := f.api.signUp(uniqueEmail())
+				return f.api.do(
+					mergePatch(
+						userPath(f.user.ID),
+						f.userToken,
+						fmt.Sprintf(`{"email":%q}`, other.Email),
+					),
+				)
+			},
+		},
+		{
+			http.MethodPatch, "/api/v1/users/{id}", http.StatusUnsupportedMediaType,
+			func(f *fixture) response {
+				r := mergePatch(userPath(f.user.ID), f.userToken, `{"lastName":"Patched"}`)
+				r.header["Content-Type"] = "text/plain"
+				return f.api.do(r)
+			},
+		},
+
+		// Webhooks
+		{
+			http.MethodGet, "/api/v1/webhooks", http.StatusOK,
+			func(f *fixture) response { return f.api.do(get("/api/v1/webhooks", f.adminToken)) },
+		},
+		{
+			http.MethodGet, "/api/v1/webhooks", http.StatusUnauthorized,
+			func(f *fixture) response { return f.api.do(get("/api/v1/webhooks", "")) },
+		},
+		{
+			http.MethodGet, "/api/v1/webhooks", http.StatusForbidden,
+			func(f *fixture) response { return f.api.do(get("/api/v1/webhooks", f.userToken)) },
+		},
+		{
+			http.MethodPost, "/api/v1/webhooks", http.StatusCreated,
+			func(f *fixture) response {
+				return f.api.do(
+					request{
+						method: http.MethodPost,
+						path:   "/api/v1/webhooks",
+						token:  f.adminToken,
+						body:   webhookBody,
+					},
+				)
+			},
+		},
+		{
+			http.MethodPost, "/api/v1/webhooks", http.StatusBadRequest,
+			func(f *fixture) response {
+				return f.api.do(
+					request{
+						method: http.MethodPost,
+						path:   "/api/v1/webhooks",
+						token:  f.adminToken,
+						body:   map[string]any{"url": "ftp://example.com", "eventTypes": []string{"user.created"}},
+					},
+				)
+			},
+		},
+		{
+			http.MethodPost, "/api/v1/webhooks", http.StatusUnauthorized,
+			func(f *fixture) response {
+				return f.api.do(
+					request{method: http.MethodPost, path: "/api/v1/webhooks", body: webhookBody},
+				)
+			},
+		},
+		{
+			http.MethodPost, "/api/v1/webhooks", http.StatusForbidden,
+			func(f *fixture) response {
+				return f.api.do(
+					request{
+						method: http.MethodPost,
+						path:   "/api/v1/webhooks",
+						token:  f.userToken,
+						body:   webhookBody,
+					},
+				)
+			},
+		},
+		{
+			http.MethodGet, "/api/v1/webhooks/{id}", http.StatusOK,
+			func(f *fixture) response { return f.api.do(get(webhookPath(f.webhookID), f.adminToken)) },
+		},
+		{
+			http.MethodGet, "/api/v1/webhooks/{id}", http.StatusBadRequest,
+			func(f *fixture) response { return f.api.do(get(webhookPath("abc"), f.adminToken)) },
+		},
+		{
+			http.MethodGet, "/api/v1/webhooks/{id}", http.StatusUnauthorized,
+			func(f *fixture) response { return f.api.do(get(webhookPath(f.webhookID), "")) },
+		},
+		{
+			http.MethodGet, "/api/v1/webhooks/{id}", http.StatusForbidden,
+			func(f *fixture) response { return f.api.do(get(webhookPath(f.webhookID), f.userToken)) },
+		},
+		{
+			http.MethodGet, "/api/v1/webhooks/{id}", http.StatusNotFound,
+			func(f *fixture) response { return f.api.do(get(webhookPath(missingID), f.adminToken)) },
+		},
+		{
+			http.MethodPut, "/api/v1/webhooks/{id}", http.StatusOK,
+			func(f *fixture) response {
+				id := f.createWebhook("https://partner.example.com/hooks/users")
+				return f.api.do(
+					request{
+						method: http.MethodPut,
+						path:   webhookPath(id),
+						token:  f.adminToken,
+						body:   webhookBody,
+					},
+				)
+			},
+		},
+		{
+			http.MethodPut, "/api/v1/webhooks/{id}", http.StatusBadRequest,
+			func(f *fixture) response {
+				return f.api.do(
+					request{
+						method: http.MethodPut,
+						path:   webhookPath(f.webhookID),
+						token:  f.adminToken,
+						body:   map[string]any{"url": "https://partner.example.com/hooks/users"},
+					},
+				)
+			},
+		},
+		{
+			http.MethodPut, "/api/v1/webhooks/{id}", http.StatusUnauthorized,
+			func(f *fixture) response {
+				return f.api.do(
+					request{method: http.MethodPut, path: webhookPath(f.webhookID), body: webhookBody},
+				)
+			},
+		},
+		{
+			http.MethodPut, "/api/v1/webhooks/{id}", http.StatusForbidden,
+			func(f *fixture) response {
+				return f.api.do(
+					request{
+						method: http.MethodPut,
+						path:   webhookPath(f.webhookID),
+						token:  f.userToken,
+						body:   webhookBody,
+					},
+				)
+			},
+		},
+		{
+			http.MethodPut, "/api/v1/webhooks/{id}", http.StatusNotFound,
+			func(f *fixture) response {
+				return f.api.do(
+					request{
+						method: http.MethodPut,
+						path:   webhookPath(missingID),
+						token:  f.adminToken,
+						body:   webhookBody,
+					},
+				)
+			},
+		},
+		{
+			http.MethodDelete, "/api/v1/webhooks/{id}", http.StatusNoContent,
+			func(f *fixture) response {
+				id := f.createWebhook("https://partner.example.com/hooks/users")
+				return f.api.do(
+					request{method: http.MethodDelete, path: webhookPath(id), token: f.adminToken},
+				)
+			},
+		},
+		{
+			http.MethodDelete, "/api/v1/webhooks/{id}", http.StatusBadRequest,
+			func(f *fixture) response {
+				return f.api.do(
+					request{method: http.MethodDelete, path: webhookPath("abc"), token: f.adminToken},
+				)
+			},
+		},
+		{
+			http.MethodDelete, "/api/v1/webhooks/{id}", http.StatusUnauthorized,
+			func(f *fixture) response {
+				return f.api.do(request{method: http.MethodDelete, path: webhookPath(f.webhookID)})
+			},
+		},
+		{
+			http.MethodDelete, "/api/v1/webhooks/{id}", http.StatusForbidden,
+			func(f *fixture) response {
+				return f.api.do(
+					request{method: http.MethodDelete, path: webhookPath(f.webhookID), token: f.userToken},
+				)
+			},
+		},
+		{
+			http.MethodDelete, "/api/v1/webhooks/{id}", http.StatusNotFound,
+			func(f *fixture) response {
+				return f.api.do(
+					request{method: http.MethodDelete, path: webhookPath(missingID), token: f.adminToken},
+				)
+			},
+		},
+		{
+			http.MethodGet, "/api/v1/webhooks/{id}/deliveries", http.StatusOK,
+			func(f *fixture) response {
+				return f.api.do(get(webhookPath(f.webhookID)+"/deliveries", f.adminToken))
+			},
+		},
+		{
+			http.MethodGet, "/api/v1/webhooks/{id}/deliveries", http.StatusBadRequest,
+			func(f *fixture) response {
+				return f.api.do(get(webhookPath(f.webhookID)+"/deliveries?limit=abc", f.adminToken))
+			},
+		},
+		{
+			http.MethodGet, "/api/v1/webhooks/{id}/deliveries", http.StatusUnauthorized,
+			func(f *fixture) response { return f.api.do(get(webhookPath(f.webhookID)+"/deliveries", "")) },
+		},
+		{
+			http.MethodGet, "/api/v1/webhooks/{id}/deliveries", http.StatusForbidden,
+			func(f *fixture) response {
+				return f.api.do(get(webhookPath(f.webhookID)+"/deliveries", f.userToken))
+			},
+		},
+		{
+			http.MethodGet, "/api/v1/webhooks/{id}/deliveries", http.StatusNotFound,
+			func(f *fixture) response {
+				return f.api.do(get(webhookPath(missingID)+"/deliveries", f.adminToken))
+			},
+		},
+		{
+			http.MethodPost, "/api/v1/webhooks/{id}/deliveries/{deliveryId}/replay", http.StatusAccepted,
+			func(f *fixture) response {
+				return f.api.do(
+					request{
+						method: http.MethodPost,
+						path:   fmt.Sprintf("%s/deliveries/%d/replay", webhookPath(f.webhookID), f.deliveryID),
+						token:  f.adminToken,
+					},
+				)
+			},
+		},
+		{
+			http.MethodPost, "/api/v1/webhooks/{id}/deliveries/{deliveryId}/replay", http.StatusBadRequest,
+			func(f *fixture) response {
+				return f.api.do(
+					request{
+						method: http.MethodPost,
+						path:   webhookPath(f.webhookID) + "/deliveries/abc/replay",
+						token:  f.adminToken,
+					},
+				)
+			},
+		},
+		{
+			http.MethodPost, "/api/v1/webhooks/{id}/deliveries/{deliveryId}/replay", http.StatusUnauthorized,
+			func(f *fixture) response {
+				return f.api.do(
+					request{
+						method: http.MethodPost,
+						path:   fmt.Sprintf("%s/deliveries/%d/replay", webhookPath(f.webhookID), f.deliveryID),
+					},
+				)
+			},
+		},
+		{
+			http.MethodPost, "/api/v1/webhooks/{id}/deliveries/{deliveryId}/replay", http.StatusForbidden,
+			func(f *fixture) response {
+				return f.api.do(
+					request{
+						method: http.MethodPost,
+						path:   fmt.Sprintf("%s/deliveries/%d/replay", webhookPath(f.webhookID), f.deliveryID),
+						token:  f.userToken,
+					},
+				)
+			},
+		},
+		{
+			http.MethodPost, "/api/v1/webhooks/{id}/deliveries/{deliveryId}/replay", http.StatusNotFound,
+			func(f *fixture) response {
+				return f.api.do(
+					request{
+						method: http.MethodPost,
+						path:   fmt.Sprintf("%s/deliveries/%d/replay", webhookPath(f.webhookID), missingID),
+						token:  f.adminToken,
+					},
+				)
+			},
+		},
+
+		// Probes
+		{
+			http.MethodGet, "/health", http.StatusOK,
+			func(f *fixture) response { return f.api.do(get("/health", "")) },
+		},
+		{
+			http.MethodGet, "/livez", http.StatusOK,
+			func(f *fixture) response { return f.api.do(get("/livez", "")) },
+		},
+		{
+			http.MethodGet, "/readyz", http.StatusOK,
+			func(f *fixture) response { return f.api.do(get("/readyz", "")) },
+		},
+		{
+			http.MethodGet, "/readyz", http.StatusServiceUnavailable,
+			func(f *fixture) response { return f.drained.do(get("/readyz", "")) },
+		},
+	}
+}
+
+// TestSwaggerDocumentation checks that every documented response can be
+// obtained, and has the documented schema
+func TestSwaggerDocumentation(t *testing.T) {
+	spec := loadSwagger(t)
+	f := newFixture(t)
+
+	exercised := map[string]bool{}
+	for _, e := range exchanges() {
+		name := fmt.Sprintf("%s %s %d", e.method, e.path, e.status)
+		exercised[name] = true
+		t.Run(
+			name, func(t *testing.T) {
+				f.t = t
+				f.api.t, f.limited.t, f.broken.t, f.drained.t = t, t, t, t
+
+				res := e.send(f)
+				if res.status != e.status {
+					t.Fatalf("expected status %d, got %d: %s", e.status, res.status, res.body)
+				}
+
+				documented, ok := spec.Paths[e.path][strings.ToLower(e.method)].Responses[strconv.Itoa(e.status)]
+				if !ok {
+					t.Fatalf("status %d is not documented", e.status)
+				}
+				if documented.Schema == nil {
+					if len(res.body) != 0 {
+						t.Fatalf("expected no content, got %s", res.body)
+					}
+					return
+				}
+
+				var body any
+				if err := json.Unmarshal(res.body, &body); err != nil {
+					t.Fatalf("failed to decode %s: %v", res.body, err)
+				}
+				for _, mismatch := range spec.validate(documented.Schema, body, "body") {
+					t.Error(mismatch)
+				}
+			},
+		)
+	}
+
+	// Every documented response is exercised
+	var missing []string
+	for path, operations := range spec.Paths {
+		for method, op := range operations {
+			for status := range op.Responses {
+				name := fmt.Sprintf("%s %s %s", strings.ToUpper(method), path, status)
+				if !exercised[name] {
+					missing = append(missing, name)
+				}
+			}
+		}
+	}
+	sort.Strings(missing)
+	for _, name := range missing {
+		t.Errorf("no exchange for the documented response %s", name)
+	}
+}
@@ -0,0 +1,239 @@
+// Package config loads the typed configuration of the API from defaults,
+// a YAML file, environment variables and command line flags, and
+// validates it.
+package config
+
+import (
+	"time"
+)
+
+// Config is the configuration of the API. Each setting is read from the
+// YAML key of its field, from the environment variable named by its env
+// tag and from the flag named after that variable, e.g. -db-host for
+// DB_HOST. Settings tagged reload are applied without a restart when the
+// configuration is reloaded.
+type Config struct {
+	Env         string            `yaml:"env" env:"ENV"`
+	Server      ServerConfig      `yaml:"server"`
+	CORS        CORSConfig        `yaml:"cors"`
+	Watch       WatchConfig       `yaml:"watch"`
+	Log         LogConfig         `yaml:"log"`
+	Storage     string            `yaml:"storage" env:"STORAGE"`
+	Database    DatabaseConfig    `yaml:"database"`
+	JWT         JWTConfig         `yaml:"jwt"`
+	Outbox      OutboxConfig      `yaml:"outbox"`
+	Webhooks    WebhooksConfig    `yaml:"webhooks"`
+	Idempotency IdempotencyConfig `yaml:"idempotency"`
+	RateLimit   RateLimitConfig   `yaml:"rateLimit"`
+	Access      AccessConfig      `yaml:"access"`
+	Metrics     MetricsConfig     `yaml:"metrics"`
+	Tracing     TracingConfig     `yaml:"tracing"`
+	Mail        MailConfig        `yaml:"mail"`
+	Health      HealthConfig      `yaml:"health"`
+
+	// args are the flags the configuration was loaded with
+	args []string
+}
+
+// ServerConfig configures the HTTP server
+type ServerConfig struct {
+	Port int `yaml:"port" env:"PORT"`
+	// TrustedProxies may set X-Forwarded-For; none are trusted by default
+	TrustedProxies []string `yaml:"trustedProxies" env:"TRUSTED_PROXIES"`
+	// ShutdownDrainDelay is how long /readyz reports unready before the
+	// server stops
+	ShutdownDrainDelay time.Duration `yaml:"shutdownDrainDelay" env:"SHUTDOWN_DRAIN_DELAY"`
+	// ShutdownTimeout is how long in-flight requests may take to complete
+	// once the server stops
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout" env:"SHUTDOWN_TIMEOUT"`
+}
+
+// CORSConfig configures the browser origins allowed to call the API
+type CORSConfig struct {
+	// AllowedOrigins lists origins such as https://app.example.com, or *
+	// for any; CORS is disabled when empty
+	AllowedOrigins []string `yaml:"allowedOrigins" env:"CORS_ALLOWED_ORIGINS" reload:"true"`
+}
+
+// WatchConfig configures the watching of the configuration sources
+type WatchConfig struct {
+	// Interval is how often the sources are checked for changes; with 0,
+	// the configuration is only reloaded on SIGHUP
+	Interval time.Duration `yaml:"interval" env:"CONFIG_WATCH_INTERVAL"`
+}
+
+// LogConfig configures the JSON logs
+type LogConfig struct {
+	// Level defaults to debug in development and to info otherwise
+	Level string `yaml:"level" env:"LOG_LEVEL" reload:"true"`
+}
+
+// DatabaseConfig configures the database storage
+type DatabaseConfig struct {
+	Driver string `yaml:"driver" env:"DB_DRIVER"`
+	Host   string `yaml:"host" env:"DB_HOST"`
+	// Port defaults to the standard port of the driver when 0
+	Port     int    `yaml:"port" env:"DB_PORT"`
+	User     string `yaml:"user" env:"DB_USER"`
+	Password string `yaml:"password" env:"DB_PASSWORD"`
+	// Name is the file path with SQLite, or ":memory:"
+	Name           string `yaml:"name" env:"DB_NAME"`
+	SSLMode        string `yaml:"sslMode" env:"DB_SSL_MODE"`
+	MigrateOnStart bool   `yaml:"migrateOnStart" env:"MIGRATE_ON_START"`
+}
+
+// JWTConfig configures the signing of access tokens
+type JWTConfig struct {
+	// Secret signs new tokens
+	Secret string `yaml:"secret" env:"JWT_SECRET" reload:"true"`
+	// PreviousSecrets are still accepted, so that tokens signed before a
+	// rotation of the secret remain valid until they expire
+	PreviousSecrets []string `yaml:"previousSecrets" env:"JWT_PREVIOUS_SECRETS" reload:"true"`
+	ExpirationHours int      `yaml:"expirationHours" env:"JWT_EXPIRATION_HOURS" reload:"true"`
+}
+
+// OutboxConfig configures the delivery of domain events
+type OutboxConfig struct {
+	// Publishers lists log, file and mediatr
+	Publishers   []string      `yaml:"publishers" env:"OUTBOX_PUBLISHERS"`
+	File         string        `yaml:"file" env:"OUTBOX_FILE"`
+	PollInterval time.Duration `yaml:"pollInterval" env:"OUTBOX_POLL_INTERVAL"`
+	BatchSize    int           `yaml:"batchSize" env:"OUTBOX_BATCH_SIZE"`
+}
+
+// WebhooksConfig configures the delivery of webhooks
+type WebhooksConfig struct {
+	PollInterval time.Duration `yaml:"pollInterval" env:"WEBHOOK_POLL_INTERVAL"`
+	Timeout      time.Duration `yaml:"timeout" env:"WEBHOOK_TIMEOUT"`
+	MaxAttempts  int           `yaml:"maxAttempts" env:"WEBHOOK_MAX_ATTEMPTS"`
+}
+
+// IdempotencyConfig configures the Idempotency-Key support
+type IdempotencyConfig struct {
+	TTL time.Duration `yaml:"ttl" env:"IDEMPOTENCY_TTL"`
+}
+
+// RateLimitConfig configures rate limiting
+type RateLimitConfig struct {
+	Enabled bool `yaml:"enabled" env:"RATE_LIMIT_ENABLED"`
+	// Store is memory, or database to share counters between instances
+	Store string `yaml:"store" env:"RATE_LIMIT_STORE"`
+	// PoliciesFile replaces the default policies when set
+	PoliciesFile string `yaml:"policiesFile" env:"RATE_LIMIT_CONFIG" reload:"true"`
+	// APIKeys lists the "client:key" pairs of the X-API-Key header that
+	// policies keyed by api_key limit per client
+	APIKeys []string `yaml:"apiKeys" env:"RATE_LIMIT_API_KEYS"`
+}
+
+// AccessConfig configures who may use the user routes: public,
+// authenticated or admin
+type AccessConfig struct {
+	UserCreate string `yaml:"userCreate" env:"USER_CREATE_ACCESS"`
+	UserLookup string `yaml:"userLookup" env:"USER_LOOKUP_ACCESS"`
+}
+
+// MetricsConfig configures the Prometheus metrics
+type MetricsConfig struct {
+	Enabled bool `yaml:"enabled" env:"METRICS_ENABLED"`
+}
+
+// TracingConfig configures OpenTelemetry tracing
+type TracingConfig struct {
+	// Exporter is none, stdout or otlp
+	Exporter    string  `yaml:"exporter" env:"TRACING_EXPORTER"`
+	SampleRatio float64 `yaml:"sampleRatio" env:"TRACING_SAMPLE_RATIO"`
+	ServiceName string  `yaml:"serviceName" env:"OTEL_SERVICE_NAME"`
+	// OTLPEndpoint is the base URL of the OTLP/HTTP collector; the
+	// standard OTEL_EXPORTER_OTLP_* variables apply when empty
+	OTLPEndpoint string `yaml:"otlpEndpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+}
+
+// MailConfig configures the emails sent to users
+type MailConfig struct {
+	// Mailer is log, smtp or none
+	Mailer string     `yaml:"mailer" env:"MAILER"`
+	SMTP   SMTPConfig `yaml:"smtp"`
+}
+
+// SMTPConfig configures the smtp mailer
+type SMTPConfig struct {
+	Host     string `yaml:"host" env:"SMTP_HOST"`
+	Port     int    `yaml:"port" env:"SMTP_PORT"`
+	Username string `yaml:"username" env:"SMTP_USERNAME"`
+	Password string `yaml:"password" env:"SMTP_PASSWORD"`
+	From     string `yaml:"from" env:"SMTP_FROM"`
+}
+
+// HealthConfig configures the readiness checks
+type HealthConfig struct {
+	CheckTimeout time.Duration `yaml:"checkTimeout" env:"HEALTH_CHECK_TIMEOUT"`
+	OutboxMaxLag time.Duration `yaml:"outboxMaxLag" env:"HEALTH_OUTBOX_MAX_LAG"`
+}
+
+// Default returns the configuration used for the settings that no source
+// sets
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:               8080,
+			ShutdownDrainDelay: 5 * time.Second,
+			ShutdownTimeout:    30 * time.Second,
+		},
+		Watch: WatchConfig{
+			Interval: 10 * time.Second,
+		},
+		Storage: "database",
+		Database: DatabaseConfig{
+			Driver: "postgres",
+			Host:   "localhost",
+		},
+		JWT: JWTConfig{
+			ExpirationHours: 24,
+		},
+		Outbox: OutboxConfig{
+			Publishers:   []string{"log"},
+			File:         "events.jsonl",
+			PollInterval: time.Second,
+			BatchSize:    100,
+		},
+		Webhooks: WebhooksConfig{
+			PollInterval: time.Second,
+			Timeout:      10 * time.Second,
+			MaxAttempts:  10,
+		},
+		Idempotency: IdempotencyConfig{
+			TTL: 24 * time.Hour,
+		},
+		RateLimit: RateLimitConfig{
+			Enabled: true,
+			Store:   "memory",
+		},
+		Access: AccessConfig{
+			UserCreate: "admin",
+			UserLookup: "admin",
+		},
+		Metrics: MetricsConfig{
+			Enabled: true,
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			SampleRatio: 1,
+			ServiceName: "go-clean-architecture",
+		},
+		Mail: MailConfig{
+			Mailer: "log",
+			SMTP: SMTPConfig{
+				Port: 587,
+			},
+		},
+		Health: HealthConfig{
+			CheckTimeout: 2 * time.Second,
+			OutboxMaxLag: 5 * time.Minute,
+		},
+	}
+}
+
+// Expiration returns the lifetime of access tokens
+func (c JWTConfig) Expiration() time.Duration {
+	return time.Duration(c.ExpirationHours) * time.Hour
+}
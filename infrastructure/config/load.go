@@ -0,0 +1,284 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv names the YAML configuration file when -config is not given
+const FileEnv = "CONFIG_FILE"
+
+// dotEnvFile is the file whose variables complete the environment, in the
+// working directory
+const dotEnvFile = ".env"
+
+// setting is a leaf field of the configuration
+type setting struct {
+	env    string
+	reload bool
+	value  reflect.Value
+}
+
+// Load reads the configuration from its sources, each overriding the
+// previous ones: the defaults, the YAML file named by -config or
+// CONFIG_FILE, the environment variables, including those of a .env file,
+// and the flags of args. Flags stop at the first non-flag argument, e.g.
+// a subcommand; the remaining arguments are returned. The configuration
+// is not validated.
+func Load(args []string) (*Config, []string, error) {
+	env, err := readEnvironment(dotEnvFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfg := Default()
+	settings := cfg.settings()
+
+	// Flags are named after the environment variables, e.g. -db-host
+	flags := flag.NewFlagSet("go-clean-architecture", flag.ContinueOnError)
+	file := flags.String("config", env.get(FileEnv), "YAML configuration file")
+	overrides := make(map[string]string)
+	for _, s := range settings {
+		flags.Func(
+			FlagName(s.env), "Overrides "+s.env, func(value string) error {
+				overrides[s.env] = value
+				return nil
+			},
+		)
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	if *file != "" {
+		if err := cfg.loadFile(*file); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for _, s := range settings {
+		value, ok, err := env.lookup(s.env)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			if err := set(s.value, value); err != nil {
+				return nil, nil, fmt.Errorf("invalid %s: %w", s.env, err)
+			}
+		}
+		if value, ok := overrides[s.env]; ok {
+			if err := set(s.value, value); err != nil {
+				return nil, nil, fmt.Errorf("invalid -%s: %w", FlagName(s.env), err)
+			}
+		}
+	}
+
+	if cfg.Log.Level == "" {
+		cfg.Log.Level = "info"
+		if cfg.Env == "development" {
+			cfg.Log.Level = "debug"
+		}
+	}
+
+	cfg.args = slices.Clone(args[:len(args)-flags.NArg()])
+	return cfg, flags.Args(), nil
+}
+
+// Reload loads and validates the configuration again from the sources it
+// was loaded from, e.g. after its file or a secret file changed
+func (c *Config) Reload() (*Config, error) {
+	cfg, _, err := Load(c.args)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Changes lists the environment variables of the settings that differ
+// between two configurations, split between those applied by a reload
+// and those that need a restart
+func Changes(old, new *Config) (reloadable, restart []string) {
+	oldSettings, newSettings := old.settings(), new.settings()
+	for i, s := range newSettings {
+		if reflect.DeepEqual(s.value.Interface(), oldSettings[i].value.Interface()) {
+			continue
+		}
+		if s.reload {
+			reloadable = append(reloadable, s.env)
+		} else {
+			restart = append(restart, s.env)
+		}
+	}
+	return reloadable, restart
+}
+
+// Reloaded returns a copy of c with the reloadable settings of next. The
+// settings that need a restart keep the values the application runs
+// with, so that Changes keeps reporting them until a restart.
+func (c *Config) Reloaded(next *Config) *Config {
+	cfg := *c
+	nextSettings := next.settings()
+	for i, s := range cfg.settings() {
+		if s.reload {
+			s.value.Set(nextSettings[i].value)
+		}
+	}
+	return &cfg
+}
+
+// FlagName returns the name of the flag of an environment variable, e.g.
+// db-host for DB_HOST
+func FlagName(env string) string {
+	return strings.ToLower(strings.ReplaceAll(env, "_", "-"))
+}
+
+// loadFile overrides the configuration with a YAML file; unknown keys
+// are rejected so that typos do not go unnoticed
+func (c *Config) loadFile(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read configuration file: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse configuration file %s: %w", path, err)
+	}
+	return nil
+}
+
+// environment is the process environment over the variables of a .env
+// file, which are read again on every load so that a reload sees changes
+// to the file. The variables of the process take precedence.
+type environment map[string]string
+
+// readEnvironment reads the variables of a .env file, if it exists
+func readEnvironment(path string) (environment, error) {
+	dotEnv, err := godotenv.Read(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		slog.Debug(".env file not found, using environment variables")
+		return environment{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return dotEnv, nil
+}
+
+// get returns the value of a variable of the process, or else of the
+// .env file
+func (e environment) get(key string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return e[key]
+}
+
+// lookup returns the value of an environment variable, or the content
+// of the file named by its _FILE variant, e.g. JWT_SECRET_FILE for a
+// Docker or Kubernetes secret. Empty variables are treated as unset.
+func (e environment) lookup(key string) (string, bool, error) {
+	value := e.get(key)
+	path := e.get(key + "_FILE")
+	if path == "" {
+		return value, value != "", nil
+	}
+	if value != "" {
+		return "", false, fmt.Errorf("%s and %s_FILE are both set", key, key)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read %s_FILE: %w", key, err)
+	}
+	return strings.TrimRight(string(content), "\r\n"), true, nil
+}
+
+// settings lists the leaf fields of the configuration that have an env tag
+func (c *Config) settings() []setting {
+	var settings []setting
+	var walk func(value reflect.Value)
+	walk = func(value reflect.Value) {
+		for i := range value.NumField() {
+			field, fieldValue := value.Type().Field(i), value.Field(i)
+			if env := field.Tag.Get("env"); env != "" {
+				settings = append(
+					settings, setting{
+						env:    env,
+						reload: field.Tag.Get("reload") == "true",
+						value:  fieldValue,
+					},
+				)
+			} else if field.Type.Kind() == reflect.Struct {
+				walk(fieldValue)
+			}
+		}
+	}
+	walk(reflect.ValueOf(c).Elem())
+	return settings
+}
+
+// set parses a raw value into a field: strings, integers, booleans,
+// floats, durations such as "5s" and comma separated lists
+func set(value reflect.Value, raw string) error {
+	if value.Type() == reflect.TypeOf(time.Duration(0)) {
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		value.SetInt(int64(duration))
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Int:
+		number, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", raw)
+		}
+		value.SetInt(int64(number))
+	case reflect.Bool:
+		enabled, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", raw)
+		}
+		value.SetBool(enabled)
+	case reflect.Float64:
+		number, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", raw)
+		}
+		value.SetFloat(number)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		value.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported setting type %s", value.Type())
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/EngenMe/go-clean-architecture/infrastructure/config"
+)
+
+// secret is a valid JWT secret
+const secret = "0123456789abcdef0123456789abcdef"
+
+// unsetEnv unsets an environment variable for the duration of a test
+func unsetEnv(t *testing.T, key string) {
+	t.Helper()
+	t.Setenv(key, "")
+	os.Unsetenv(key)
+}
+
+// writeFile writes a file of the working directory
+func writeFile(t *testing.T, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+}
+
+func TestReloadReadsDotEnvAgain(t *testing.T) {
+	t.Chdir(t.TempDir())
+	for _, key := range []string{"JWT_SECRET", "JWT_SECRET_FILE", "LOG_LEVEL", config.FileEnv} {
+		unsetEnv(t, key)
+	}
+	writeFile(t, ".env", "JWT_SECRET="+secret+"\nLOG_LEVEL=warn\n")
+
+	cfg, _, err := config.Load(nil)
+	if err != nil {
+		t.Fatalf("failed to load the configuration: %v", err)
+	}
+	if cfg.JWT.Secret != secret || cfg.Log.Level != "warn" {
+		t.Fatalf("expected the settings of .env, got %q and %q", cfg.JWT.Secret, cfg.Log.Level)
+	}
+
+	// Edits of .env are picked up by a reload
+	writeFile(t, ".env", "JWT_SECRET="+secret+"\nLOG_LEVEL=error\n")
+	reloaded, err := cfg.Reload()
+	if err != nil {
+		t.Fatalf("failed to reload the configuration: %v", err)
+	}
+	if reloaded.Log.Level != "error" {
+		t.Errorf("expected the edited LOG_LEVEL, got %q", reloaded.Log.Level)
+	}
+	reloadable, restart := config.Changes(cfg, reloaded)
+	if !slices.Equal(reloadable, []string{"LOG_LEVEL"}) || len(restart) != 0 {
+		t.Errorf("expected only LOG_LEVEL to change, got %q and %q", reloadable, restart)
+	}
+
+	// The variables of the process take precedence over .env
+	t.Setenv("LOG_LEVEL", "debug")
+	reloaded, err = cfg.Reload()
+	if err != nil {
+		t.Fatalf("failed to reload the configuration: %v", err)
+	}
+	if reloaded.Log.Level != "debug" {
+		t.Errorf("expected LOG_LEVEL of the process, got %q", reloaded.Log.Level)
+	}
+
+	// .env may be removed
+	if err := os.Remove(".env"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("JWT_SECRET", secret)
+	if _, err := cfg.Reload(); err != nil {
+		t.Errorf("expected a reload without .env to succeed, got %v", err)
+	}
+}
+
+func TestReloadedKeepsRestartSettings(t *testing.T) {
+	cfg := config.Default()
+	next := config.Default()
+	next.Log.Level = "debug"
+	next.CORS.AllowedOrigins = []string{"https://example.com"}
+	next.Server.Port = 9090
+
+	reloaded := cfg.Reloaded(next)
+	if reloaded.Log.Level != "debug" || !slices.Equal(reloaded.CORS.AllowedOrigins, next.CORS.AllowedOrigins) {
+		t.Errorf("expected the reloadable settings of the new configuration, got %+v", reloaded)
+	}
+	if reloaded.Server.Port != cfg.Server.Port || cfg.Log.Level == "debug" {
+		t.Errorf("expected a copy keeping the port %d, got %+v", cfg.Server.Port, reloaded)
+	}
+
+	// The restart setting is still reported as changed
+	reloadable, restart := config.Changes(reloaded, next)
+	if len(reloadable) != 0 || !slices.Equal(restart, []string{"PORT"}) {
+		t.Errorf("expected only PORT to need a restart, got %q and %q", reloadable, restart)
+	}
+}
+
+func TestDotEnvSecretFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	for _, key := range []string{"JWT_SECRET", "JWT_SECRET_FILE", config.FileEnv} {
+		unsetEnv(t, key)
+	}
+	path := filepath.Join(dir, "jwt_secret")
+	writeFile(t, path, secret+"\n")
+	writeFile(t, ".env", "JWT_SECRET_FILE="+path+"\n")
+
+	cfg, _, err := config.Load(nil)
+	if err != nil {
+		t.Fatalf("failed to load the configuration: %v", err)
+	}
+	if cfg.JWT.Secret != secret {
+		t.Errorf("expected the secret of the file named in .env, got %q", cfg.JWT.Secret)
+	}
+}
@@ -0,0 +1,192 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/infrastructure/logging"
+)
+
+// MinSecretLength is the minimum length of JWT_SECRET in bytes, the size
+// of an HMAC-SHA256 key
+const MinSecretLength = 32
+
+// MinAPIKeyLength is the minimum length of the keys of RATE_LIMIT_API_KEYS
+const MinAPIKeyLength = 16
+
+// Validate reports every invalid setting at once, so that a misconfigured
+// server fails on startup rather than on the first request
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+	checkOneOf := func(env, value string, allowed ...string) {
+		check(
+			slices.Contains(allowed, value),
+			"%s must be one of %v, got %q",
+			env,
+			allowed,
+			value,
+		)
+	}
+	checkPositive := func(env string, value time.Duration) {
+		check(value > 0, "%s must be a positive duration, got %s", env, value)
+	}
+
+	check(validPort(c.Server.Port), "PORT must be between 1 and 65535, got %d", c.Server.Port)
+	check(
+		c.Server.ShutdownDrainDelay >= 0,
+		"SHUTDOWN_DRAIN_DELAY must not be negative, got %s",
+		c.Server.ShutdownDrainDelay,
+	)
+	check(
+		c.Server.ShutdownTimeout > 0,
+		"SHUTDOWN_TIMEOUT must be a positive duration, got %s",
+		c.Server.ShutdownTimeout,
+	)
+	for _, origin := range c.CORS.AllowedOrigins {
+		check(
+			validOrigin(origin),
+			"CORS_ALLOWED_ORIGINS entry %q must be * or scheme://host[:port]",
+			origin,
+		)
+	}
+	check(
+		c.Watch.Interval >= 0,
+		"CONFIG_WATCH_INTERVAL must not be negative, got %s",
+		c.Watch.Interval,
+	)
+	if _, err := logging.ParseLevel(c.Log.Level); err != nil {
+		errs = append(errs, fmt.Errorf("invalid LOG_LEVEL: %w", err))
+	}
+
+	checkOneOf("STORAGE", c.Storage, "database", "memory")
+	if c.Storage == "database" {
+		checkOneOf("DB_DRIVER", c.Database.Driver, "postgres", "mysql", "sqlite")
+		check(
+			c.Database.Port == 0 || validPort(c.Database.Port),
+			"DB_PORT must be between 1 and 65535, got %d",
+			c.Database.Port,
+		)
+	}
+
+	check(c.JWT.Secret != "", "JWT_SECRET is required")
+	check(
+		c.JWT.Secret == "" || len(c.JWT.Secret) >= MinSecretLength,
+		"JWT_SECRET must be at least %d bytes long",
+		MinSecretLength,
+	)
+	for _, secret := range c.JWT.PreviousSecrets {
+		check(
+			len(secret) >= MinSecretLength,
+			"JWT_PREVIOUS_SECRETS entries must be at least %d bytes long",
+			MinSecretLength,
+		)
+	}
+	check(
+		c.JWT.ExpirationHours > 0,
+		"JWT_EXPIRATION_HOURS must be positive, got %d",
+		c.JWT.ExpirationHours,
+	)
+
+	for _, publisher := range c.Outbox.Publishers {
+		checkOneOf("OUTBOX_PUBLISHERS entry", publisher, "log", "file", "mediatr")
+	}
+	checkPositive("OUTBOX_POLL_INTERVAL", c.Outbox.PollInterval)
+	check(
+		c.Outbox.BatchSize > 0,
+		"OUTBOX_BATCH_SIZE must be positive, got %d",
+		c.Outbox.BatchSize,
+	)
+	checkPositive("WEBHOOK_POLL_INTERVAL", c.Webhooks.PollInterval)
+	checkPositive("WEBHOOK_TIMEOUT", c.Webhooks.Timeout)
+	check(
+		c.Webhooks.MaxAttempts > 0,
+		"WEBHOOK_MAX_ATTEMPTS must be positive, got %d",
+		c.Webhooks.MaxAttempts,
+	)
+	checkPositive("IDEMPOTENCY_TTL", c.Idempotency.TTL)
+
+	checkOneOf("RATE_LIMIT_STORE", c.RateLimit.Store, "memory", "database")
+	check(
+		c.RateLimit.Store != "database" || c.Storage == "database",
+		"RATE_LIMIT_STORE=database requires STORAGE=database",
+	)
+	apiKeys := make(map[string]bool, len(c.RateLimit.APIKeys))
+	for _, entry := range c.RateLimit.APIKeys {
+		client, key, ok := strings.Cut(entry, ":")
+		check(
+			ok && client != "" && len(key) >= MinAPIKeyLength,
+			"RATE_LIMIT_API_KEYS entries must be \"client:key\" with keys of at least %d bytes",
+			MinAPIKeyLength,
+		)
+		check(!apiKeys[key], "RATE_LIMIT_API_KEYS entries must have different keys")
+		apiKeys[key] = true
+	}
+
+	if c.Access.UserCreate == "public" {
+		errs = append(
+			errs,
+			errors.New("USER_CREATE_ACCESS must not be public: anonymous callers must use /auth/signup"),
+		)
+	} else {
+		checkOneOf("USER_CREATE_ACCESS", c.Access.UserCreate, "authenticated", "admin")
+	}
+	checkOneOf("USER_LOOKUP_ACCESS", c.Access.UserLookup, "public", "authenticated", "admin")
+	check(
+		c.Access.UserLookup != "public" || c.RateLimit.Enabled,
+		"USER_LOOKUP_ACCESS=public requires RATE_LIMIT_ENABLED=true",
+	)
+
+	checkOneOf("TRACING_EXPORTER", c.Tracing.Exporter, "none", "stdout", "otlp")
+	check(
+		c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1,
+		"TRACING_SAMPLE_RATIO must be between 0 and 1, got %g",
+		c.Tracing.SampleRatio,
+	)
+
+	checkOneOf("MAILER", c.Mail.Mailer, "log", "smtp", "none")
+	if c.Mail.Mailer == "smtp" {
+		check(c.Mail.SMTP.Host != "", "SMTP_HOST is required with MAILER=smtp")
+		check(c.Mail.SMTP.From != "", "SMTP_FROM is required with MAILER=smtp")
+		check(
+			validPort(c.Mail.SMTP.Port),
+			"SMTP_PORT must be between 1 and 65535, got %d",
+			c.Mail.SMTP.Port,
+		)
+	}
+
+	checkPositive("HEALTH_CHECK_TIMEOUT", c.Health.CheckTimeout)
+	checkPositive("HEALTH_OUTBOX_MAX_LAG", c.Health.OutboxMaxLag)
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return nil
+}
+
+// validOrigin reports whether origin is * or an origin such as
+// https://app.example.com, without a path
+func validOrigin(origin string) bool {
+	if origin == "*" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil &&
+		(u.Scheme == "http" || u.Scheme == "https") &&
+		u.Host != "" &&
+		u.Path == "" &&
+		u.RawQuery == ""
+}
+
+// validPort reports whether port is a valid TCP port
+func validPort(port int) bool {
+	return port > 0 && port <= 65535
+}
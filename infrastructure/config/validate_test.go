@@ -0,0 +1,84 @@
+package config_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/EngenMe/go-clean-architecture/infrastructure/config"
+)
+
+// validConfig returns the default configuration with a JWT secret
+func validConfig() *config.Config {
+	cfg := config.Default()
+	cfg.JWT.Secret = secret
+	cfg.Log.Level = "info"
+	return cfg
+}
+
+func TestValidateUserAccess(t *testing.T) {
+	tests := []struct {
+		name        string
+		userCreate  string
+		userLookup  string
+		rateLimited bool
+		wantErr     string
+	}{
+		{"admin only", "admin", "admin", true, ""},
+		{"authenticated", "authenticated", "authenticated", true, ""},
+		{"public lookup", "admin", "public", true, ""},
+		{"public lookup without rate limit", "admin", "public", false, "USER_LOOKUP_ACCESS=public requires RATE_LIMIT_ENABLED=true"},
+		{"admin lookup without rate limit", "admin", "admin", false, ""},
+		{"public creation", "public", "admin", true, "USER_CREATE_ACCESS must not be public"},
+		{"unknown creation access", "bogus", "admin", true, `USER_CREATE_ACCESS must be one of [authenticated admin], got "bogus"`},
+		{"unknown lookup access", "admin", "bogus", true, `USER_LOOKUP_ACCESS must be one of [public authenticated admin], got "bogus"`},
+		{"empty creation access", "", "admin", true, "USER_CREATE_ACCESS must be one of"},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				cfg := validConfig()
+				cfg.Access.UserCreate = tt.userCreate
+				cfg.Access.UserLookup = tt.userLookup
+				cfg.RateLimit.Enabled = tt.rateLimited
+
+				err := cfg.Validate()
+				switch {
+				case tt.wantErr == "" && err != nil:
+					t.Errorf("expected a valid configuration, got %v", err)
+				case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+					t.Errorf("expected an error containing %q, got %v", tt.wantErr, err)
+				}
+			},
+		)
+	}
+}
+
+func TestValidateAPIKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		apiKeys []string
+		wantErr string
+	}{
+		{"none", nil, ""},
+		{"clients", []string{"partner:0123456789abcdef", "mobile:fedcba9876543210"}, ""},
+		{"no client", []string{":0123456789abcdef"}, "RATE_LIMIT_API_KEYS entries must be"},
+		{"short key", []string{"partner:short"}, "RATE_LIMIT_API_KEYS entries must be"},
+		{"same key", []string{"a:0123456789abcdef", "b:0123456789abcdef"}, "different keys"},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				cfg := validConfig()
+				cfg.RateLimit.APIKeys = tt.apiKeys
+
+				err := cfg.Validate()
+				switch {
+				case tt.wantErr == "" && err != nil:
+					t.Errorf("expected a valid configuration, got %v", err)
+				case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+					t.Errorf("expected an error containing %q, got %v", tt.wantErr, err)
+				}
+			},
+		)
+	}
+}
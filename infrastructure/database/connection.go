@@ -2,42 +2,153 @@ package database
 
 import (
 	"fmt"
-	"log"
-	"os"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+	"time"
 
-	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/config"
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
-// NewDatabaseConnection creates a new PostgreSQL database connection
-func NewDatabaseConnection() (*gorm.DB, error) {
-	dsn := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		os.Getenv("DB_HOST"),
-		os.Getenv("DB_PORT"),
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-		os.Getenv("DB_NAME"),
-		os.Getenv("DB_SSL_MODE"),
-	)
+// Supported database drivers for DB_DRIVER
+const (
+	DriverPostgres = "postgres"
+	DriverMySQL    = "mysql"
+	DriverSQLite   = "sqlite"
+)
 
-	config := &gorm.Config{}
-	if os.Getenv("ENV") == "development" {
-		config.Logger = logger.Default.LogMode(logger.Info)
+// sqliteMemory is the DB_NAME value that selects an in-memory SQLite database
+const sqliteMemory = ":memory:"
+
+// NewDatabaseConnection creates a new database connection for the driver
+// of the configuration (postgres, mysql or sqlite). GORM timestamps rows
+// with clk. The schema is not touched; run the Migrator to apply
+// migrations.
+func NewDatabaseConnection(cfg config.DatabaseConfig, clk clock.Clock) (*gorm.DB, error) {
+	dialector, err := newDialector(cfg)
+	if err != nil {
+		return nil, err
 	}
 
-	db, err := gorm.Open(postgres.Open(dsn), config)
+	db, err := gorm.Open(
+		dialector,
+		&gorm.Config{
+			Logger: newSlogLogger(),
+			NowFunc: func() time.Time {
+				return clk.Now().UTC()
+			},
+		},
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	// Auto-migrate schemas
-	if err := db.AutoMigrate(&entities.User{}); err != nil {
-		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	if cfg.Driver == DriverSQLite {
+		// SQLite allows a single writer, and every connection to an
+		// in-memory database would otherwise see its own empty database
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure database: %w", err)
+		}
+		sqlDB.SetMaxOpenConns(1)
 	}
 
-	log.Println("Database connection established successfully")
+	slog.Info("Database connection established", slog.String("driver", cfg.Driver))
 	return db, nil
 }
+
+// newDialector builds the GORM dialector and DSN of the configured driver
+func newDialector(cfg config.DatabaseConfig) (gorm.Dialector, error) {
+	switch cfg.Driver {
+	case DriverPostgres:
+		return postgres.Open(postgresDSN(cfg)), nil
+	case DriverMySQL:
+		return mysql.Open(mysqlDSN(cfg)), nil
+	case DriverSQLite:
+		return sqlite.Open(sqliteDSN(cfg)), nil
+	default:
+		return nil, fmt.Errorf(
+			"unsupported DB_DRIVER %q (expected %s, %s or %s)",
+			cfg.Driver,
+			DriverPostgres,
+			DriverMySQL,
+			DriverSQLite,
+		)
+	}
+}
+
+// postgresDSN builds a PostgreSQL DSN, leaving out the unset settings so
+// that libpq defaults apply
+func postgresDSN(cfg config.DatabaseConfig) string {
+	settings := []struct{ key, value string }{
+		{"host", cfg.Host},
+		{"port", portString(cfg.Port)},
+		{"user", cfg.User},
+		{"password", cfg.Password},
+		{"dbname", cfg.Name},
+		{"sslmode", cfg.SSLMode},
+	}
+
+	var dsn []string
+	for _, setting := range settings {
+		if setting.value != "" {
+			dsn = append(dsn, fmt.Sprintf("%s=%s", setting.key, setting.value))
+		}
+	}
+	return strings.Join(dsn, " ")
+}
+
+// mysqlDSN builds a MySQL DSN
+func mysqlDSN(cfg config.DatabaseConfig) string {
+	port := portString(cfg.Port)
+	if port == "" {
+		port = "3306"
+	}
+
+	dsn := mysqldriver.NewConfig()
+	dsn.User = cfg.User
+	dsn.Passwd = cfg.Password
+	dsn.Net = "tcp"
+	dsn.Addr = net.JoinHostPort(cfg.Host, port)
+	dsn.DBName = cfg.Name
+	dsn.ParseTime = true
+	dsn.Loc = time.UTC
+	dsn.Params = map[string]string{"charset": "utf8mb4"}
+	// Migration scripts contain several statements
+	dsn.MultiStatements = true
+
+	// Map the PostgreSQL style DB_SSL_MODE onto the MySQL tls parameter
+	switch cfg.SSLMode {
+	case "require":
+		dsn.TLSConfig = "skip-verify"
+	case "verify-ca", "verify-full":
+		dsn.TLSConfig = "true"
+	}
+
+	return dsn.FormatDSN()
+}
+
+// sqliteDSN builds a SQLite DSN where the database name is the file path,
+// or ":memory:" (the default) for an in-memory database
+func sqliteDSN(cfg config.DatabaseConfig) string {
+	path := cfg.Name
+	if path == "" || path == sqliteMemory {
+		return "file::memory:?cache=shared&_foreign_keys=1"
+	}
+	return fmt.Sprintf("file:%s?_foreign_keys=1&_busy_timeout=5000", path)
+}
+
+// portString formats a port, or returns "" for the default port 0
+func portString(port int) string {
+	if port == 0 {
+		return ""
+	}
+	return strconv.Itoa(port)
+}
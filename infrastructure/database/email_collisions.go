@@ -0,0 +1,103 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"gorm.io/gorm"
+)
+
+// CollidingUser is a user sharing its email with others once normalized
+type CollidingUser struct {
+	ID    uint
+	Email string // As stored
+}
+
+// EmailCollision lists the users whose emails are the same once normalized
+type EmailCollision struct {
+	Email string // Normalized email
+	Users []CollidingUser
+}
+
+// EmailCollisionError reports the users that must be merged or given
+// another email before emails can be unique regardless of case
+type EmailCollisionError struct {
+	Collisions []EmailCollision
+}
+
+// Error lists every collision, one per line
+func (e *EmailCollisionError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "emails shared by several users once normalized (%d):", len(e.Collisions))
+	for _, collision := range e.Collisions {
+		fmt.Fprintf(&b, "\n  %s:", collision.Email)
+		for _, user := range collision.Users {
+			fmt.Fprintf(&b, " user %d (%q)", user.ID, user.Email)
+		}
+	}
+	return b.String()
+}
+
+// normalizeUserEmails prepares migration 009: it stores every email as
+// entities.NewEmail normalizes it, unless some users collide once their
+// emails are normalized, which it reports as an *EmailCollisionError
+func normalizeUserEmails(ctx context.Context, tx *gorm.DB, dryRun bool) error {
+	var users []CollidingUser
+	if err := tx.WithContext(ctx).
+		Table("users").
+		Select("id, email").
+		Order("id").
+		Scan(&users).Error; err != nil {
+		return fmt.Errorf("failed to read user emails: %w", err)
+	}
+
+	// Group the users by normalized email, in the order of their IDs
+	var emails []string
+	byEmail := map[string][]CollidingUser{}
+	for _, user := range users {
+		email := normalizeStoredEmail(user.Email)
+		if _, ok := byEmail[email]; !ok {
+			emails = append(emails, email)
+		}
+		byEmail[email] = append(byEmail[email], user)
+	}
+
+	var collisions []EmailCollision
+	for _, email := range emails {
+		if len(byEmail[email]) > 1 {
+			collisions = append(collisions, EmailCollision{Email: email, Users: byEmail[email]})
+		}
+	}
+	if len(collisions) > 0 {
+		return &EmailCollisionError{Collisions: collisions}
+	}
+	if dryRun {
+		return nil
+	}
+
+	for _, user := range users {
+		email := normalizeStoredEmail(user.Email)
+		if email == user.Email {
+			continue
+		}
+		if err := tx.WithContext(ctx).
+			Table("users").
+			Where("id = ?", user.ID).
+			Update("email", email).Error; err != nil {
+			return fmt.Errorf("failed to normalize the email of user %d: %w", user.ID, err)
+		}
+	}
+	return nil
+}
+
+// normalizeStoredEmail normalizes an email stored before emails were
+// validated; invalid ones are only trimmed and lowercased
+func normalizeStoredEmail(stored string) string {
+	email, err := entities.NewEmail(stored)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(stored))
+	}
+	return email.String()
+}
@@ -0,0 +1,144 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mattn/go-sqlite3"
+)
+
+// PostgreSQL SQLSTATE codes translated into domain errors
+const (
+	pgUniqueViolation      = "23505"
+	pgForeignKeyViolation  = "23503"
+	pgNotNullViolation     = "23502"
+	pgCheckViolation       = "23514"
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+)
+
+// MySQL server error numbers translated into domain errors
+const (
+	mysqlDuplicateEntry     = 1062
+	mysqlRowIsReferenced    = 1451
+	mysqlNoReferencedRow    = 1452
+	mysqlBadNull            = 1048
+	mysqlCheckViolated      = 3819
+	mysqlLockWaitTimeout    = 1205
+	mysqlDeadlock           = 1213
+	mysqlDuplicateEntryLong = 1586
+)
+
+// TranslateError maps PostgreSQL, MySQL and SQLite driver errors to domain
+// errors so that constraint violations surface as client errors instead of 500s.
+// Errors that are not recognized are returned unchanged.
+func TranslateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return translatePostgresError(pgErr)
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return translateMySQLError(mysqlErr)
+	}
+
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return translateSQLiteError(sqliteErr)
+	}
+
+	return err
+}
+
+// translatePostgresError maps a PostgreSQL error to a domain error by SQLSTATE
+func translatePostgresError(pgErr *pgconn.PgError) error {
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		if isEmailConstraint(pgErr.ConstraintName, pgErr.Detail) {
+			return utils.ErrEmailAlreadyExists
+		}
+		return utils.ErrConflict
+	case pgForeignKeyViolation:
+		return fmt.Errorf(
+			"%w: %s",
+			utils.ErrReferenceViolation,
+			pgErr.ConstraintName,
+		)
+	case pgNotNullViolation:
+		return fmt.Errorf(
+			"%w: %s is required",
+			utils.ErrInvalidInput,
+			pgErr.ColumnName,
+		)
+	case pgCheckViolation:
+		return fmt.Errorf(
+			"%w: violates %s",
+			utils.ErrInvalidInput,
+			pgErr.ConstraintName,
+		)
+	case pgSerializationFailure, pgDeadlockDetected:
+		return utils.ErrConcurrentUpdate
+	default:
+		return pgErr
+	}
+}
+
+// translateMySQLError maps a MySQL error to a domain error by error number
+func translateMySQLError(mysqlErr *mysql.MySQLError) error {
+	switch mysqlErr.Number {
+	case mysqlDuplicateEntry, mysqlDuplicateEntryLong:
+		// The message quotes the duplicate value before the key name,
+		// so only the key name is inspected
+		_, key, _ := strings.Cut(mysqlErr.Message, " for key ")
+		if isEmailConstraint(key, "") {
+			return utils.ErrEmailAlreadyExists
+		}
+		return utils.ErrConflict
+	case mysqlRowIsReferenced, mysqlNoReferencedRow:
+		return utils.ErrReferenceViolation
+	case mysqlBadNull, mysqlCheckViolated:
+		return fmt.Errorf("%w: %s", utils.ErrInvalidInput, mysqlErr.Message)
+	case mysqlLockWaitTimeout, mysqlDeadlock:
+		return utils.ErrConcurrentUpdate
+	default:
+		return mysqlErr
+	}
+}
+
+// translateSQLiteError maps a SQLite error to a domain error by result code
+func translateSQLiteError(sqliteErr sqlite3.Error) error {
+	switch sqliteErr.ExtendedCode {
+	case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+		if isEmailConstraint(sqliteErr.Error(), "") {
+			return utils.ErrEmailAlreadyExists
+		}
+		return utils.ErrConflict
+	case sqlite3.ErrConstraintForeignKey:
+		return utils.ErrReferenceViolation
+	case sqlite3.ErrConstraintNotNull, sqlite3.ErrConstraintCheck:
+		return fmt.Errorf("%w: %s", utils.ErrInvalidInput, sqliteErr.Error())
+	}
+
+	switch sqliteErr.Code {
+	case sqlite3.ErrBusy, sqlite3.ErrLocked:
+		return utils.ErrConcurrentUpdate
+	default:
+		return sqliteErr
+	}
+}
+
+// isEmailConstraint reports whether a unique violation concerns the email
+// column, given the violated constraint (or driver message) and detail
+func isEmailConstraint(constraint, detail string) bool {
+	return strings.Contains(constraint, "email") ||
+		strings.HasPrefix(detail, "Key (email)")
+}
@@ -23,7 +23,7 @@ func (r *GenericPostgresRepository[T]) Create(
 	ctx context.Context,
 	entity *T,
 ) error {
-	return r.db.WithContext(ctx).Create(entity).Error
+	return TranslateError(Conn(ctx, r.db).Create(entity).Error)
 }
 
 // FindByID retrieves an entity by ID
@@ -32,12 +32,12 @@ func (r *GenericPostgresRepository[T]) FindByID(
 	id uint,
 ) (*T, error) {
 	var entity T
-	result := r.db.WithContext(ctx).First(&entity, id)
+	result := Conn(ctx, r.db).First(&entity, id)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, nil // No entity found
 		}
-		return nil, result.Error
+		return nil, TranslateError(result.Error)
 	}
 	return &entity, nil
 }
@@ -48,9 +48,9 @@ func (r *GenericPostgresRepository[T]) FindAll(ctx context.Context) (
 	error,
 ) {
 	var entities []*T
-	result := r.db.WithContext(ctx).Find(&entities)
+	result := Conn(ctx, r.db).Find(&entities)
 	if result.Error != nil {
-		return nil, result.Error
+		return nil, TranslateError(result.Error)
 	}
 	return entities, nil
 }
@@ -60,7 +60,7 @@ func (r *GenericPostgresRepository[T]) Update(
 	ctx context.Context,
 	entity *T,
 ) error {
-	return r.db.WithContext(ctx).Save(entity).Error
+	return TranslateError(Conn(ctx, r.db).Save(entity).Error)
 }
 
 // Delete removes an entity by ID
@@ -69,7 +69,7 @@ func (r *GenericPostgresRepository[T]) Delete(
 	id uint,
 ) error {
 	var entity T
-	return r.db.WithContext(ctx).Delete(&entity, id).Error
+	return TranslateError(Conn(ctx, r.db).Delete(&entity, id).Error)
 }
 
 // GetDB returns the database connection
@@ -0,0 +1,110 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormIdempotencyStore implements IdempotencyStore interface using GORM
+type GormIdempotencyStore struct {
+	db *gorm.DB
+}
+
+// NewGormIdempotencyStore creates a new GORM idempotency store
+func NewGormIdempotencyStore(db *gorm.DB) repositories.IdempotencyStore {
+	return &GormIdempotencyStore{db: db}
+}
+
+// Reserve claims key with an insert that only one concurrent request can
+// win, replacing the record first if it has expired
+func (s *GormIdempotencyStore) Reserve(
+	ctx context.Context,
+	key string,
+	requestHash string,
+	now time.Time,
+	ttl time.Duration,
+) (*entities.IdempotencyRecord, bool, error) {
+	now = now.UTC()
+
+	// Free the key if its previous record has expired
+	if err := Conn(ctx, s.db).
+		Where("idempotency_key = ? AND expires_at <= ?", key, now).
+		Delete(&entities.IdempotencyRecord{}).Error; err != nil {
+		return nil, false, TranslateError(err)
+	}
+
+	record := entities.IdempotencyRecord{
+		Key:          key,
+		RequestHash:  requestHash,
+		ResponseBody: []byte{},
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(ttl),
+	}
+	result := Conn(ctx, s.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&record)
+	if result.Error != nil {
+		return nil, false, TranslateError(result.Error)
+	}
+	if result.RowsAffected == 1 {
+		return &record, true, nil
+	}
+
+	var existing entities.IdempotencyRecord
+	err := Conn(ctx, s.db).Where("idempotency_key = ?", key).First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Released in the meantime: let the client retry
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, TranslateError(err)
+	}
+	return &existing, false, nil
+}
+
+// Complete stores the response of the request that reserved key
+func (s *GormIdempotencyStore) Complete(
+	ctx context.Context,
+	key string,
+	statusCode int,
+	contentType string,
+	body []byte,
+) error {
+	return TranslateError(
+		Conn(ctx, s.db).Model(&entities.IdempotencyRecord{}).
+			Where("idempotency_key = ?", key).
+			Updates(
+				map[string]any{
+					"status_code":   statusCode,
+					"content_type":  contentType,
+					"response_body": body,
+				},
+			).Error,
+	)
+}
+
+// Release removes a reservation so that the request can be retried
+func (s *GormIdempotencyStore) Release(ctx context.Context, key string) error {
+	return TranslateError(
+		Conn(ctx, s.db).
+			Where("idempotency_key = ?", key).
+			Delete(&entities.IdempotencyRecord{}).Error,
+	)
+}
+
+// DeleteExpired removes the records expired at now
+func (s *GormIdempotencyStore) DeleteExpired(
+	ctx context.Context,
+	now time.Time,
+) (int64, error) {
+	result := Conn(ctx, s.db).
+		Where("expires_at <= ?", now.UTC()).
+		Delete(&entities.IdempotencyRecord{})
+	return result.RowsAffected, TranslateError(result.Error)
+}
@@ -0,0 +1,110 @@
+package database_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/infrastructure/database"
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+)
+
+// epoch is the time the tests of the stores start at
+var epoch = time.Date(2030, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+// newIdempotencyStore creates a store on a migrated SQLite database
+func newIdempotencyStore(t *testing.T) repositories.IdempotencyStore {
+	t.Helper()
+	db, migrator := newMigrator(t)
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	return database.NewGormIdempotencyStore(db)
+}
+
+func TestGormIdempotencyStoreLifecycle(t *testing.T) {
+	ctx := context.Background()
+	store := newIdempotencyStore(t)
+
+	record, reserved, err := store.Reserve(ctx, "key", "hash", epoch, time.Hour)
+	if err != nil || !reserved || record.IsCompleted() {
+		t.Fatalf("expected the key to be reserved, got %+v, %t (%v)", record, reserved, err)
+	}
+
+	// Until completed, the key is reported in progress with its first hash
+	record, reserved, err = store.Reserve(ctx, "key", "other", epoch, time.Hour)
+	if err != nil || reserved || record.IsCompleted() || record.RequestHash != "hash" {
+		t.Fatalf("expected the key to be in progress, got %+v, %t (%v)", record, reserved, err)
+	}
+
+	if err := store.Complete(ctx, "key", 201, "application/json", []byte(`{"id":1}`)); err != nil {
+		t.Fatalf("failed to complete: %v", err)
+	}
+	record, reserved, err = store.Reserve(ctx, "key", "hash", epoch.Add(time.Minute), time.Hour)
+	if err != nil ||
+		reserved ||
+		record.StatusCode != 201 ||
+		record.ContentType != "application/json" ||
+		string(record.ResponseBody) != `{"id":1}` ||
+		!record.ExpiresAt.Equal(epoch.Add(time.Hour)) {
+		t.Fatalf("expected the stored response, got %+v, %t (%v)", record, reserved, err)
+	}
+
+	// Released keys can be reserved again
+	if err := store.Release(ctx, "key"); err != nil {
+		t.Fatalf("failed to release: %v", err)
+	}
+	if _, reserved, err := store.Reserve(ctx, "key", "other", epoch, time.Hour); err != nil || !reserved {
+		t.Errorf("expected a released key to be reserved again, got %t (%v)", reserved, err)
+	}
+}
+
+func TestGormIdempotencyStoreExpiry(t *testing.T) {
+	ctx := context.Background()
+	store := newIdempotencyStore(t)
+	store.Reserve(ctx, "old", "hash", epoch, time.Hour)
+	store.Reserve(ctx, "new", "hash", epoch.Add(30*time.Minute), time.Hour)
+
+	// A key expires at the end of its TTL
+	if _, reserved, _ := store.Reserve(ctx, "old", "other", epoch.Add(time.Hour-time.Second), time.Hour); reserved {
+		t.Error("expected the key to be held until it expires")
+	}
+	record, reserved, err := store.Reserve(ctx, "old", "other", epoch.Add(time.Hour), time.Hour)
+	if err != nil || !reserved || record.RequestHash != "other" {
+		t.Errorf("expected an expired key to be reserved again, got %+v, %t (%v)", record, reserved, err)
+	}
+
+	deleted, err := store.DeleteExpired(ctx, epoch.Add(90*time.Minute))
+	if err != nil || deleted != 1 {
+		t.Errorf("expected the expired record to be deleted, got %d (%v)", deleted, err)
+	}
+	if _, reserved, _ := store.Reserve(ctx, "old", "other", epoch.Add(90*time.Minute), time.Hour); reserved {
+		t.Error("expected the unexpired record to be kept")
+	}
+}
+
+func TestGormIdempotencyStoreConcurrentReserve(t *testing.T) {
+	store := newIdempotencyStore(t)
+
+	var wg sync.WaitGroup
+	var winners atomic.Int32
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, reserved, err := store.Reserve(context.Background(), "key", "hash", epoch, time.Hour)
+			if err != nil {
+				t.Errorf("failed to reserve: %v", err)
+			}
+			if reserved {
+				winners.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := winners.Load(); got != 1 {
+		t.Errorf("expected a single reservation, got %d", got)
+	}
+}
@@ -0,0 +1,109 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// slowQueryThreshold is the duration above which queries are logged as warnings
+const slowQueryThreshold = 200 * time.Millisecond
+
+// slogLogger implements GORM's logger interface on top of the default
+// slog logger, so that queries carry the request ID of their context. SQL
+// statements are logged at debug level without their parameters, which
+// may hold password hashes and other sensitive values.
+type slogLogger struct {
+	level logger.LogLevel
+}
+
+// newSlogLogger creates a GORM logger writing to the default slog logger
+func newSlogLogger() logger.Interface {
+	return &slogLogger{level: logger.Info}
+}
+
+// LogMode returns a logger with the given GORM log level
+func (l *slogLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return &slogLogger{level: level}
+}
+
+// Info logs a GORM message at info level
+func (l *slogLogger) Info(ctx context.Context, format string, args ...interface{}) {
+	if l.level >= logger.Info {
+		slog.InfoContext(ctx, fmt.Sprintf(format, args...))
+	}
+}
+
+// Warn logs a GORM message at warn level
+func (l *slogLogger) Warn(ctx context.Context, format string, args ...interface{}) {
+	if l.level >= logger.Warn {
+		slog.WarnContext(ctx, fmt.Sprintf(format, args...))
+	}
+}
+
+// Error logs a GORM message at error level
+func (l *slogLogger) Error(ctx context.Context, format string, args ...interface{}) {
+	if l.level >= logger.Error {
+		slog.ErrorContext(ctx, fmt.Sprintf(format, args...))
+	}
+}
+
+// Trace logs a query: failed queries as errors, slow queries as warnings
+// and the others at debug level
+func (l *slogLogger) Trace(
+	ctx context.Context,
+	begin time.Time,
+	fc func() (sql string, rowsAffected int64),
+	err error,
+) {
+	if l.level <= logger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil && l.level >= logger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		sql, rows := fc()
+		slog.ErrorContext(
+			ctx,
+			"Query failed",
+			slog.String("sql", sql),
+			slog.Int64("rows", rows),
+			slog.Duration("duration", elapsed),
+			slog.String("error", err.Error()),
+		)
+	case elapsed > slowQueryThreshold && l.level >= logger.Warn:
+		sql, rows := fc()
+		slog.WarnContext(
+			ctx,
+			"Slow query",
+			slog.String("sql", sql),
+			slog.Int64("rows", rows),
+			slog.Duration("duration", elapsed),
+		)
+	case l.level >= logger.Info && slog.Default().Enabled(ctx, slog.LevelDebug):
+		sql, rows := fc()
+		slog.DebugContext(
+			ctx,
+			"Query",
+			slog.String("sql", sql),
+			slog.Int64("rows", rows),
+			slog.Duration("duration", elapsed),
+		)
+	}
+}
+
+// ParamsFilter drops the query parameters, so that logged statements keep
+// their placeholders instead of the values
+func (l *slogLogger) ParamsFilter(
+	_ context.Context,
+	sql string,
+	_ ...interface{},
+) (string, []interface{}) {
+	return sql, nil
+}
@@ -0,0 +1,11 @@
+// Package migrations embeds the versioned SQL migrations for every
+// supported database driver. Files are named
+// <version>_<name>.<up|down>.sql inside a directory per driver.
+package migrations
+
+import "embed"
+
+// FS holds the migration files, keyed by driver directory
+//
+//go:embed postgres/*.sql mysql/*.sql sqlite/*.sql
+var FS embed.FS
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS outbox_messages (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    event_id VARCHAR(64) NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    aggregate_id BIGINT UNSIGNED NOT NULL,
+    payload TEXT NOT NULL,
+    occurred_at DATETIME(3) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at DATETIME(3) NOT NULL,
+    last_error TEXT,
+    delivered_at DATETIME(3) NULL,
+    created_at DATETIME(3) DEFAULT CURRENT_TIMESTAMP(3),
+    CONSTRAINT uni_outbox_messages_event_id UNIQUE (event_id),
+    INDEX idx_outbox_messages_pending (delivered_at, next_attempt_at)
+);
@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    url TEXT NOT NULL,
+    event_types TEXT NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at DATETIME(3) DEFAULT CURRENT_TIMESTAMP(3),
+    updated_at DATETIME(3) DEFAULT CURRENT_TIMESTAMP(3)
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    subscription_id BIGINT UNSIGNED NOT NULL,
+    event_id VARCHAR(64) NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    payload TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at DATETIME(3) NOT NULL,
+    response_status INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    delivered_at DATETIME(3) NULL,
+    created_at DATETIME(3) DEFAULT CURRENT_TIMESTAMP(3),
+    updated_at DATETIME(3) DEFAULT CURRENT_TIMESTAMP(3),
+    CONSTRAINT uni_webhook_deliveries_event UNIQUE (subscription_id, event_id),
+    CONSTRAINT fk_webhook_deliveries_subscription FOREIGN KEY (subscription_id)
+        REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    INDEX idx_webhook_deliveries_pending (status, next_attempt_at)
+);
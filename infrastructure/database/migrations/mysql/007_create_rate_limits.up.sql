@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS rate_limits (
+    rate_limit_key VARCHAR(255) PRIMARY KEY,
+    state BLOB NOT NULL,
+    expires_at DATETIME(3) NOT NULL,
+    INDEX idx_rate_limits_expires_at (expires_at)
+);
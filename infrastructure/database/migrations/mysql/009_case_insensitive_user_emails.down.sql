@@ -0,0 +1,2 @@
+-- Normalized emails are kept
+DROP INDEX uni_users_lower_email ON users;
@@ -0,0 +1,5 @@
+-- Emails identify users regardless of case. Before this script runs, the
+-- migrator normalizes the stored emails, and refuses to go on while some
+-- users share an email once normalized (see `migrate check`). Functional
+-- indexes require MySQL 8.0.13 or later.
+CREATE UNIQUE INDEX uni_users_lower_email ON users ((LOWER(email)));
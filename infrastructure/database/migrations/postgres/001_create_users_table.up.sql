@@ -2,12 +2,12 @@ CREATE TABLE IF NOT EXISTS users (
     id SERIAL PRIMARY KEY,
     email VARCHAR(255) NOT NULL,
     password VARCHAR(255) NOT NULL,
-    first_name VARCHAR(100),
-    last_name VARCHAR(100),
+    first_name VARCHAR(100) NOT NULL,
+    last_name VARCHAR(100) NOT NULL,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     CONSTRAINT uni_users_email UNIQUE (email)
 );
 
 -- Create index on email
-CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
@@ -0,0 +1,2 @@
+ALTER TABLE users ALTER COLUMN first_name DROP NOT NULL;
+ALTER TABLE users ALTER COLUMN last_name DROP NOT NULL;
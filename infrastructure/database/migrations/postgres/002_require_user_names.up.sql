@@ -0,0 +1,7 @@
+-- Databases initialized from the original 001 script have nullable names,
+-- while the User entity requires them. Align the schema with the entity.
+UPDATE users SET first_name = '' WHERE first_name IS NULL;
+UPDATE users SET last_name = '' WHERE last_name IS NULL;
+
+ALTER TABLE users ALTER COLUMN first_name SET NOT NULL;
+ALTER TABLE users ALTER COLUMN last_name SET NOT NULL;
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS rate_limits (
+    rate_limit_key VARCHAR(255) PRIMARY KEY,
+    state BYTEA NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_limits_expires_at ON rate_limits(expires_at);
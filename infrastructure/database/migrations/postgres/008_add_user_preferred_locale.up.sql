@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS preferred_locale VARCHAR(10) NOT NULL DEFAULT 'en';
@@ -0,0 +1,4 @@
+-- Normalized emails are kept
+CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
+
+DROP INDEX IF EXISTS uni_users_lower_email;
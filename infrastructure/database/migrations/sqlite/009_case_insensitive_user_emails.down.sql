@@ -0,0 +1,2 @@
+-- Normalized emails are kept
+DROP INDEX IF EXISTS uni_users_lower_email;
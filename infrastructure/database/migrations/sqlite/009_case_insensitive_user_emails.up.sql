@@ -0,0 +1,4 @@
+-- Emails identify users regardless of case. Before this script runs, the
+-- migrator normalizes the stored emails, and refuses to go on while some
+-- users share an email once normalized (see `migrate check`).
+CREATE UNIQUE INDEX IF NOT EXISTS uni_users_lower_email ON users (LOWER(email));
@@ -0,0 +1,491 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/infrastructure/database/migrations"
+	"gorm.io/gorm"
+)
+
+// migrationLockKey identifies the advisory lock held while migrating
+const migrationLockKey = 7246534117
+
+// migrationLockName is the MySQL named lock held while migrating
+const migrationLockName = "schema_migrations"
+
+// migrationFilePattern matches <version>_<name>.<up|down>.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Migration is a versioned schema change
+type Migration struct {
+	Version uint
+	Name    string
+	UpSQL   string
+	DownSQL string
+	prepare migrationHook // Prepares the data before UpSQL runs, if set
+}
+
+// migrationHook prepares the data of the database for a migration that SQL
+// alone cannot. It runs in the transaction of the migration, before its up
+// script, and may refuse the migration. With dryRun set, it only checks
+// that the migration can be applied and changes nothing.
+type migrationHook func(ctx context.Context, tx *gorm.DB, dryRun bool) error
+
+// migrationHooks are the hooks of the migrations that need one, by version
+var migrationHooks = map[uint]migrationHook{
+	9: normalizeUserEmails,
+}
+
+// MigrationStatus describes whether a migration has been applied
+type MigrationStatus struct {
+	Version   uint       `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"appliedAt,omitempty"`
+}
+
+// schemaMigration is a row of the schema_migrations table
+type schemaMigration struct {
+	Version   uint      `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// TableName specifies the table name for applied migrations
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrator applies the embedded SQL migrations for the connected driver
+// and records them in the schema_migrations table
+type Migrator struct {
+	db         *gorm.DB
+	driver     string
+	migrations []Migration
+}
+
+// NewMigrator creates a migrator for the driver of the given connection
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	driver := db.Dialector.Name()
+	loaded, err := loadMigrations(migrations.FS, driver)
+	if err != nil {
+		return nil, err
+	}
+	for i := range loaded {
+		loaded[i].prepare = migrationHooks[loaded[i].Version]
+	}
+
+	return &Migrator{
+		db:         db,
+		driver:     driver,
+		migrations: loaded,
+	}, nil
+}
+
+// Migrations returns the known migrations ordered by version
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// LatestVersion returns the version of the newest known migration
+func (m *Migrator) LatestVersion() uint {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up applies every pending migration in version order and returns how
+// many were applied
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	count := 0
+	err := m.withLock(
+		ctx, func() error {
+			applied, err := m.applied(ctx)
+			if err != nil {
+				return err
+			}
+
+			for _, migration := range m.migrations {
+				if _, ok := applied[migration.Version]; ok {
+					continue
+				}
+				if err := m.apply(ctx, migration); err != nil {
+					return err
+				}
+				count++
+			}
+			return nil
+		},
+	)
+	return count, err
+}
+
+// Down reverts up to steps applied migrations, newest first, and returns
+// how many were reverted
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	count := 0
+	err := m.withLock(
+		ctx, func() error {
+			applied, err := m.applied(ctx)
+			if err != nil {
+				return err
+			}
+
+			for i := len(m.migrations) - 1; i >= 0 && count < steps; i-- {
+				migration := m.migrations[i]
+				if _, ok := applied[migration.Version]; !ok {
+					continue
+				}
+				if err := m.revert(ctx, migration); err != nil {
+					return err
+				}
+				count++
+			}
+			return nil
+		},
+	)
+	return count, err
+}
+
+// Status reports every known migration and whether it has been applied
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, len(m.migrations))
+	for i, migration := range m.migrations {
+		statuses[i] = MigrationStatus{
+			Version: migration.Version,
+			Name:    migration.Name,
+		}
+		if row, ok := applied[migration.Version]; ok {
+			appliedAt := row.AppliedAt
+			statuses[i].Applied = true
+			statuses[i].AppliedAt = &appliedAt
+		}
+	}
+	return statuses, nil
+}
+
+// Check runs the hooks of the pending migrations without changing
+// anything, so that what would make them fail can be fixed beforehand.
+// A hook expects the schema left by the migrations before it, so the hooks
+// following a pending migration cannot run yet: Check returns these
+// unchecked migrations, which Up still checks when applying them.
+func (m *Migrator) Check(ctx context.Context) ([]Migration, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var unchecked []Migration
+	var errs []error
+	earlierPending := false
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		switch {
+		case migration.prepare == nil:
+		case earlierPending:
+			unchecked = append(unchecked, migration)
+		default:
+			if err := migration.prepare(ctx, m.db, true); err != nil {
+				errs = append(
+					errs, fmt.Errorf(
+						"migration %03d_%s cannot be applied: %w",
+						migration.Version,
+						migration.Name,
+						err,
+					),
+				)
+			}
+		}
+		earlierPending = true
+	}
+	return unchecked, errors.Join(errs...)
+}
+
+// Version returns the highest applied migration version, or 0 if none
+func (m *Migrator) Version(ctx context.Context) (uint, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return 0, err
+	}
+
+	var version sql.NullInt64
+	if err := m.db.WithContext(ctx).
+		Model(&schemaMigration{}).
+		Select("MAX(version)").
+		Scan(&version).Error; err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return uint(version.Int64), nil
+}
+
+// apply runs an up migration and records it in a single transaction.
+// MySQL commits DDL implicitly, so a failing migration there may be
+// partially applied.
+func (m *Migrator) apply(ctx context.Context, migration Migration) error {
+	slog.InfoContext(
+		ctx,
+		"Applying migration",
+		slog.Uint64("version", uint64(migration.Version)),
+		slog.String("name", migration.Name),
+	)
+
+	err := m.db.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			if migration.prepare != nil {
+				if err := migration.prepare(ctx, tx, false); err != nil {
+					return err
+				}
+			}
+			if err := tx.Exec(migration.UpSQL).Error; err != nil {
+				return err
+			}
+			return tx.Create(
+				&schemaMigration{
+					Version:   migration.Version,
+					Name:      migration.Name,
+					AppliedAt: tx.NowFunc().UTC(),
+				},
+			).Error
+		},
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to apply migration %03d_%s: %w",
+			migration.Version,
+			migration.Name,
+			err,
+		)
+	}
+	return nil
+}
+
+// revert runs a down migration and removes its record in a single transaction
+func (m *Migrator) revert(ctx context.Context, migration Migration) error {
+	if migration.DownSQL == "" {
+		return fmt.Errorf(
+			"migration %03d_%s has no down script",
+			migration.Version,
+			migration.Name,
+		)
+	}
+
+	slog.InfoContext(
+		ctx,
+		"Reverting migration",
+		slog.Uint64("version", uint64(migration.Version)),
+		slog.String("name", migration.Name),
+	)
+
+	err := m.db.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			if err := tx.Exec(migration.DownSQL).Error; err != nil {
+				return err
+			}
+			return tx.Delete(&schemaMigration{}, migration.Version).Error
+		},
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to revert migration %03d_%s: %w",
+			migration.Version,
+			migration.Name,
+			err,
+		)
+	}
+	return nil
+}
+
+// applied returns the recorded migrations keyed by version
+func (m *Migrator) applied(ctx context.Context) (map[uint]schemaMigration, error) {
+	var rows []schemaMigration
+	if err := m.db.WithContext(ctx).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+
+	applied := make(map[uint]schemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// AppliedVersion returns the highest applied migration version, or 0 if
+// none, without creating the schema_migrations table as Version does, so
+// that readiness probes only read
+func (m *Migrator) AppliedVersion(ctx context.Context) (uint, error) {
+	db := m.db.WithContext(ctx)
+	if !db.Migrator().HasTable(&schemaMigration{}) {
+		return 0, nil
+	}
+
+	var version sql.NullInt64
+	if err := db.Model(&schemaMigration{}).
+		Select("MAX(version)").
+		Scan(&version).Error; err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return uint(version.Int64), nil
+}
+
+// ensureTable creates the schema_migrations table if it does not exist
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	err := m.db.WithContext(ctx).Exec(
+		`CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT NOT NULL PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMP NOT NULL
+		)`,
+	).Error
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+	return nil
+}
+
+// withLock runs fn while holding a database-wide advisory lock so that
+// parallel instances do not migrate concurrently
+func (m *Migrator) withLock(ctx context.Context, fn func() error) error {
+	if err := m.ensureTable(ctx); err != nil {
+		return err
+	}
+
+	// SQLite serializes writers itself and only has a single connection
+	if m.driver == DriverSQLite {
+		return fn()
+	}
+
+	sqlDB, err := m.db.DB()
+	if err != nil {
+		return err
+	}
+
+	// Session-level locks belong to a connection, so the same connection
+	// must be used to acquire and release them
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire migration connection: %w", err)
+	}
+	defer conn.Close()
+
+	switch m.driver {
+	case DriverPostgres:
+		if _, err := conn.ExecContext(
+			ctx,
+			"SELECT pg_advisory_lock($1)",
+			migrationLockKey,
+		); err != nil {
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		defer conn.ExecContext(
+			context.Background(),
+			"SELECT pg_advisory_unlock($1)",
+			migrationLockKey,
+		)
+	case DriverMySQL:
+		var acquired sql.NullInt64
+		if err := conn.QueryRowContext(
+			ctx,
+			"SELECT GET_LOCK(?, -1)",
+			migrationLockName,
+		).Scan(&acquired); err != nil {
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		if acquired.Int64 != 1 {
+			return fmt.Errorf("failed to acquire migration lock %q", migrationLockName)
+		}
+		defer conn.ExecContext(
+			context.Background(),
+			"SELECT RELEASE_LOCK(?)",
+			migrationLockName,
+		)
+	}
+
+	return fn()
+}
+
+// loadMigrations reads and pairs the up and down scripts of a driver
+func loadMigrations(files fs.FS, driver string) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, driver)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for driver %q: %w", driver, err)
+	}
+
+	byVersion := map[uint]*Migration{}
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseUint(match[1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", entry.Name(), err)
+		}
+
+		content, err := fs.ReadFile(files, driver+"/"+entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[uint(version)]
+		if !ok {
+			migration = &Migration{Version: uint(version), Name: match[2]}
+			byVersion[uint(version)] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf(
+				"migration version %d is used by both %q and %q",
+				version,
+				migration.Name,
+				match[2],
+			)
+		}
+
+		if match[3] == "up" {
+			migration.UpSQL = string(content)
+		} else {
+			migration.DownSQL = string(content)
+		}
+	}
+
+	loaded := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.UpSQL == "" {
+			return nil, fmt.Errorf(
+				"migration %03d_%s has no up script",
+				migration.Version,
+				migration.Name,
+			)
+		}
+		loaded = append(loaded, *migration)
+	}
+	sort.Slice(
+		loaded, func(i, j int) bool {
+			return loaded[i].Version < loaded[j].Version
+		},
+	)
+	return loaded, nil
+}
@@ -0,0 +1,188 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/config"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+// newMigrator connects a new SQLite database file and its migrator
+func newMigrator(t *testing.T) (*gorm.DB, *database.Migrator) {
+	t.Helper()
+	db, err := database.NewDatabaseConnection(
+		config.DatabaseConfig{
+			Driver: database.DriverSQLite,
+			Name:   filepath.Join(t.TempDir(), "test.db"),
+		},
+		clock.System,
+	)
+	if err != nil {
+		t.Fatalf("failed to connect the database: %v", err)
+	}
+	t.Cleanup(
+		func() {
+			if sqlDB, err := db.DB(); err == nil {
+				sqlDB.Close()
+			}
+		},
+	)
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		t.Fatalf("failed to load the migrations: %v", err)
+	}
+	return db, migrator
+}
+
+// insertUser stores a user with an email as given, as older versions did
+func insertUser(t *testing.T, db *gorm.DB, email string) {
+	t.Helper()
+	err := db.Exec(
+		`INSERT INTO users (email, password, first_name, last_name, role, created_at, updated_at)
+		VALUES (?, 'hash', 'Jane', 'Doe', 'user', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`,
+		email,
+	).Error
+	if err != nil {
+		t.Fatalf("failed to insert user %q: %v", email, err)
+	}
+}
+
+// storedEmails returns the emails of the users table ordered by ID
+func storedEmails(t *testing.T, db *gorm.DB) []string {
+	t.Helper()
+	var emails []string
+	if err := db.Table("users").Order("id").Pluck("email", &emails).Error; err != nil {
+		t.Fatalf("failed to read emails: %v", err)
+	}
+	return emails
+}
+
+func TestMigratorFreshDatabase(t *testing.T) {
+	ctx := context.Background()
+	db, migrator := newMigrator(t)
+
+	// Reading the version creates nothing
+	if version, err := migrator.AppliedVersion(ctx); err != nil || version != 0 {
+		t.Errorf("expected version 0, got %d (%v)", version, err)
+	}
+	if db.Migrator().HasTable("schema_migrations") {
+		t.Error("expected the version to be read without creating schema_migrations")
+	}
+
+	// Hooks cannot run before the tables they read exist
+	unchecked, err := migrator.Check(ctx)
+	if err != nil {
+		t.Fatalf("expected a fresh database to pass the check, got %v", err)
+	}
+	if len(unchecked) != 1 || unchecked[0].Version != 9 {
+		t.Errorf("expected migration 9 to be left unchecked, got %+v", unchecked)
+	}
+
+	count, err := migrator.Up(ctx)
+	if err != nil {
+		t.Fatalf("failed to migrate a fresh database: %v", err)
+	}
+	if count != len(migrator.Migrations()) {
+		t.Errorf("expected %d migrations applied, got %d", len(migrator.Migrations()), count)
+	}
+	version, err := migrator.Version(ctx)
+	if err != nil || version != migrator.LatestVersion() {
+		t.Errorf("expected version %d, got %d (%v)", migrator.LatestVersion(), version, err)
+	}
+	if version, err := migrator.AppliedVersion(ctx); err != nil || version != migrator.LatestVersion() {
+		t.Errorf("expected applied version %d, got %d (%v)", migrator.LatestVersion(), version, err)
+	}
+
+	// Nothing is left to apply or check
+	if count, err := migrator.Up(ctx); err != nil || count != 0 {
+		t.Errorf("expected no migration to apply, got %d (%v)", count, err)
+	}
+	if unchecked, err := migrator.Check(ctx); err != nil || len(unchecked) != 0 {
+		t.Errorf("expected nothing to check, got %+v (%v)", unchecked, err)
+	}
+}
+
+func TestMigratorNormalizesEmails(t *testing.T) {
+	ctx := context.Background()
+	db, migrator := newMigrator(t)
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	if _, err := migrator.Down(ctx, 1); err != nil {
+		t.Fatalf("failed to revert migration 9: %v", err)
+	}
+	insertUser(t, db, " Jane@Example.COM ")
+	insertUser(t, db, "john@bücher.example")
+
+	if unchecked, err := migrator.Check(ctx); err != nil || len(unchecked) != 0 {
+		t.Fatalf("expected the check to pass, got %+v (%v)", unchecked, err)
+	}
+	if got := storedEmails(t, db); got[0] != " Jane@Example.COM " {
+		t.Errorf("expected the check to change nothing, got %q", got)
+	}
+
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("failed to apply migration 9: %v", err)
+	}
+	want := []string{"jane@example.com", "john@xn--bcher-kva.example"}
+	got := storedEmails(t, db)
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("expected normalized emails %q, got %q", want, got)
+	}
+
+	// The index now rejects emails differing only by case
+	if err := db.Exec(
+		`INSERT INTO users (email, password, first_name, last_name, role, created_at, updated_at)
+		VALUES ('JANE@example.com', 'hash', 'Jane', 'Doe', 'user', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`,
+	).Error; err == nil {
+		t.Error("expected a case variant of an email to be rejected")
+	}
+}
+
+func TestMigratorRefusesEmailCollisions(t *testing.T) {
+	ctx := context.Background()
+	db, migrator := newMigrator(t)
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	if _, err := migrator.Down(ctx, 1); err != nil {
+		t.Fatalf("failed to revert migration 9: %v", err)
+	}
+	insertUser(t, db, "jane@example.com")
+	insertUser(t, db, "other@example.com")
+	insertUser(t, db, "Jane@Example.com")
+
+	var collisionErr *database.EmailCollisionError
+	_, err := migrator.Check(ctx)
+	if !errors.As(err, &collisionErr) {
+		t.Fatalf("expected an email collision, got %v", err)
+	}
+	if len(collisionErr.Collisions) != 1 {
+		t.Fatalf("expected a single collision, got %+v", collisionErr.Collisions)
+	}
+	collision := collisionErr.Collisions[0]
+	if collision.Email != "jane@example.com" ||
+		len(collision.Users) != 2 ||
+		collision.Users[0].ID != 1 ||
+		collision.Users[1].ID != 3 {
+		t.Errorf("unexpected collision: %+v", collision)
+	}
+
+	// Applying the migration is refused and leaves the data untouched
+	if _, err := migrator.Up(ctx); !errors.As(err, &collisionErr) {
+		t.Fatalf("expected the migration to be refused, got %v", err)
+	}
+	version, err := migrator.Version(ctx)
+	if err != nil || version != 8 {
+		t.Errorf("expected version 8, got %d (%v)", version, err)
+	}
+	if got := storedEmails(t, db); got[2] != "Jane@Example.com" {
+		t.Errorf("expected the emails to be left as is, got %q", got)
+	}
+}
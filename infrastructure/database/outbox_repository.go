@@ -0,0 +1,148 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/domain/events"
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormOutboxRepository implements OutboxRepository interface using GORM
+type GormOutboxRepository struct {
+	db *gorm.DB
+}
+
+// NewGormOutboxRepository creates a new GORM outbox repository
+func NewGormOutboxRepository(db *gorm.DB) repositories.OutboxRepository {
+	return &GormOutboxRepository{db: db}
+}
+
+// Add stores events in the outbox, inside the transaction carried by ctx if any
+func (r *GormOutboxRepository) Add(
+	ctx context.Context,
+	envelopes ...events.Envelope,
+) error {
+	if len(envelopes) == 0 {
+		return nil
+	}
+
+	messages := make([]entities.OutboxMessage, len(envelopes))
+	for i, envelope := range envelopes {
+		messages[i] = entities.OutboxMessage{
+			EventID:       envelope.ID,
+			EventType:     envelope.Type,
+			AggregateID:   envelope.AggregateID,
+			Payload:       string(envelope.Payload),
+			OccurredAt:    envelope.OccurredAt,
+			NextAttemptAt: envelope.OccurredAt,
+		}
+	}
+
+	return TranslateError(Conn(ctx, r.db).Create(&messages).Error)
+}
+
+// ClaimDue returns undelivered messages that are due and leases them so
+// that concurrent relays skip them until the lease expires
+func (r *GormOutboxRepository) ClaimDue(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]entities.OutboxMessage, error) {
+	var messages []entities.OutboxMessage
+	err := Conn(ctx, r.db).Transaction(
+		func(tx *gorm.DB) error {
+			query := tx.Where(
+				"delivered_at IS NULL AND next_attempt_at <= ?",
+				now.UTC(),
+			).Order("id").Limit(limit)
+			// SQLite has a single writer and no row locks
+			if tx.Dialector.Name() != DriverSQLite {
+				query = query.Clauses(
+					clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"},
+				)
+			}
+			if err := query.Find(&messages).Error; err != nil {
+				return err
+			}
+			if len(messages) == 0 {
+				return nil
+			}
+
+			ids := make([]uint, len(messages))
+			for i, message := range messages {
+				ids[i] = message.ID
+			}
+			return tx.Model(&entities.OutboxMessage{}).
+				Where("id IN ?", ids).
+				Update("next_attempt_at", now.Add(lease).UTC()).Error
+		},
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	return messages, nil
+}
+
+// MarkDelivered records a successful delivery
+func (r *GormOutboxRepository) MarkDelivered(
+	ctx context.Context,
+	id uint,
+	deliveredAt time.Time,
+) error {
+	return TranslateError(
+		Conn(ctx, r.db).Model(&entities.OutboxMessage{}).
+			Where("id = ?", id).
+			Updates(
+				map[string]any{
+					"attempts":     gorm.Expr("attempts + 1"),
+					"delivered_at": deliveredAt.UTC(),
+					"last_error":   "",
+				},
+			).Error,
+	)
+}
+
+// MarkFailed records a failed delivery and schedules the next attempt
+func (r *GormOutboxRepository) MarkFailed(
+	ctx context.Context,
+	id uint,
+	lastError string,
+	nextAttemptAt time.Time,
+) error {
+	return TranslateError(
+		Conn(ctx, r.db).Model(&entities.OutboxMessage{}).
+			Where("id = ?", id).
+			Updates(
+				map[string]any{
+					"attempts":        gorm.Expr("attempts + 1"),
+					"last_error":      lastError,
+					"next_attempt_at": nextAttemptAt.UTC(),
+				},
+			).Error,
+	)
+}
+
+// OldestPending returns when the oldest undelivered message occurred
+func (r *GormOutboxRepository) OldestPending(ctx context.Context) (
+	*time.Time,
+	error,
+) {
+	var messages []entities.OutboxMessage
+	result := Conn(ctx, r.db).
+		Where("delivered_at IS NULL").
+		Order("occurred_at").
+		Limit(1).
+		Find(&messages)
+	if result.Error != nil {
+		return nil, TranslateError(result.Error)
+	}
+	if len(messages) == 0 {
+		return nil, nil
+	}
+	return &messages[0].OccurredAt, nil
+}
@@ -24,7 +24,7 @@ func (r *PostgresUserRepository) Create(
 	ctx context.Context,
 	user *entities.User,
 ) error {
-	return r.db.WithContext(ctx).Create(user).Error
+	return TranslateError(Conn(ctx, r.db).Create(user).Error)
 }
 
 // GetByID retrieves a user by ID
@@ -33,12 +33,12 @@ func (r *PostgresUserRepository) GetByID(
 	id uint,
 ) (*entities.User, error) {
 	var user entities.User
-	result := r.db.WithContext(ctx).First(&user, id)
+	result := Conn(ctx, r.db).First(&user, id)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, nil // No user found
 		}
-		return nil, result.Error
+		return nil, TranslateError(result.Error)
 	}
 	return &user, nil
 }
@@ -46,15 +46,17 @@ func (r *PostgresUserRepository) GetByID(
 // GetByEmail retrieves a user by email
 func (r *PostgresUserRepository) GetByEmail(
 	ctx context.Context,
-	email string,
+	email entities.Email,
 ) (*entities.User, error) {
 	var user entities.User
-	result := r.db.WithContext(ctx).Where("email = ?", email).First(&user)
+	// Stored emails are lowercased, and LOWER(email) is the expression of
+	// the unique index
+	result := Conn(ctx, r.db).Where("LOWER(email) = ?", email.String()).First(&user)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, nil // No user found
 		}
-		return nil, result.Error
+		return nil, TranslateError(result.Error)
 	}
 	return &user, nil
 }
@@ -65,9 +67,9 @@ func (r *PostgresUserRepository) GetAll(ctx context.Context) (
 	error,
 ) {
 	var users []entities.User
-	result := r.db.WithContext(ctx).Find(&users)
+	result := Conn(ctx, r.db).Find(&users)
 	if result.Error != nil {
-		return nil, result.Error
+		return nil, TranslateError(result.Error)
 	}
 	return users, nil
 }
@@ -77,10 +79,12 @@ func (r *PostgresUserRepository) Update(
 	ctx context.Context,
 	user *entities.User,
 ) error {
-	return r.db.WithContext(ctx).Save(user).Error
+	return TranslateError(Conn(ctx, r.db).Save(user).Error)
 }
 
 // Delete removes a user by ID
 func (r *PostgresUserRepository) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&entities.User{}, id).Error
+	return TranslateError(
+		Conn(ctx, r.db).Delete(&entities.User{}, id).Error,
+	)
 }
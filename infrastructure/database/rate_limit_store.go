@@ -0,0 +1,88 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormRateLimitStore implements RateLimitStore interface using GORM, so
+// that every instance of the API shares the same counters
+type GormRateLimitStore struct {
+	db *gorm.DB
+}
+
+// NewGormRateLimitStore creates a new GORM rate limit store
+func NewGormRateLimitStore(db *gorm.DB) repositories.RateLimitStore {
+	return &GormRateLimitStore{db: db}
+}
+
+// Update locks the row of key for the duration of a transaction, creating
+// it first if needed, and stores the state returned by fn
+func (s *GormRateLimitStore) Update(
+	ctx context.Context,
+	key string,
+	now time.Time,
+	ttl time.Duration,
+	fn func(state []byte) ([]byte, error),
+) error {
+	now = now.UTC()
+	err := Conn(ctx, s.db).Transaction(
+		func(tx *gorm.DB) error {
+			// Make sure the row exists so that concurrent requests wait on its lock
+			placeholder := entities.RateLimitState{
+				Key:       key,
+				State:     []byte{},
+				ExpiresAt: now,
+			}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+				Create(&placeholder).Error; err != nil {
+				return err
+			}
+
+			query := tx.Where("rate_limit_key = ?", key)
+			// SQLite has a single writer and no row locks
+			if tx.Dialector.Name() != DriverSQLite {
+				query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+			}
+			var current entities.RateLimitState
+			if err := query.First(&current).Error; err != nil {
+				return err
+			}
+
+			var state []byte
+			if current.ExpiresAt.After(now) && len(current.State) > 0 {
+				state = current.State
+			}
+			next, err := fn(state)
+			if err != nil {
+				return err
+			}
+
+			return tx.Model(&entities.RateLimitState{}).
+				Where("rate_limit_key = ?", key).
+				Updates(
+					map[string]any{
+						"state":      next,
+						"expires_at": now.Add(ttl),
+					},
+				).Error
+		},
+	)
+	return TranslateError(err)
+}
+
+// DeleteExpired removes the states expired at now
+func (s *GormRateLimitStore) DeleteExpired(
+	ctx context.Context,
+	now time.Time,
+) (int64, error) {
+	result := Conn(ctx, s.db).
+		Where("expires_at <= ?", now.UTC()).
+		Delete(&entities.RateLimitState{})
+	return result.RowsAffected, TranslateError(result.Error)
+}
@@ -0,0 +1,43 @@
+package database
+
+import (
+	"context"
+
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+	"gorm.io/gorm"
+)
+
+// txKey is the context key of the active GORM transaction
+type txKey struct{}
+
+// GormUnitOfWork implements UnitOfWork interface with GORM transactions
+type GormUnitOfWork struct {
+	db *gorm.DB
+}
+
+// NewGormUnitOfWork creates a new GORM unit of work
+func NewGormUnitOfWork(db *gorm.DB) repositories.UnitOfWork {
+	return &GormUnitOfWork{db: db}
+}
+
+// Do runs fn in a transaction that is committed when fn returns nil and
+// rolled back otherwise. Nested calls use savepoints.
+func (u *GormUnitOfWork) Do(
+	ctx context.Context,
+	fn func(ctx context.Context) error,
+) error {
+	return Conn(ctx, u.db).Transaction(
+		func(tx *gorm.DB) error {
+			return fn(context.WithValue(ctx, txKey{}, tx))
+		},
+	)
+}
+
+// Conn returns the transaction carried by ctx, or db when there is none,
+// bound to ctx
+func Conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
@@ -0,0 +1,199 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormWebhookDeliveryRepository implements WebhookDeliveryRepository
+// interface using GORM
+type GormWebhookDeliveryRepository struct {
+	db    *gorm.DB
+	clock clock.Clock
+}
+
+// NewGormWebhookDeliveryRepository creates a new GORM webhook delivery
+// repository timestamping updates with clk
+func NewGormWebhookDeliveryRepository(
+	db *gorm.DB,
+	clk clock.Clock,
+) repositories.WebhookDeliveryRepository {
+	return &GormWebhookDeliveryRepository{db: db, clock: clk}
+}
+
+// Enqueue stores pending deliveries, skipping the ones already recorded
+func (r *GormWebhookDeliveryRepository) Enqueue(
+	ctx context.Context,
+	deliveries ...entities.WebhookDelivery,
+) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	// A redelivered event must not be sent twice to the same subscription
+	return TranslateError(
+		Conn(ctx, r.db).
+			Clauses(
+				clause.OnConflict{
+					Columns:   []clause.Column{{Name: "subscription_id"}, {Name: "event_id"}},
+					DoNothing: true,
+				},
+			).
+			Create(&deliveries).Error,
+	)
+}
+
+// ClaimDue returns pending deliveries that are due and leases them so that
+// concurrent dispatchers skip them until the lease expires
+func (r *GormWebhookDeliveryRepository) ClaimDue(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]entities.WebhookDelivery, error) {
+	var deliveries []entities.WebhookDelivery
+	err := Conn(ctx, r.db).Transaction(
+		func(tx *gorm.DB) error {
+			query := tx.Where(
+				"status = ? AND next_attempt_at <= ?",
+				entities.WebhookDeliveryPending,
+				now.UTC(),
+			).Order("id").Limit(limit)
+			// SQLite has a single writer and no row locks
+			if tx.Dialector.Name() != DriverSQLite {
+				query = query.Clauses(
+					clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"},
+				)
+			}
+			if err := query.Find(&deliveries).Error; err != nil {
+				return err
+			}
+			if len(deliveries) == 0 {
+				return nil
+			}
+
+			ids := make([]uint, len(deliveries))
+			for i, delivery := range deliveries {
+				ids[i] = delivery.ID
+			}
+			return tx.Model(&entities.WebhookDelivery{}).
+				Where("id IN ?", ids).
+				Update("next_attempt_at", now.Add(lease).UTC()).Error
+		},
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	return deliveries, nil
+}
+
+// MarkSucceeded records a successful attempt
+func (r *GormWebhookDeliveryRepository) MarkSucceeded(
+	ctx context.Context,
+	id uint,
+	responseStatus int,
+	deliveredAt time.Time,
+) error {
+	return r.update(
+		ctx, id, map[string]any{
+			"status":          entities.WebhookDeliverySucceeded,
+			"attempts":        gorm.Expr("attempts + 1"),
+			"response_status": responseStatus,
+			"last_error":      "",
+			"delivered_at":    deliveredAt.UTC(),
+		},
+	)
+}
+
+// MarkFailed records a failed attempt and schedules the next one, or gives
+// up on the delivery when nextAttemptAt is nil
+func (r *GormWebhookDeliveryRepository) MarkFailed(
+	ctx context.Context,
+	id uint,
+	responseStatus int,
+	lastError string,
+	nextAttemptAt *time.Time,
+) error {
+	updates := map[string]any{
+		"attempts":        gorm.Expr("attempts + 1"),
+		"response_status": responseStatus,
+		"last_error":      lastError,
+	}
+	if nextAttemptAt != nil {
+		updates["next_attempt_at"] = nextAttemptAt.UTC()
+	} else {
+		updates["status"] = entities.WebhookDeliveryFailed
+	}
+	return r.update(ctx, id, updates)
+}
+
+// FindByID returns a delivery, or nil if it does not exist
+func (r *GormWebhookDeliveryRepository) FindByID(
+	ctx context.Context,
+	id uint,
+) (*entities.WebhookDelivery, error) {
+	var delivery entities.WebhookDelivery
+	result := Conn(ctx, r.db).First(&delivery, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, TranslateError(result.Error)
+	}
+	return &delivery, nil
+}
+
+// FindBySubscription returns the latest deliveries of a subscription
+func (r *GormWebhookDeliveryRepository) FindBySubscription(
+	ctx context.Context,
+	subscriptionID uint,
+	limit int,
+) ([]entities.WebhookDelivery, error) {
+	deliveries := []entities.WebhookDelivery{}
+	result := Conn(ctx, r.db).
+		Where("subscription_id = ?", subscriptionID).
+		Order("id DESC").
+		Limit(limit).
+		Find(&deliveries)
+	if result.Error != nil {
+		return nil, TranslateError(result.Error)
+	}
+	return deliveries, nil
+}
+
+// Replay schedules a delivery to be sent again
+func (r *GormWebhookDeliveryRepository) Replay(
+	ctx context.Context,
+	id uint,
+	now time.Time,
+) error {
+	return r.update(
+		ctx, id, map[string]any{
+			"status":          entities.WebhookDeliveryPending,
+			"attempts":        0,
+			"next_attempt_at": now.UTC(),
+			"delivered_at":    nil,
+		},
+	)
+}
+
+// update applies column updates to one delivery
+func (r *GormWebhookDeliveryRepository) update(
+	ctx context.Context,
+	id uint,
+	updates map[string]any,
+) error {
+	updates["updated_at"] = r.clock.Now().UTC()
+	return TranslateError(
+		Conn(ctx, r.db).Model(&entities.WebhookDelivery{}).
+			Where("id = ?", id).
+			Updates(updates).Error,
+	)
+}
@@ -0,0 +1,155 @@
+// Package health runs the named checks that decide whether the API is
+// ready to receive traffic.
+package health
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// StatusUp reports a passing check, or a ready server
+	StatusUp = "up"
+	// StatusDown reports a failing check, or a server that is not ready
+	StatusDown = "down"
+)
+
+// ErrShuttingDown reports that the server is draining its traffic
+var ErrShuttingDown = errors.New("server is shutting down")
+
+// CheckFunc returns an error when the checked dependency is unhealthy
+type CheckFunc func(ctx context.Context) error
+
+// CheckResult is the outcome of a single check. Only its name and status
+// are sent to clients, as readiness is public; errors are logged.
+type CheckResult struct {
+	Name      string  `json:"name" example:"database"`
+	Status    string  `json:"status" example:"up"`
+	LatencyMs float64 `json:"-"`
+	Error     string  `json:"-"`
+}
+
+// Report is the outcome of all the checks; the server is up when every
+// check is
+type Report struct {
+	Status string        `json:"status" example:"up"`
+	Checks []CheckResult `json:"checks"`
+}
+
+// check is a registered named check
+type check struct {
+	name string
+	fn   CheckFunc
+}
+
+// Registry holds the readiness checks
+type Registry struct {
+	timeout      time.Duration
+	mu           sync.RWMutex
+	checks       []check
+	shuttingDown atomic.Bool
+}
+
+// NewRegistry creates a registry whose checks fail after timeout
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{
+		timeout: timeout,
+	}
+}
+
+// Register adds a named check, run on every readiness request
+func (r *Registry) Register(name string, fn CheckFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, check{name: name, fn: fn})
+}
+
+// SetShuttingDown makes the server unready for good, so that load
+// balancers stop routing new requests to it before it stops
+func (r *Registry) SetShuttingDown() {
+	r.shuttingDown.Store(true)
+}
+
+// Check runs all the checks concurrently and reports their outcome. While
+// shutting down, the checks are skipped and the server is reported down.
+func (r *Registry) Check(ctx context.Context) Report {
+	if r.shuttingDown.Load() {
+		return Report{
+			Status: StatusDown,
+			Checks: []CheckResult{
+				{
+					Name:   "shutdown",
+					Status: StatusDown,
+					Error:  ErrShuttingDown.Error(),
+				},
+			},
+		}
+	}
+
+	r.mu.RLock()
+	checks := append([]check(nil), r.checks...)
+	r.mu.RUnlock()
+
+	report := Report{
+		Status: StatusUp,
+		Checks: make([]CheckResult, len(checks)),
+	}
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Checks[i] = r.run(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		if result.Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+	return report
+}
+
+// run runs a check, giving up when it outlives the timeout even if it
+// ignores its context
+func (r *Registry) run(ctx context.Context, check check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- check.fn(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := CheckResult{
+		Name:      check.name,
+		Status:    StatusUp,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+		slog.WarnContext(
+			ctx,
+			"Health check failed",
+			slog.String("check", check.name),
+			slog.Float64("latency_ms", result.LatencyMs),
+			slog.String("error", err.Error()),
+		)
+	}
+	return result
+}
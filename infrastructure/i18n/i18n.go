@@ -0,0 +1,100 @@
+// Package i18n holds the message catalogs of the API and negotiates the
+// language of responses. English is the default and the fallback for
+// messages missing from other catalogs.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// DefaultLocale is used when no supported locale is requested
+const DefaultLocale = "en"
+
+// locales lists the supported locales, the default first
+var locales = []string{DefaultLocale, "fr", "ar"}
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// catalogs maps each locale to its messages by key
+var catalogs = mustLoadCatalogs()
+
+// matcher matches requested languages to the supported locales
+var matcher = newMatcher()
+
+// mustLoadCatalogs loads the embedded catalog of every locale
+func mustLoadCatalogs() map[string]map[string]string {
+	loaded := make(map[string]map[string]string, len(locales))
+	for _, locale := range locales {
+		content, err := localeFiles.ReadFile("locales/" + locale + ".json")
+		if err != nil {
+			panic(fmt.Sprintf("i18n: missing catalog for %s: %v", locale, err))
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(content, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: invalid catalog for %s: %v", locale, err))
+		}
+		loaded[locale] = messages
+	}
+	return loaded
+}
+
+// newMatcher creates a matcher of the supported locales
+func newMatcher() language.Matcher {
+	tags := make([]language.Tag, len(locales))
+	for i, locale := range locales {
+		tags[i] = language.MustParse(locale)
+	}
+	return language.NewMatcher(tags)
+}
+
+// Locales returns the supported locales, the default first
+func Locales() []string {
+	return append([]string(nil), locales...)
+}
+
+// IsSupported reports whether a catalog exists for locale
+func IsSupported(locale string) bool {
+	_, ok := catalogs[locale]
+	return ok
+}
+
+// Negotiate returns the supported locale best matching an Accept-Language
+// header, or the default locale
+func Negotiate(acceptLanguage string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return DefaultLocale
+	}
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return DefaultLocale
+	}
+	return locales[index]
+}
+
+// Translate returns the message of key in locale, falling back to English
+// and then to the key itself. args are name/value pairs replacing {name}
+// placeholders, e.g. Translate("fr", "error.invalid_parameter", "name", "id").
+func Translate(locale, key string, args ...string) string {
+	message, ok := catalogs[locale][key]
+	if !ok {
+		if message, ok = catalogs[DefaultLocale][key]; !ok {
+			return key
+		}
+	}
+	if len(args) == 0 {
+		return message
+	}
+
+	replacements := make([]string, 0, len(args))
+	for i := 0; i+1 < len(args); i += 2 {
+		replacements = append(replacements, "{"+args[i]+"}", args[i+1])
+	}
+	return strings.NewReplacer(replacements...).Replace(message)
+}
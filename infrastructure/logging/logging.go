@@ -0,0 +1,89 @@
+// Package logging configures the structured JSON logger shared by the API,
+// the mediatr pipeline and GORM, and correlates log lines with requests.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader is the header a request ID is read from and echoed in
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDKey is the context key of the request ID
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the request ID, which is
+// added to every log line written with that context
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, if any
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// ParseLevel parses a LOG_LEVEL value: debug, info, warn or error
+func ParseLevel(value string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(value))); err != nil {
+		return level, fmt.Errorf("invalid log level %q (expected debug, info, warn or error)", value)
+	}
+	return level, nil
+}
+
+// New creates a logger writing JSON lines at or above level to w. Request
+// IDs are taken from the context and sensitive attributes are redacted.
+func New(w io.Writer, level slog.Leveler) *slog.Logger {
+	handler := slog.NewJSONHandler(
+		w, &slog.HandlerOptions{
+			Level:       level,
+			ReplaceAttr: redactAttr,
+		},
+	)
+	return slog.New(&contextHandler{Handler: handler})
+}
+
+// Setup makes a JSON logger the default slog logger. Since slog also
+// becomes the output of the standard log package, every log line of the
+// process is written as JSON.
+func Setup(w io.Writer, level slog.Leveler) {
+	slog.SetDefault(New(w, level))
+}
+
+// contextHandler adds the request ID and the trace of the context to each
+// record
+type contextHandler struct {
+	slog.Handler
+}
+
+// Handle adds the request ID and trace IDs, if any, and writes the record
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		record.AddAttrs(
+			slog.String("trace_id", spanContext.TraceID().String()),
+			slog.String("span_id", spanContext.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+// WithAttrs returns a handler that also adds the request ID
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup returns a handler that also adds the request ID
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
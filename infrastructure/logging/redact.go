@@ -0,0 +1,61 @@
+package logging
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// Redacted replaces the values of sensitive attributes
+const Redacted = "[REDACTED]"
+
+// sensitiveKeys are the fragments of attribute and header names whose
+// values are never logged
+var sensitiveKeys = []string{
+	"password",
+	"secret",
+	"token",
+	"authorization",
+	"cookie",
+	"api_key",
+	"apikey",
+	"api-key",
+}
+
+// IsSensitive reports whether the value of an attribute or header with
+// this name must not be logged
+func IsSensitive(name string) bool {
+	name = strings.ToLower(name)
+	for _, key := range sensitiveKeys {
+		if strings.Contains(name, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactHeaders returns the headers as a log value, with the values of
+// sensitive headers such as Authorization replaced
+func RedactHeaders(header http.Header) slog.Value {
+	attrs := make([]slog.Attr, 0, len(header))
+	for name, values := range header {
+		value := strings.Join(values, ", ")
+		if IsSensitive(name) {
+			value = Redacted
+		}
+		attrs = append(attrs, slog.String(name, value))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// redactAttr replaces the value of sensitive attributes, including those
+// nested in groups, and of sensitive headers logged as an http.Header
+func redactAttr(_ []string, attr slog.Attr) slog.Attr {
+	if IsSensitive(attr.Key) {
+		return slog.String(attr.Key, Redacted)
+	}
+	if header, ok := attr.Value.Any().(http.Header); ok {
+		return slog.Attr{Key: attr.Key, Value: RedactHeaders(header)}
+	}
+	return attr
+}
@@ -0,0 +1,29 @@
+package mail
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/EngenMe/go-clean-architecture/interfaces/messaging"
+)
+
+// LogMailer implements Mailer interface by logging emails instead of
+// sending them, which is convenient in development
+type LogMailer struct{}
+
+// NewLogMailer creates a new logging mailer
+func NewLogMailer() messaging.Mailer {
+	return &LogMailer{}
+}
+
+// Send logs the recipient, subject and body of the email
+func (m *LogMailer) Send(ctx context.Context, email messaging.Email) error {
+	slog.InfoContext(
+		ctx,
+		"Email",
+		slog.String("to", email.To),
+		slog.String("subject", email.Subject),
+		slog.String("body", email.Body),
+	)
+	return nil
+}
@@ -0,0 +1,73 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+
+	"github.com/EngenMe/go-clean-architecture/interfaces/messaging"
+)
+
+// SMTPOptions configures the SMTP server emails are sent through
+type SMTPOptions struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// SMTPMailer implements Mailer interface by sending emails through an SMTP
+// server
+type SMTPMailer struct {
+	options SMTPOptions
+}
+
+// NewSMTPMailer creates a mailer sending through the configured server
+func NewSMTPMailer(options SMTPOptions) (messaging.Mailer, error) {
+	if options.Host == "" || options.From == "" {
+		return nil, fmt.Errorf("SMTP host and sender address are required")
+	}
+	if options.Port == "" {
+		options.Port = "587"
+	}
+	return &SMTPMailer{options: options}, nil
+}
+
+// Send delivers the email as a UTF-8 plain text message
+func (m *SMTPMailer) Send(ctx context.Context, email messaging.Email) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.options.Username != "" {
+		auth = smtp.PlainAuth("", m.options.Username, m.options.Password, m.options.Host)
+	}
+
+	headers := []string{
+		"From: " + m.options.From,
+		"To: " + email.To,
+		// Subjects are encoded because they may contain non-ASCII characters
+		"Subject: " + mime.QEncoding.Encode("utf-8", email.Subject),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=utf-8",
+		"Content-Transfer-Encoding: 8bit",
+	}
+	message := strings.Join(headers, "\r\n") + "\r\n\r\n" +
+		strings.ReplaceAll(email.Body, "\n", "\r\n")
+
+	if err := smtp.SendMail(
+		net.JoinHostPort(m.options.Host, m.options.Port),
+		auth,
+		m.options.From,
+		[]string{email.To},
+		[]byte(message),
+	); err != nil {
+		return fmt.Errorf("failed to send email to %s: %w", email.To, err)
+	}
+	return nil
+}
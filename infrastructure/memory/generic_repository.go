@@ -0,0 +1,242 @@
+package memory
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+	"gorm.io/gorm"
+)
+
+// UniqueField declares a value that must be unique across stored entities,
+// mirroring a unique index in the database
+type UniqueField[T repositories.Entity] struct {
+	Name  string
+	Value func(entity *T) string
+	Err   error // Returned when the value is already taken
+}
+
+// GenericMemoryRepository implements GenericRepository interface in memory.
+// It is safe for concurrent use and follows the same semantics as the
+// GORM implementation: auto-increment IDs, automatic timestamps and
+// nil results for missing entities.
+type GenericMemoryRepository[T repositories.Entity] struct {
+	mu       sync.RWMutex
+	entities map[uint]T
+	nextID   uint
+	unique   []UniqueField[T]
+	clock    clock.Clock
+}
+
+// NewGenericMemoryRepository creates a new generic in-memory repository
+// timestamping entities with clk
+func NewGenericMemoryRepository[T repositories.Entity](
+	clk clock.Clock,
+	unique ...UniqueField[T],
+) repositories.GenericRepository[T] {
+	return &GenericMemoryRepository[T]{
+		entities: make(map[uint]T),
+		unique:   unique,
+		clock:    clk,
+	}
+}
+
+// Create adds a new entity to the store
+func (r *GenericMemoryRepository[T]) Create(
+	ctx context.Context,
+	entity *T,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.insert(entity)
+}
+
+// FindByID retrieves an entity by ID
+func (r *GenericMemoryRepository[T]) FindByID(
+	ctx context.Context,
+	id uint,
+) (*T, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entity, ok := r.entities[id]
+	if !ok {
+		return nil, nil // No entity found
+	}
+	return &entity, nil
+}
+
+// FindAll retrieves all entities ordered by ID
+func (r *GenericMemoryRepository[T]) FindAll(ctx context.Context) (
+	[]*T,
+	error,
+) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entities := make([]*T, 0, len(r.entities))
+	for _, entity := range r.entities {
+		entity := entity
+		entities = append(entities, &entity)
+	}
+	sort.Slice(
+		entities, func(i, j int) bool {
+			return (*entities[i]).GetID() < (*entities[j]).GetID()
+		},
+	)
+	return entities, nil
+}
+
+// FindFirst retrieves the entity with the lowest ID matching the predicate
+func (r *GenericMemoryRepository[T]) FindFirst(
+	ctx context.Context,
+	match func(entity *T) bool,
+) (*T, error) {
+	entities, err := r.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entity := range entities {
+		if match(entity) {
+			return entity, nil
+		}
+	}
+	return nil, nil // No entity found
+}
+
+// Update saves an existing entity, inserting it when it does not exist yet
+func (r *GenericMemoryRepository[T]) Update(
+	ctx context.Context,
+	entity *T,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := (*entity).GetID()
+	if _, ok := r.entities[id]; id == 0 || !ok {
+		return r.insert(entity)
+	}
+
+	if err := r.checkUnique(entity, id); err != nil {
+		return err
+	}
+
+	setTimeField(entity, "UpdatedAt", r.clock.Now())
+	r.entities[id] = *entity
+	return nil
+}
+
+// Delete removes an entity by ID
+func (r *GenericMemoryRepository[T]) Delete(
+	ctx context.Context,
+	id uint,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.entities, id)
+	return nil
+}
+
+// GetDB returns nil as there is no database behind the in-memory store
+func (r *GenericMemoryRepository[T]) GetDB() *gorm.DB {
+	return nil
+}
+
+// insert stores a new entity, assigning its ID and timestamps.
+// The caller must hold the write lock.
+func (r *GenericMemoryRepository[T]) insert(entity *T) error {
+	id := (*entity).GetID()
+	if id != 0 {
+		if _, ok := r.entities[id]; ok {
+			return utils.ErrConflict
+		}
+	}
+
+	if err := r.checkUnique(entity, id); err != nil {
+		return err
+	}
+
+	if id == 0 {
+		r.nextID++
+		id = r.nextID
+		if identifiable, ok := any(entity).(repositories.Identifiable); ok {
+			identifiable.SetID(id)
+		}
+	} else if id > r.nextID {
+		r.nextID = id
+	}
+
+	now := r.clock.Now()
+	if getTimeField(entity, "CreatedAt").IsZero() {
+		setTimeField(entity, "CreatedAt", now)
+	}
+	if getTimeField(entity, "UpdatedAt").IsZero() {
+		setTimeField(entity, "UpdatedAt", now)
+	}
+
+	r.entities[id] = *entity
+	return nil
+}
+
+// checkUnique verifies the unique fields of an entity against every other
+// stored entity. The caller must hold the lock.
+func (r *GenericMemoryRepository[T]) checkUnique(entity *T, id uint) error {
+	for _, field := range r.unique {
+		value := field.Value(entity)
+		for otherID, other := range r.entities {
+			if otherID != id && field.Value(&other) == value {
+				if field.Err != nil {
+					return field.Err
+				}
+				return utils.ErrConflict
+			}
+		}
+	}
+	return nil
+}
+
+// getTimeField returns a time.Time field of an entity, or the zero time
+func getTimeField[T any](entity *T, name string) time.Time {
+	field := reflect.ValueOf(entity).Elem().FieldByName(name)
+	if !field.IsValid() {
+		return time.Time{}
+	}
+	value, _ := field.Interface().(time.Time)
+	return value
+}
+
+// setTimeField sets a time.Time field of an entity when it exists
+func setTimeField[T any](entity *T, name string, value time.Time) {
+	field := reflect.ValueOf(entity).Elem().FieldByName(name)
+	if field.IsValid() && field.CanSet() && field.Type() == reflect.TypeOf(value) {
+		field.Set(reflect.ValueOf(value))
+	}
+}
@@ -0,0 +1,185 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/memory"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+)
+
+// epoch is the time fake clocks start at
+var epoch = time.Date(2030, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+// errURLTaken is returned for a subscription URL already in use
+var errURLTaken = errors.New("url taken")
+
+// newSubscriptions creates a repository of subscriptions with unique URLs
+func newSubscriptions(clk clock.Clock) repositories.GenericRepository[entities.WebhookSubscription] {
+	return memory.NewGenericMemoryRepository(
+		clk,
+		memory.UniqueField[entities.WebhookSubscription]{
+			Name:  "url",
+			Value: func(s *entities.WebhookSubscription) string { return s.URL },
+			Err:   errURLTaken,
+		},
+	)
+}
+
+// create stores a subscription to url
+func create(
+	t *testing.T,
+	repository repositories.GenericRepository[entities.WebhookSubscription],
+	url string,
+) *entities.WebhookSubscription {
+	t.Helper()
+	subscription := &entities.WebhookSubscription{URL: url}
+	if err := repository.Create(context.Background(), subscription); err != nil {
+		t.Fatalf("failed to create %s: %v", url, err)
+	}
+	return subscription
+}
+
+func TestGenericMemoryRepositoryCRUD(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewFake(epoch)
+	repository := newSubscriptions(clk)
+
+	first := create(t, repository, "https://a.example")
+	second := create(t, repository, "https://b.example")
+	if first.ID != 1 || second.ID != 2 {
+		t.Fatalf("expected IDs 1 and 2, got %d and %d", first.ID, second.ID)
+	}
+	if !first.CreatedAt.Equal(epoch) || !first.UpdatedAt.Equal(epoch) {
+		t.Errorf("expected the timestamps of the clock, got %+v", first)
+	}
+
+	found, err := repository.FindByID(ctx, 2)
+	if err != nil || found == nil || found.URL != "https://b.example" {
+		t.Fatalf("expected subscription 2, got %+v (%v)", found, err)
+	}
+	if found, err := repository.FindByID(ctx, 3); err != nil || found != nil {
+		t.Errorf("expected no subscription 3, got %+v (%v)", found, err)
+	}
+
+	// Returned entities are copies
+	found.URL = "https://changed.example"
+	if stored, _ := repository.FindByID(ctx, 2); stored.URL != "https://b.example" {
+		t.Errorf("expected the stored entity to be left as is, got %s", stored.URL)
+	}
+
+	clk.Advance(time.Hour)
+	first.Active = true
+	if err := repository.Update(ctx, first); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	updated, _ := repository.FindByID(ctx, 1)
+	if !updated.Active || !updated.CreatedAt.Equal(epoch) || !updated.UpdatedAt.Equal(epoch.Add(time.Hour)) {
+		t.Errorf("expected an update at %s keeping the creation time, got %+v", epoch.Add(time.Hour), updated)
+	}
+
+	if err := repository.Delete(ctx, 1); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	all, err := repository.FindAll(ctx)
+	if err != nil || len(all) != 1 || all[0].ID != 2 {
+		t.Errorf("expected only subscription 2 to be left, got %+v (%v)", all, err)
+	}
+
+	// IDs are not reused
+	if third := create(t, repository, "https://c.example"); third.ID != 3 {
+		t.Errorf("expected ID 3, got %d", third.ID)
+	}
+}
+
+func TestGenericMemoryRepositoryFindAllOrder(t *testing.T) {
+	repository := newSubscriptions(clock.NewFake(epoch))
+	for _, url := range []string{"https://a.example", "https://b.example", "https://c.example", "https://d.example"} {
+		create(t, repository, url)
+	}
+
+	all, err := repository.FindAll(context.Background())
+	if err != nil || len(all) != 4 {
+		t.Fatalf("expected 4 subscriptions, got %d (%v)", len(all), err)
+	}
+	for i, subscription := range all {
+		if subscription.ID != uint(i+1) {
+			t.Errorf("expected subscription %d at %d, got %d", i+1, i, subscription.ID)
+		}
+	}
+
+	first, err := repository.(*memory.GenericMemoryRepository[entities.WebhookSubscription]).FindFirst(
+		context.Background(),
+		func(s *entities.WebhookSubscription) bool { return s.ID > 2 },
+	)
+	if err != nil || first == nil || first.ID != 3 {
+		t.Errorf("expected the lowest matching ID, got %+v (%v)", first, err)
+	}
+}
+
+func TestGenericMemoryRepositoryUniqueFields(t *testing.T) {
+	ctx := context.Background()
+	repository := newSubscriptions(clock.NewFake(epoch))
+	first := create(t, repository, "https://a.example")
+	create(t, repository, "https://b.example")
+
+	if err := repository.Create(ctx, &entities.WebhookSubscription{URL: "https://a.example"}); !errors.Is(err, errURLTaken) {
+		t.Errorf("expected a taken URL to be rejected on create, got %v", err)
+	}
+
+	first.URL = "https://b.example"
+	if err := repository.Update(ctx, first); !errors.Is(err, errURLTaken) {
+		t.Errorf("expected a taken URL to be rejected on update, got %v", err)
+	}
+
+	// An entity does not collide with itself
+	first.URL = "https://a.example"
+	first.Active = true
+	if err := repository.Update(ctx, first); err != nil {
+		t.Errorf("expected an update keeping the URL to succeed, got %v", err)
+	}
+}
+
+func TestGenericMemoryRepositoryExplicitIDs(t *testing.T) {
+	ctx := context.Background()
+	repository := newSubscriptions(clock.NewFake(epoch))
+
+	if err := repository.Create(ctx, &entities.WebhookSubscription{ID: 5, URL: "https://a.example"}); err != nil {
+		t.Fatalf("failed to create with an ID: %v", err)
+	}
+	err := repository.Create(ctx, &entities.WebhookSubscription{ID: 5, URL: "https://b.example"})
+	if !errors.Is(err, utils.ErrConflict) {
+		t.Errorf("expected a taken ID to conflict, got %v", err)
+	}
+
+	// Later IDs follow the highest one
+	if next := create(t, repository, "https://c.example"); next.ID != 6 {
+		t.Errorf("expected ID 6, got %d", next.ID)
+	}
+
+	// Updating an unknown entity inserts it
+	if err := repository.Update(ctx, &entities.WebhookSubscription{ID: 9, URL: "https://d.example"}); err != nil {
+		t.Fatalf("failed to upsert: %v", err)
+	}
+	if found, _ := repository.FindByID(ctx, 9); found == nil || !found.CreatedAt.Equal(epoch) {
+		t.Errorf("expected subscription 9 to be created, got %+v", found)
+	}
+}
+
+func TestGenericMemoryRepositoryCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	repository := newSubscriptions(clock.NewFake(epoch))
+
+	if err := repository.Create(ctx, &entities.WebhookSubscription{URL: "https://a.example"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected create to fail, got %v", err)
+	}
+	if _, err := repository.FindAll(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected find all to fail, got %v", err)
+	}
+}
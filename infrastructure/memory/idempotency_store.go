@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+)
+
+// IdempotencyStore implements IdempotencyStore interface in memory
+type IdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]entities.IdempotencyRecord
+}
+
+// NewIdempotencyStore creates a new in-memory idempotency store
+func NewIdempotencyStore() repositories.IdempotencyStore {
+	return &IdempotencyStore{records: make(map[string]entities.IdempotencyRecord)}
+}
+
+// Reserve claims key unless an unexpired record already holds it
+func (s *IdempotencyStore) Reserve(
+	ctx context.Context,
+	key string,
+	requestHash string,
+	now time.Time,
+	ttl time.Duration,
+) (*entities.IdempotencyRecord, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now = now.UTC()
+	if existing, ok := s.records[key]; ok && existing.ExpiresAt.After(now) {
+		return &existing, false, nil
+	}
+
+	record := entities.IdempotencyRecord{
+		Key:          key,
+		RequestHash:  requestHash,
+		ResponseBody: []byte{},
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(ttl),
+	}
+	s.records[key] = record
+	return &record, true, nil
+}
+
+// Complete stores the response of the request that reserved key
+func (s *IdempotencyStore) Complete(
+	ctx context.Context,
+	key string,
+	statusCode int,
+	contentType string,
+	body []byte,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[key]
+	if !ok {
+		return nil
+	}
+	record.StatusCode = statusCode
+	record.ContentType = contentType
+	record.ResponseBody = append([]byte(nil), body...)
+	s.records[key] = record
+	return nil
+}
+
+// Release removes a reservation so that the request can be retried
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+	return nil
+}
+
+// DeleteExpired removes the records expired at now
+func (s *IdempotencyStore) DeleteExpired(
+	ctx context.Context,
+	now time.Time,
+) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for key, record := range s.records {
+		if !record.ExpiresAt.After(now) {
+			delete(s.records, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
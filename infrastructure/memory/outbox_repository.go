@@ -0,0 +1,166 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/domain/events"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+)
+
+// OutboxRepository implements OutboxRepository interface in memory
+type OutboxRepository struct {
+	mu       sync.Mutex
+	messages []entities.OutboxMessage
+	eventIDs map[string]bool
+	clock    clock.Clock
+}
+
+// NewOutboxRepository creates a new in-memory outbox repository
+// timestamping messages with clk
+func NewOutboxRepository(clk clock.Clock) repositories.OutboxRepository {
+	return &OutboxRepository{eventIDs: make(map[string]bool), clock: clk}
+}
+
+// Add stores events in the outbox
+func (r *OutboxRepository) Add(
+	ctx context.Context,
+	envelopes ...events.Envelope,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, envelope := range envelopes {
+		if r.eventIDs[envelope.ID] {
+			return utils.ErrConflict
+		}
+	}
+
+	now := r.clock.Now().UTC()
+	for _, envelope := range envelopes {
+		r.eventIDs[envelope.ID] = true
+		r.messages = append(
+			r.messages, entities.OutboxMessage{
+				ID:            uint(len(r.messages) + 1),
+				EventID:       envelope.ID,
+				EventType:     envelope.Type,
+				AggregateID:   envelope.AggregateID,
+				Payload:       string(envelope.Payload),
+				OccurredAt:    envelope.OccurredAt,
+				NextAttemptAt: envelope.OccurredAt,
+				CreatedAt:     now,
+			},
+		)
+	}
+	return nil
+}
+
+// ClaimDue returns undelivered messages that are due and leases them
+func (r *OutboxRepository) ClaimDue(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]entities.OutboxMessage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var claimed []entities.OutboxMessage
+	for i := range r.messages {
+		if len(claimed) >= limit {
+			break
+		}
+		message := &r.messages[i]
+		if message.DeliveredAt != nil || message.NextAttemptAt.After(now) {
+			continue
+		}
+		claimed = append(claimed, *message)
+		message.NextAttemptAt = now.Add(lease)
+	}
+	return claimed, nil
+}
+
+// MarkDelivered records a successful delivery
+func (r *OutboxRepository) MarkDelivered(
+	ctx context.Context,
+	id uint,
+	deliveredAt time.Time,
+) error {
+	return r.update(
+		ctx, id, func(message *entities.OutboxMessage) {
+			message.Attempts++
+			message.DeliveredAt = &deliveredAt
+			message.LastError = ""
+		},
+	)
+}
+
+// MarkFailed records a failed delivery and schedules the next attempt
+func (r *OutboxRepository) MarkFailed(
+	ctx context.Context,
+	id uint,
+	lastError string,
+	nextAttemptAt time.Time,
+) error {
+	return r.update(
+		ctx, id, func(message *entities.OutboxMessage) {
+			message.Attempts++
+			message.LastError = lastError
+			message.NextAttemptAt = nextAttemptAt
+		},
+	)
+}
+
+// OldestPending returns when the oldest undelivered message occurred
+func (r *OutboxRepository) OldestPending(ctx context.Context) (
+	*time.Time,
+	error,
+) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var oldest *time.Time
+	for _, message := range r.messages {
+		if message.DeliveredAt == nil && (oldest == nil || message.OccurredAt.Before(*oldest)) {
+			occurredAt := message.OccurredAt
+			oldest = &occurredAt
+		}
+	}
+	return oldest, nil
+}
+
+// update applies fn to the message with the given ID
+func (r *OutboxRepository) update(
+	ctx context.Context,
+	id uint,
+	fn func(message *entities.OutboxMessage),
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id == 0 || int(id) > len(r.messages) {
+		return utils.ErrNotFound
+	}
+	fn(&r.messages[id-1])
+	return nil
+}
@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+)
+
+// RateLimitStore implements RateLimitStore interface in memory; counters
+// are local to the process
+type RateLimitStore struct {
+	mu     sync.Mutex
+	states map[string]entities.RateLimitState
+}
+
+// NewRateLimitStore creates a new in-memory rate limit store
+func NewRateLimitStore() repositories.RateLimitStore {
+	return &RateLimitStore{states: make(map[string]entities.RateLimitState)}
+}
+
+// Update stores the state returned by fn while holding the store lock
+func (s *RateLimitStore) Update(
+	ctx context.Context,
+	key string,
+	now time.Time,
+	ttl time.Duration,
+	fn func(state []byte) ([]byte, error),
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now = now.UTC()
+	var state []byte
+	if current, ok := s.states[key]; ok && current.ExpiresAt.After(now) {
+		state = current.State
+	}
+
+	next, err := fn(state)
+	if err != nil {
+		return err
+	}
+
+	s.states[key] = entities.RateLimitState{
+		Key:       key,
+		State:     append([]byte(nil), next...),
+		ExpiresAt: now.Add(ttl),
+	}
+	return nil
+}
+
+// DeleteExpired removes the states expired at now
+func (s *RateLimitStore) DeleteExpired(
+	ctx context.Context,
+	now time.Time,
+) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for key, state := range s.states {
+		if !state.ExpiresAt.After(now) {
+			delete(s.states, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,34 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+)
+
+// UnitOfWork implements UnitOfWork interface for in-memory storage.
+// Units are serialized with each other, but changes made before a
+// failure are not rolled back.
+type UnitOfWork struct {
+	mu sync.Mutex
+}
+
+// NewUnitOfWork creates a new in-memory unit of work
+func NewUnitOfWork() repositories.UnitOfWork {
+	return &UnitOfWork{}
+}
+
+// Do runs fn while holding the unit of work lock
+func (u *UnitOfWork) Do(
+	ctx context.Context,
+	fn func(ctx context.Context) error,
+) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return fn(ctx)
+}
@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+)
+
+// UserEmailUnique enforces unique user emails like the users.email index
+func UserEmailUnique() UniqueField[entities.User] {
+	return UniqueField[entities.User]{
+		Name: "email",
+		Value: func(user *entities.User) string {
+			return user.Email.String()
+		},
+		Err: utils.ErrEmailAlreadyExists,
+	}
+}
+
+// NewGenericMemoryUserRepository creates a generic in-memory user repository
+// with the same unique constraints as the users table
+func NewGenericMemoryUserRepository(clk clock.Clock) repositories.GenericRepository[entities.User] {
+	return NewGenericMemoryRepository[entities.User](clk, UserEmailUnique())
+}
+
+// MemoryUserRepository implements UserRepository interface in memory
+type MemoryUserRepository struct {
+	store *GenericMemoryRepository[entities.User]
+}
+
+// NewMemoryUserRepository creates a new in-memory user repository
+func NewMemoryUserRepository(clk clock.Clock) repositories.UserRepository {
+	return &MemoryUserRepository{
+		store: NewGenericMemoryUserRepository(clk).(*GenericMemoryRepository[entities.User]),
+	}
+}
+
+// Create adds a new user to the store
+func (r *MemoryUserRepository) Create(
+	ctx context.Context,
+	user *entities.User,
+) error {
+	return r.store.Create(ctx, user)
+}
+
+// GetByID retrieves a user by ID
+func (r *MemoryUserRepository) GetByID(
+	ctx context.Context,
+	id uint,
+) (*entities.User, error) {
+	return r.store.FindByID(ctx, id)
+}
+
+// GetByEmail retrieves a user by email
+func (r *MemoryUserRepository) GetByEmail(
+	ctx context.Context,
+	email entities.Email,
+) (*entities.User, error) {
+	return r.store.FindFirst(
+		ctx, func(user *entities.User) bool {
+			return user.Email == email
+		},
+	)
+}
+
+// GetAll retrieves all users
+func (r *MemoryUserRepository) GetAll(ctx context.Context) (
+	[]entities.User,
+	error,
+) {
+	ptrUsers, err := r.store.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]entities.User, len(ptrUsers))
+	for i, u := range ptrUsers {
+		users[i] = *u
+	}
+	return users, nil
+}
+
+// Update updates an existing user
+func (r *MemoryUserRepository) Update(
+	ctx context.Context,
+	user *entities.User,
+) error {
+	return r.store.Update(ctx, user)
+}
+
+// Delete removes a user by ID
+func (r *MemoryUserRepository) Delete(ctx context.Context, id uint) error {
+	return r.store.Delete(ctx, id)
+}
@@ -0,0 +1,210 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+)
+
+// WebhookDeliveryRepository implements WebhookDeliveryRepository interface
+// in memory
+type WebhookDeliveryRepository struct {
+	mu         sync.Mutex
+	deliveries []entities.WebhookDelivery
+	clock      clock.Clock
+}
+
+// NewWebhookDeliveryRepository creates a new in-memory webhook delivery
+// repository timestamping deliveries with clk
+func NewWebhookDeliveryRepository(clk clock.Clock) repositories.WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{clock: clk}
+}
+
+// Enqueue stores pending deliveries, skipping the ones already recorded
+func (r *WebhookDeliveryRepository) Enqueue(
+	ctx context.Context,
+	deliveries ...entities.WebhookDelivery,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.clock.Now().UTC()
+	for _, delivery := range deliveries {
+		if r.exists(delivery.SubscriptionID, delivery.EventID) {
+			continue
+		}
+		delivery.ID = uint(len(r.deliveries) + 1)
+		delivery.CreatedAt = now
+		delivery.UpdatedAt = now
+		r.deliveries = append(r.deliveries, delivery)
+	}
+	return nil
+}
+
+// ClaimDue returns pending deliveries that are due and leases them
+func (r *WebhookDeliveryRepository) ClaimDue(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]entities.WebhookDelivery, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var claimed []entities.WebhookDelivery
+	for i := range r.deliveries {
+		if len(claimed) >= limit {
+			break
+		}
+		delivery := &r.deliveries[i]
+		if delivery.Status != entities.WebhookDeliveryPending || delivery.NextAttemptAt.After(now) {
+			continue
+		}
+		claimed = append(claimed, *delivery)
+		delivery.NextAttemptAt = now.Add(lease)
+	}
+	return claimed, nil
+}
+
+// MarkSucceeded records a successful attempt
+func (r *WebhookDeliveryRepository) MarkSucceeded(
+	ctx context.Context,
+	id uint,
+	responseStatus int,
+	deliveredAt time.Time,
+) error {
+	return r.update(
+		ctx, id, func(delivery *entities.WebhookDelivery) {
+			delivery.Status = entities.WebhookDeliverySucceeded
+			delivery.Attempts++
+			delivery.ResponseStatus = responseStatus
+			delivery.LastError = ""
+			delivery.DeliveredAt = &deliveredAt
+		},
+	)
+}
+
+// MarkFailed records a failed attempt and schedules the next one, or gives
+// up on the delivery when nextAttemptAt is nil
+func (r *WebhookDeliveryRepository) MarkFailed(
+	ctx context.Context,
+	id uint,
+	responseStatus int,
+	lastError string,
+	nextAttemptAt *time.Time,
+) error {
+	return r.update(
+		ctx, id, func(delivery *entities.WebhookDelivery) {
+			delivery.Attempts++
+			delivery.ResponseStatus = responseStatus
+			delivery.LastError = lastError
+			if nextAttemptAt != nil {
+				delivery.NextAttemptAt = *nextAttemptAt
+			} else {
+				delivery.Status = entities.WebhookDeliveryFailed
+			}
+		},
+	)
+}
+
+// FindByID returns a delivery, or nil if it does not exist
+func (r *WebhookDeliveryRepository) FindByID(
+	ctx context.Context,
+	id uint,
+) (*entities.WebhookDelivery, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id == 0 || int(id) > len(r.deliveries) {
+		return nil, nil
+	}
+	delivery := r.deliveries[id-1]
+	return &delivery, nil
+}
+
+// FindBySubscription returns the latest deliveries of a subscription
+func (r *WebhookDeliveryRepository) FindBySubscription(
+	ctx context.Context,
+	subscriptionID uint,
+	limit int,
+) ([]entities.WebhookDelivery, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deliveries := []entities.WebhookDelivery{}
+	for i := len(r.deliveries) - 1; i >= 0 && len(deliveries) < limit; i-- {
+		if r.deliveries[i].SubscriptionID == subscriptionID {
+			deliveries = append(deliveries, r.deliveries[i])
+		}
+	}
+	return deliveries, nil
+}
+
+// Replay schedules a delivery to be sent again
+func (r *WebhookDeliveryRepository) Replay(
+	ctx context.Context,
+	id uint,
+	now time.Time,
+) error {
+	return r.update(
+		ctx, id, func(delivery *entities.WebhookDelivery) {
+			delivery.Status = entities.WebhookDeliveryPending
+			delivery.Attempts = 0
+			delivery.NextAttemptAt = now
+			delivery.DeliveredAt = nil
+		},
+	)
+}
+
+// exists reports whether a delivery of the event to the subscription is
+// already recorded; the caller must hold the lock
+func (r *WebhookDeliveryRepository) exists(subscriptionID uint, eventID string) bool {
+	for _, delivery := range r.deliveries {
+		if delivery.SubscriptionID == subscriptionID && delivery.EventID == eventID {
+			return true
+		}
+	}
+	return false
+}
+
+// update applies fn to the delivery with the given ID
+func (r *WebhookDeliveryRepository) update(
+	ctx context.Context,
+	id uint,
+	fn func(delivery *entities.WebhookDelivery),
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id == 0 || int(id) > len(r.deliveries) {
+		return utils.ErrNotFound
+	}
+	fn(&r.deliveries[id-1])
+	r.deliveries[id-1].UpdatedAt = r.clock.Now().UTC()
+	return nil
+}
@@ -0,0 +1,29 @@
+package messaging
+
+import (
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/infrastructure/random"
+)
+
+// backoff returns the delay before the retry following the given number of
+// previous attempts: exponential growth from minDelay with jitter read from
+// source, capped at maxDelay. Without random bytes the delay is not jittered.
+func backoff(
+	attempts int,
+	minDelay, maxDelay time.Duration,
+	source random.Source,
+) time.Duration {
+	delay := minDelay
+	for i := 0; i < attempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	n, err := random.Uint64(source)
+	if err != nil {
+		return delay
+	}
+	return delay/2 + time.Duration(n%uint64(delay/2+1))
+}
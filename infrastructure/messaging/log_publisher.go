@@ -0,0 +1,58 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/EngenMe/go-clean-architecture/domain/events"
+	"github.com/EngenMe/go-clean-architecture/interfaces/messaging"
+)
+
+// LogPublisher implements Publisher interface by writing each event as a
+// JSON line to a writer such as stdout or an append-only file
+type LogPublisher struct {
+	mu     sync.Mutex
+	writer io.Writer
+}
+
+// NewLogPublisher creates a publisher writing JSON lines to w
+func NewLogPublisher(w io.Writer) messaging.Publisher {
+	return &LogPublisher{writer: w}
+}
+
+// NewFilePublisher creates a publisher appending JSON lines to the file at
+// path. The returned closer releases the file.
+func NewFilePublisher(path string) (messaging.Publisher, io.Closer, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open event file: %w", err)
+	}
+	return &LogPublisher{writer: file}, file, nil
+}
+
+// Publish writes the event envelope as a single JSON line
+func (p *LogPublisher) Publish(_ context.Context, envelope events.Envelope) error {
+	line, err := json.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("failed to encode event %s: %w", envelope.ID, err)
+	}
+	line = append(line, '\n')
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, err := p.writer.Write(line); err != nil {
+		return fmt.Errorf("failed to write event %s: %w", envelope.ID, err)
+	}
+	// Flush files so that an acknowledged event survives a crash
+	if file, ok := p.writer.(*os.File); ok && file != os.Stdout && file != os.Stderr {
+		if err := file.Sync(); err != nil {
+			return fmt.Errorf("failed to sync event %s: %w", envelope.ID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package messaging
+
+import (
+	"context"
+
+	"github.com/EngenMe/go-clean-architecture/domain/events"
+	"github.com/EngenMe/go-clean-architecture/interfaces/messaging"
+)
+
+// Notifier notifies the in-process handlers of a notification according
+// to its dynamic type, as the application mediator does
+type Notifier interface {
+	Publish(ctx context.Context, notification any) error
+}
+
+// MediatrPublisher implements Publisher interface by publishing events as
+// in-process notifications of an application mediator. Subscribers
+// register with mediator.Subscribe on that mediator for the concrete event
+// type, e.g. events.UserCreated, so that each application only notifies
+// its own handlers.
+type MediatrPublisher struct {
+	notifier Notifier
+}
+
+// NewMediatrPublisher creates a new publisher notifying the handlers of
+// notifier
+func NewMediatrPublisher(notifier Notifier) messaging.Publisher {
+	return &MediatrPublisher{notifier: notifier}
+}
+
+// Publish decodes the envelope and notifies the handlers of its event type
+func (p *MediatrPublisher) Publish(
+	ctx context.Context,
+	envelope events.Envelope,
+) error {
+	event, err := events.Decode(envelope)
+	if err != nil {
+		return err
+	}
+	return p.notifier.Publish(ctx, event)
+}
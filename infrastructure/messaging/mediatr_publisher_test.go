@@ -0,0 +1,80 @@
+package messaging_test
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/EngenMe/go-clean-architecture/application/mediator"
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/domain/events"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/messaging"
+)
+
+// recordingHandler records the users of the events it is notified of
+type recordingHandler struct {
+	users []uint
+	err   error
+}
+
+// Handle records the user of event
+func (h *recordingHandler) Handle(_ context.Context, event events.UserCreated) error {
+	h.users = append(h.users, event.User.ID)
+	return h.err
+}
+
+// userCreated returns the envelope of a user.created event
+func userCreated(t *testing.T, id uint) events.Envelope {
+	t.Helper()
+	envelope, err := events.NewEnvelope(
+		events.UserCreated{User: entities.UserDTO{ID: id}, OccurredAt: epoch},
+		rand.Reader,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return envelope
+}
+
+func TestMediatrPublisherNotifiesItsMediator(t *testing.T) {
+	ctx := context.Background()
+	first, second := mediator.New(), mediator.New()
+	handler, other := &recordingHandler{}, &recordingHandler{}
+	mediator.Subscribe[events.UserCreated](first, handler)
+	mediator.Subscribe[events.UserCreated](second, other)
+
+	if err := messaging.NewMediatrPublisher(first).Publish(ctx, userCreated(t, 1)); err != nil {
+		t.Fatalf("failed to publish: %v", err)
+	}
+	if len(handler.users) != 1 || handler.users[0] != 1 {
+		t.Errorf("expected the handler to be notified of user 1, got %v", handler.users)
+	}
+	if len(other.users) != 0 {
+		t.Errorf("expected the handlers of another mediator not to be notified, got %v", other.users)
+	}
+
+	// Events without subscribers are dropped
+	deleted, err := events.NewEnvelope(events.UserDeleted{UserID: 1, OccurredAt: epoch}, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := messaging.NewMediatrPublisher(first).Publish(ctx, deleted); err != nil {
+		t.Errorf("expected an event without subscribers to be dropped, got %v", err)
+	}
+}
+
+func TestMediatrPublisherStopsAtFirstError(t *testing.T) {
+	m := mediator.New()
+	failing, next := &recordingHandler{err: errors.New("unavailable")}, &recordingHandler{}
+	mediator.Subscribe[events.UserCreated](m, failing)
+	mediator.Subscribe[events.UserCreated](m, next)
+
+	err := messaging.NewMediatrPublisher(m).Publish(context.Background(), userCreated(t, 1))
+	if err == nil || err.Error() != "unavailable" {
+		t.Fatalf("expected the handler error, got %v", err)
+	}
+	if len(failing.users) != 1 || len(next.users) != 0 {
+		t.Errorf("expected the handlers after the failing one not to be notified, got %v and %v", failing.users, next.users)
+	}
+}
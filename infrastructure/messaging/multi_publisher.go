@@ -0,0 +1,35 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+
+	"github.com/EngenMe/go-clean-architecture/domain/events"
+	"github.com/EngenMe/go-clean-architecture/interfaces/messaging"
+)
+
+// MultiPublisher implements Publisher interface by fanning events out to
+// several publishers. A failure in any of them fails the delivery, so the
+// event is retried for all of them.
+type MultiPublisher struct {
+	publishers []messaging.Publisher
+}
+
+// NewMultiPublisher creates a publisher that delivers to every publisher
+func NewMultiPublisher(publishers ...messaging.Publisher) messaging.Publisher {
+	return &MultiPublisher{publishers: publishers}
+}
+
+// Publish delivers the event to every publisher and joins their errors
+func (p *MultiPublisher) Publish(
+	ctx context.Context,
+	envelope events.Envelope,
+) error {
+	var errs []error
+	for _, publisher := range p.publishers {
+		if err := publisher.Publish(ctx, envelope); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
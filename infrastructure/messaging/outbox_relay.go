@@ -0,0 +1,142 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/domain/events"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/random"
+	"github.com/EngenMe/go-clean-architecture/interfaces/messaging"
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+)
+
+// RelayOptions configures the outbox relay
+type RelayOptions struct {
+	PollInterval time.Duration // Delay between polls when the outbox is drained
+	BatchSize    int           // Maximum number of messages claimed per poll
+	Lease        time.Duration // How long a claimed message is hidden from other relays
+	MinBackoff   time.Duration // Delay before the first retry
+	MaxBackoff   time.Duration // Upper bound of the retry delay
+	Clock        clock.Clock   // Tells which messages are due and when to retry
+	Random       random.Source // Jitters the retry delays
+}
+
+// DefaultRelayOptions returns the default relay options
+func DefaultRelayOptions() RelayOptions {
+	return RelayOptions{
+		PollInterval: time.Second,
+		BatchSize:    100,
+		Lease:        time.Minute,
+		MinBackoff:   time.Second,
+		MaxBackoff:   time.Hour,
+		Clock:        clock.System,
+		Random:       random.Crypto,
+	}
+}
+
+// OutboxRelay delivers outbox messages through a publisher with
+// at-least-once semantics: a message is marked delivered only after the
+// publisher succeeded, and failed deliveries are retried with exponential
+// backoff and jitter
+type OutboxRelay struct {
+	outbox    repositories.OutboxRepository
+	publisher messaging.Publisher
+	options   RelayOptions
+}
+
+// NewOutboxRelay creates a new outbox relay
+func NewOutboxRelay(
+	outbox repositories.OutboxRepository,
+	publisher messaging.Publisher,
+	options RelayOptions,
+) *OutboxRelay {
+	return &OutboxRelay{
+		outbox:    outbox,
+		publisher: publisher,
+		options:   options,
+	}
+}
+
+// Run relays messages until ctx is canceled
+func (r *OutboxRelay) Run(ctx context.Context) {
+	slog.InfoContext(
+		ctx,
+		"Outbox relay started",
+		slog.Duration("poll_interval", r.options.PollInterval),
+	)
+	for {
+		count, err := r.RelayOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "Outbox relay failed", slog.String("error", err.Error()))
+		}
+
+		// Keep draining while full batches come back
+		if count == r.options.BatchSize && err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			slog.Info("Outbox relay stopped")
+			return
+		case <-time.After(r.options.PollInterval):
+		}
+	}
+}
+
+// RelayOnce claims one batch of due messages and publishes them, returning
+// the number of messages claimed
+func (r *OutboxRelay) RelayOnce(ctx context.Context) (int, error) {
+	messages, err := r.outbox.ClaimDue(
+		ctx,
+		r.options.Clock.Now(),
+		r.options.Lease,
+		r.options.BatchSize,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, message := range messages {
+		if err := r.deliver(ctx, message); err != nil {
+			return len(messages), err
+		}
+	}
+	return len(messages), nil
+}
+
+// deliver publishes a single message and records the outcome
+func (r *OutboxRelay) deliver(
+	ctx context.Context,
+	message entities.OutboxMessage,
+) error {
+	envelope := events.Envelope{
+		ID:          message.EventID,
+		Type:        message.EventType,
+		AggregateID: message.AggregateID,
+		OccurredAt:  message.OccurredAt,
+		Payload:     json.RawMessage(message.Payload),
+	}
+
+	if err := r.publisher.Publish(ctx, envelope); err != nil {
+		nextAttemptAt := r.options.Clock.Now().Add(
+			backoff(message.Attempts, r.options.MinBackoff, r.options.MaxBackoff, r.options.Random),
+		)
+		slog.WarnContext(
+			ctx,
+			"Outbox delivery failed",
+			slog.String("event_type", message.EventType),
+			slog.String("event_id", message.EventID),
+			slog.Int("attempt", message.Attempts+1),
+			slog.Time("retry_at", nextAttemptAt),
+			slog.String("error", err.Error()),
+		)
+		return r.outbox.MarkFailed(ctx, message.ID, err.Error(), nextAttemptAt)
+	}
+
+	return r.outbox.MarkDelivered(ctx, message.ID, r.options.Clock.Now())
+}
@@ -0,0 +1,221 @@
+package messaging
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/random"
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+)
+
+// Webhook request headers
+const (
+	WebhookIDHeader        = "X-Webhook-Id"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// WebhookOptions configures the webhook dispatcher
+type WebhookOptions struct {
+	PollInterval time.Duration // Delay between polls when no delivery is due
+	BatchSize    int           // Maximum number of deliveries claimed per poll
+	Lease        time.Duration // How long a claimed delivery is hidden from other dispatchers
+	Timeout      time.Duration // Timeout of a single HTTP request
+	MaxAttempts  int           // Attempts after which a delivery is marked failed
+	MinBackoff   time.Duration // Delay before the first retry
+	MaxBackoff   time.Duration // Upper bound of the retry delay
+	Clock        clock.Clock   // Tells which deliveries are due and timestamps requests
+	Random       random.Source // Jitters the retry delays
+}
+
+// DefaultWebhookOptions returns the default webhook dispatcher options
+func DefaultWebhookOptions() WebhookOptions {
+	return WebhookOptions{
+		PollInterval: time.Second,
+		BatchSize:    50,
+		Lease:        time.Minute,
+		Timeout:      10 * time.Second,
+		MaxAttempts:  10,
+		MinBackoff:   10 * time.Second,
+		MaxBackoff:   6 * time.Hour,
+		Clock:        clock.System,
+		Random:       random.Crypto,
+	}
+}
+
+// WebhookDispatcher sends pending webhook deliveries as signed HTTP POST
+// requests and retries failed ones with exponential backoff
+type WebhookDispatcher struct {
+	subscriptions repositories.GenericRepository[entities.WebhookSubscription]
+	deliveries    repositories.WebhookDeliveryRepository
+	client        *http.Client
+	options       WebhookOptions
+}
+
+// NewWebhookDispatcher creates a new webhook dispatcher
+func NewWebhookDispatcher(
+	subscriptions repositories.GenericRepository[entities.WebhookSubscription],
+	deliveries repositories.WebhookDeliveryRepository,
+	options WebhookOptions,
+) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		subscriptions: subscriptions,
+		deliveries:    deliveries,
+		client:        &http.Client{Timeout: options.Timeout},
+		options:       options,
+	}
+}
+
+// SignWebhook returns the signature header value of a webhook request:
+// "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>" keyed
+// with the subscription secret
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Run dispatches deliveries until ctx is canceled
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	slog.InfoContext(
+		ctx,
+		"Webhook dispatcher started",
+		slog.Duration("poll_interval", d.options.PollInterval),
+	)
+	for {
+		count, err := d.DispatchOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "Webhook dispatcher failed", slog.String("error", err.Error()))
+		}
+
+		// Keep draining while full batches come back
+		if count == d.options.BatchSize && err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			slog.Info("Webhook dispatcher stopped")
+			return
+		case <-time.After(d.options.PollInterval):
+		}
+	}
+}
+
+// DispatchOnce claims one batch of due deliveries and sends them, returning
+// the number of deliveries claimed
+func (d *WebhookDispatcher) DispatchOnce(ctx context.Context) (int, error) {
+	deliveries, err := d.deliveries.ClaimDue(
+		ctx,
+		d.options.Clock.Now(),
+		d.options.Lease,
+		d.options.BatchSize,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, delivery := range deliveries {
+		if err := d.dispatch(ctx, delivery); err != nil {
+			return len(deliveries), err
+		}
+	}
+	return len(deliveries), nil
+}
+
+// dispatch sends a single delivery and records the outcome
+func (d *WebhookDispatcher) dispatch(
+	ctx context.Context,
+	delivery entities.WebhookDelivery,
+) error {
+	subscription, err := d.subscriptions.FindByID(ctx, delivery.SubscriptionID)
+	if err != nil {
+		return err
+	}
+	if subscription == nil || !subscription.Active {
+		return d.deliveries.MarkFailed(
+			ctx,
+			delivery.ID,
+			0,
+			"subscription deleted or inactive",
+			nil,
+		)
+	}
+
+	status, err := d.send(ctx, subscription, delivery)
+	if err == nil {
+		return d.deliveries.MarkSucceeded(ctx, delivery.ID, status, d.options.Clock.Now())
+	}
+
+	var nextAttemptAt *time.Time
+	if delivery.Attempts+1 < d.options.MaxAttempts {
+		next := d.options.Clock.Now().Add(
+			backoff(delivery.Attempts, d.options.MinBackoff, d.options.MaxBackoff, d.options.Random),
+		)
+		nextAttemptAt = &next
+	}
+	slog.WarnContext(
+		ctx,
+		"Webhook delivery failed",
+		slog.Uint64("delivery_id", uint64(delivery.ID)),
+		slog.String("event_type", delivery.EventType),
+		slog.String("url", subscription.URL),
+		slog.Int("attempt", delivery.Attempts+1),
+		slog.String("error", err.Error()),
+	)
+	return d.deliveries.MarkFailed(ctx, delivery.ID, status, err.Error(), nextAttemptAt)
+}
+
+// send posts the signed delivery payload and returns the response status.
+// Any non-2xx response is an error.
+func (d *WebhookDispatcher) send(
+	ctx context.Context,
+	subscription *entities.WebhookSubscription,
+	delivery entities.WebhookDelivery,
+) (int, error) {
+	body := []byte(delivery.Payload)
+	timestamp := d.options.Clock.Now().Unix()
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		subscription.URL,
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("invalid webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go-clean-architecture-webhooks")
+	req.Header.Set(WebhookIDHeader, delivery.EventID)
+	req.Header.Set(WebhookEventHeader, delivery.EventType)
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(subscription.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Drain a bounded amount of the body so that the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
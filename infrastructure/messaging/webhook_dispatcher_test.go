@@ -0,0 +1,302 @@
+package messaging_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/memory"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/messaging"
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+)
+
+// epoch is the time fake clocks start at
+var epoch = time.Date(2030, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+// secret is the HMAC key of the test subscription
+const secret = "webhook-secret"
+
+// payload is the body of the test delivery
+const payload = `{"id":"event-1","type":"user.created","data":{"id":1}}`
+
+// zeroSource reads zeros, so that backoff delays have no jitter
+type zeroSource struct{}
+
+// Read fills p with zeros
+func (zeroSource) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+// webhookRequest is a request the receiver got
+type webhookRequest struct {
+	header http.Header
+	body   string
+}
+
+// receiver is a webhook endpoint answering with a configurable status
+type receiver struct {
+	server   *httptest.Server
+	mu       sync.Mutex
+	status   int
+	requests []webhookRequest
+}
+
+// newReceiver starts a receiver answering 204 until told otherwise
+func newReceiver(t *testing.T) *receiver {
+	t.Helper()
+	r := &receiver{status: http.StatusNoContent}
+	r.server = httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, req *http.Request) {
+				body, _ := io.ReadAll(req.Body)
+				r.mu.Lock()
+				defer r.mu.Unlock()
+				r.requests = append(r.requests, webhookRequest{header: req.Header.Clone(), body: string(body)})
+				w.WriteHeader(r.status)
+			},
+		),
+	)
+	t.Cleanup(r.server.Close)
+	return r
+}
+
+// respond makes the receiver answer with status from now on
+func (r *receiver) respond(status int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+}
+
+// received returns the requests received so far
+func (r *receiver) received() []webhookRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]webhookRequest(nil), r.requests...)
+}
+
+// dispatcherTest is a dispatcher sending one delivery to a receiver
+type dispatcherTest struct {
+	t          *testing.T
+	clock      *clock.Fake
+	receiver   *receiver
+	deliveries repositories.WebhookDeliveryRepository
+	dispatcher *messaging.WebhookDispatcher
+}
+
+// newDispatcherTest enqueues a delivery to a new receiver, for a dispatcher
+// making up to 4 attempts with a backoff from 20s to 2m and no jitter
+func newDispatcherTest(t *testing.T) *dispatcherTest {
+	t.Helper()
+	ctx := context.Background()
+	clk := clock.NewFake(epoch)
+	r := newReceiver(t)
+
+	subscriptions := memory.NewGenericMemoryRepository[entities.WebhookSubscription](clk)
+	subscription := &entities.WebhookSubscription{
+		URL:        r.server.URL,
+		EventTypes: entities.WebhookAllEvents,
+		Secret:     secret,
+		Active:     true,
+	}
+	if err := subscriptions.Create(ctx, subscription); err != nil {
+		t.Fatalf("failed to create the subscription: %v", err)
+	}
+
+	deliveries := memory.NewWebhookDeliveryRepository(clk)
+	if err := deliveries.Enqueue(
+		ctx,
+		entities.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventID:        "event-1",
+			EventType:      "user.created",
+			Payload:        payload,
+			Status:         entities.WebhookDeliveryPending,
+			NextAttemptAt:  epoch,
+		},
+	); err != nil {
+		t.Fatalf("failed to enqueue the delivery: %v", err)
+	}
+
+	options := messaging.DefaultWebhookOptions()
+	options.MaxAttempts = 4
+	options.MinBackoff = 20 * time.Second
+	options.MaxBackoff = 2 * time.Minute
+	options.Clock = clk
+	options.Random = zeroSource{}
+	return &dispatcherTest{
+		t:          t,
+		clock:      clk,
+		receiver:   r,
+		deliveries: deliveries,
+		dispatcher: messaging.NewWebhookDispatcher(subscriptions, deliveries, options),
+	}
+}
+
+// dispatch runs the dispatcher once and returns how many deliveries it sent
+func (d *dispatcherTest) dispatch() int {
+	d.t.Helper()
+	count, err := d.dispatcher.DispatchOnce(context.Background())
+	if err != nil {
+		d.t.Fatalf("failed to dispatch: %v", err)
+	}
+	return count
+}
+
+// delivery returns the stored delivery
+func (d *dispatcherTest) delivery() entities.WebhookDelivery {
+	d.t.Helper()
+	delivery, err := d.deliveries.FindByID(context.Background(), 1)
+	if err != nil || delivery == nil {
+		d.t.Fatalf("failed to find the delivery: %v", err)
+	}
+	return *delivery
+}
+
+func TestWebhookDispatcherSignsRequests(t *testing.T) {
+	d := newDispatcherTest(t)
+	if count := d.dispatch(); count != 1 {
+		t.Fatalf("expected a delivery to be sent, got %d", count)
+	}
+
+	requests := d.receiver.received()
+	if len(requests) != 1 {
+		t.Fatalf("expected a request, got %d", len(requests))
+	}
+	request := requests[0]
+	if request.body != payload {
+		t.Errorf("expected the payload %s, got %s", payload, request.body)
+	}
+	timestamp := strconv.FormatInt(epoch.Unix(), 10)
+	headers := map[string]string{
+		"Content-Type":                   "application/json",
+		messaging.WebhookIDHeader:        "event-1",
+		messaging.WebhookEventHeader:     "user.created",
+		messaging.WebhookTimestampHeader: timestamp,
+	}
+	for name, want := range headers {
+		if got := request.header.Get(name); got != want {
+			t.Errorf("expected %s %q, got %q", name, want, got)
+		}
+	}
+
+	// Receivers verify the HMAC-SHA256 of "<timestamp>.<body>"
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + request.body))
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if got := request.header.Get(messaging.WebhookSignatureHeader); got != want {
+		t.Errorf("expected the signature %q, got %q", want, got)
+	}
+
+	delivery := d.delivery()
+	if delivery.Status != entities.WebhookDeliverySucceeded ||
+		delivery.Attempts != 1 ||
+		delivery.ResponseStatus != http.StatusNoContent ||
+		delivery.DeliveredAt == nil ||
+		!delivery.DeliveredAt.Equal(epoch) {
+		t.Errorf("expected the delivery to succeed at %s, got %+v", epoch, delivery)
+	}
+}
+
+func TestWebhookDispatcherRetrySchedule(t *testing.T) {
+	d := newDispatcherTest(t)
+	d.receiver.respond(http.StatusServiceUnavailable)
+
+	// Without jitter, retries wait half of the doubling delay: 10s, 20s, 40s
+	for attempt, wait := range []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second} {
+		if count := d.dispatch(); count != 1 {
+			t.Fatalf("attempt %d: expected the delivery to be sent, got %d", attempt+1, count)
+		}
+		delivery := d.delivery()
+		next := d.clock.Now().Add(wait)
+		if delivery.Status != entities.WebhookDeliveryPending ||
+			delivery.Attempts != attempt+1 ||
+			delivery.ResponseStatus != http.StatusServiceUnavailable ||
+			!delivery.NextAttemptAt.Equal(next) {
+			t.Fatalf("attempt %d: expected a retry at %s, got %+v", attempt+1, next, delivery)
+		}
+
+		// Nothing is due until the retry time
+		d.clock.Advance(wait - time.Second)
+		if count := d.dispatch(); count != 0 {
+			t.Fatalf("attempt %d: expected no delivery before the retry, got %d", attempt+1, count)
+		}
+		d.clock.Advance(time.Second)
+	}
+	if got := len(d.receiver.received()); got != 3 {
+		t.Errorf("expected 3 requests, got %d", got)
+	}
+}
+
+func TestWebhookDispatcherGivesUp(t *testing.T) {
+	d := newDispatcherTest(t)
+	d.receiver.respond(http.StatusInternalServerError)
+
+	// The delivery is marked failed after MaxAttempts attempts
+	for range 4 {
+		if count := d.dispatch(); count != 1 {
+			t.Fatalf("expected the delivery to be sent, got %d", count)
+		}
+		d.clock.Advance(time.Hour)
+	}
+	delivery := d.delivery()
+	if delivery.Status != entities.WebhookDeliveryFailed ||
+		delivery.Attempts != 4 ||
+		delivery.ResponseStatus != http.StatusInternalServerError ||
+		delivery.LastError == "" {
+		t.Fatalf("expected the delivery to fail after 4 attempts, got %+v", delivery)
+	}
+
+	// Failed deliveries are not retried
+	if count := d.dispatch(); count != 0 {
+		t.Errorf("expected no more attempts, got %d", count)
+	}
+	if got := len(d.receiver.received()); got != 4 {
+		t.Errorf("expected 4 requests, got %d", got)
+	}
+}
+
+func TestWebhookDispatcherReplay(t *testing.T) {
+	d := newDispatcherTest(t)
+	d.dispatch()
+
+	// A replayed delivery is sent again with the stored payload, signed
+	// with the time of the replay
+	d.clock.Advance(time.Hour)
+	if err := d.deliveries.Replay(context.Background(), 1, d.clock.Now()); err != nil {
+		t.Fatalf("failed to replay the delivery: %v", err)
+	}
+	if count := d.dispatch(); count != 1 {
+		t.Fatalf("expected the replayed delivery to be sent, got %d", count)
+	}
+
+	requests := d.receiver.received()
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+	replayed := requests[1]
+	if replayed.body != requests[0].body || replayed.body != payload {
+		t.Errorf("expected the stored payload to be sent again, got %s", replayed.body)
+	}
+	replayedAt := d.clock.Now().Unix()
+	if got, want := replayed.header.Get(messaging.WebhookSignatureHeader),
+		messaging.SignWebhook(secret, replayedAt, []byte(payload)); got != want {
+		t.Errorf("expected the signature %q, got %q", want, got)
+	}
+	delivery := d.delivery()
+	if delivery.Status != entities.WebhookDeliverySucceeded ||
+		delivery.Attempts != 1 ||
+		!delivery.DeliveredAt.Equal(d.clock.Now()) {
+		t.Errorf("expected the replay to succeed, got %+v", delivery)
+	}
+}
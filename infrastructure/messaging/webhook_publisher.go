@@ -0,0 +1,73 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/domain/events"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
+	"github.com/EngenMe/go-clean-architecture/interfaces/messaging"
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+)
+
+// WebhookPublisher implements Publisher interface by recording a pending
+// delivery for every active webhook subscribed to the event. The
+// WebhookDispatcher sends them afterwards, so a slow partner never holds
+// up the outbox.
+type WebhookPublisher struct {
+	subscriptions repositories.GenericRepository[entities.WebhookSubscription]
+	deliveries    repositories.WebhookDeliveryRepository
+	clock         clock.Clock
+}
+
+// NewWebhookPublisher creates a new webhook publisher whose deliveries
+// are due as soon as clk records them
+func NewWebhookPublisher(
+	subscriptions repositories.GenericRepository[entities.WebhookSubscription],
+	deliveries repositories.WebhookDeliveryRepository,
+	clk clock.Clock,
+) messaging.Publisher {
+	return &WebhookPublisher{
+		subscriptions: subscriptions,
+		deliveries:    deliveries,
+		clock:         clk,
+	}
+}
+
+// Publish enqueues the event for the matching subscriptions
+func (p *WebhookPublisher) Publish(
+	ctx context.Context,
+	envelope events.Envelope,
+) error {
+	subscriptions, err := p.subscriptions.FindAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load webhook subscriptions: %w", err)
+	}
+
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("failed to encode event %s: %w", envelope.ID, err)
+	}
+
+	now := p.clock.Now().UTC()
+	var deliveries []entities.WebhookDelivery
+	for _, subscription := range subscriptions {
+		if !subscription.Active || !subscription.Subscribes(envelope.Type) {
+			continue
+		}
+		deliveries = append(
+			deliveries, entities.WebhookDelivery{
+				SubscriptionID: subscription.ID,
+				EventID:        envelope.ID,
+				EventType:      envelope.Type,
+				Payload:        string(payload),
+				Status:         entities.WebhookDeliveryPending,
+				NextAttemptAt:  now,
+			},
+		)
+	}
+
+	return p.deliveries.Enqueue(ctx, deliveries...)
+}
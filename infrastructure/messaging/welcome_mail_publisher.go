@@ -0,0 +1,57 @@
+package messaging
+
+import (
+	"context"
+
+	"github.com/EngenMe/go-clean-architecture/domain/events"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/i18n"
+	"github.com/EngenMe/go-clean-architecture/interfaces/messaging"
+)
+
+// WelcomeMailPublisher implements Publisher interface by sending a welcome
+// email, in the user's preferred language, for each created user. Other
+// events are ignored.
+type WelcomeMailPublisher struct {
+	mailer messaging.Mailer
+}
+
+// NewWelcomeMailPublisher creates a publisher sending welcome emails
+func NewWelcomeMailPublisher(mailer messaging.Mailer) messaging.Publisher {
+	return &WelcomeMailPublisher{mailer: mailer}
+}
+
+// Publish sends the welcome email of a user.created event
+func (p *WelcomeMailPublisher) Publish(
+	ctx context.Context,
+	envelope events.Envelope,
+) error {
+	if envelope.Type != events.UserCreatedType {
+		return nil
+	}
+
+	event, err := events.Decode(envelope)
+	if err != nil {
+		return err
+	}
+	user := event.(events.UserCreated).User
+
+	// Unknown or missing locales fall back to English in Translate
+	locale := user.PreferredLocale
+	return p.mailer.Send(
+		ctx, messaging.Email{
+			To: user.Email,
+			Subject: i18n.Translate(
+				locale,
+				"mail.welcome.subject",
+				"firstName", user.FirstName,
+			),
+			Body: i18n.Translate(
+				locale,
+				"mail.welcome.body",
+				"firstName", user.FirstName,
+				"lastName", user.LastName,
+				"email", user.Email,
+			),
+		},
+	)
+}
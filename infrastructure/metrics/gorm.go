@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"gorm.io/gorm"
+)
+
+// queryStartKey is the statement setting holding the start of a query
+const queryStartKey = "metrics:query_start"
+
+// InstrumentDB times the queries of db through GORM callbacks and exports
+// the statistics of its connection pool
+func (m *Metrics) InstrumentDB(db *gorm.DB, name string) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to instrument database: %w", err)
+	}
+	if err := m.registry.Register(collectors.NewDBStatsCollector(sqlDB, name)); err != nil {
+		return fmt.Errorf("failed to register database pool metrics: %w", err)
+	}
+	if err := db.Use(&gormPlugin{metrics: m}); err != nil {
+		return fmt.Errorf("failed to register database query metrics: %w", err)
+	}
+	return nil
+}
+
+// gormPlugin implements GORM's Plugin interface by timing every query
+type gormPlugin struct {
+	metrics *Metrics
+}
+
+// Name returns the name of the plugin
+func (p *gormPlugin) Name() string {
+	return "metrics"
+}
+
+// Initialize registers the timing callbacks first and last around each
+// kind of operation
+func (p *gormPlugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().Before("*").Register("metrics:before_create", startQuery),
+		callbacks.Create().After("*").Register("metrics:after_create", p.observe("create")),
+		callbacks.Query().Before("*").Register("metrics:before_query", startQuery),
+		callbacks.Query().After("*").Register("metrics:after_query", p.observe("query")),
+		callbacks.Update().Before("*").Register("metrics:before_update", startQuery),
+		callbacks.Update().After("*").Register("metrics:after_update", p.observe("update")),
+		callbacks.Delete().Before("*").Register("metrics:before_delete", startQuery),
+		callbacks.Delete().After("*").Register("metrics:after_delete", p.observe("delete")),
+		callbacks.Row().Before("*").Register("metrics:before_row", startQuery),
+		callbacks.Row().After("*").Register("metrics:after_row", p.observe("row")),
+		callbacks.Raw().Before("*").Register("metrics:before_raw", startQuery),
+		callbacks.Raw().After("*").Register("metrics:after_raw", p.observe("raw")),
+	)
+}
+
+// startQuery records when the query started
+func startQuery(db *gorm.DB) {
+	db.InstanceSet(queryStartKey, time.Now())
+}
+
+// observe returns a callback recording the duration of an operation
+func (p *gormPlugin) observe(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(queryStartKey)
+		if !ok {
+			return
+		}
+		start, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+
+		// A missing record is an expected result rather than a failure
+		err := db.Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = nil
+		}
+
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+		p.metrics.queryDuration.
+			WithLabelValues(operation, table, outcome(err)).
+			Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,181 @@
+// Package metrics exposes the application metrics in the Prometheus text
+// format: HTTP requests, mediatr requests, database queries and connection
+// pool, and authentication outcomes.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes the names of the application metrics
+const namespace = "app"
+
+// Metrics holds the Prometheus collectors of the application. It
+// implements the recorder interfaces of the mediatr pipeline, the HTTP
+// middlewares and the auth service. A nil *Metrics records nothing.
+type Metrics struct {
+	registry        *prometheus.Registry
+	httpDuration    *prometheus.HistogramVec
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	queryDuration   *prometheus.HistogramVec
+	logins          *prometheus.CounterVec
+	tokenRejections *prometheus.CounterVec
+	signups         prometheus.Counter
+}
+
+// New creates the application metrics in a dedicated registry, along with
+// the Go runtime and process collectors
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "http_request_duration_seconds",
+				Help:      "Duration of HTTP requests by route template, method and status.",
+				Buckets:   prometheus.DefBuckets,
+			},
+			[]string{"route", "method", "status"},
+		),
+		requests: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "mediatr_requests_total",
+				Help:      "Commands and queries handled by type and outcome.",
+			},
+			[]string{"request", "outcome"},
+		),
+		requestDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "mediatr_request_duration_seconds",
+				Help:      "Duration of commands and queries by type.",
+				Buckets:   prometheus.DefBuckets,
+			},
+			[]string{"request"},
+		),
+		queryDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "db_query_duration_seconds",
+				Help:      "Duration of database queries by operation, table and outcome.",
+				Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+			},
+			[]string{"operation", "table", "outcome"},
+		),
+		logins: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "auth_logins_total",
+				Help:      "Login attempts by outcome.",
+			},
+			[]string{"outcome"},
+		),
+		tokenRejections: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "auth_token_rejections_total",
+				Help:      "Requests to protected routes rejected for a missing or invalid token, by reason.",
+			},
+			[]string{"reason"},
+		),
+		signups: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "auth_signups_total",
+				Help:      "Users registered through sign-up.",
+			},
+		),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpDuration,
+		m.requests,
+		m.requestDuration,
+		m.queryDuration,
+		m.logins,
+		m.tokenRejections,
+		m.signups,
+	)
+
+	// Export the outcomes with no observations yet as zeros
+	m.logins.WithLabelValues("success")
+	m.logins.WithLabelValues("failure")
+
+	return m
+}
+
+// Handler serves the metrics in the Prometheus text format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveHTTPRequest records a handled HTTP request. route is the route
+// template, e.g. /api/v1/users/:id, so that IDs do not create new series.
+func (m *Metrics) ObserveHTTPRequest(
+	method string,
+	route string,
+	status int,
+	duration time.Duration,
+) {
+	if m == nil {
+		return
+	}
+	m.httpDuration.
+		WithLabelValues(route, method, strconv.Itoa(status)).
+		Observe(duration.Seconds())
+}
+
+// ObserveRequest records a command or query handled by mediatr
+func (m *Metrics) ObserveRequest(request string, duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	m.requests.WithLabelValues(request, outcome(err)).Inc()
+	m.requestDuration.WithLabelValues(request).Observe(duration.Seconds())
+}
+
+// LoginSucceeded counts a successful login
+func (m *Metrics) LoginSucceeded() {
+	if m != nil {
+		m.logins.WithLabelValues("success").Inc()
+	}
+}
+
+// LoginFailed counts a login rejected for invalid credentials
+func (m *Metrics) LoginFailed() {
+	if m != nil {
+		m.logins.WithLabelValues("failure").Inc()
+	}
+}
+
+// SignedUp counts a user registered through sign-up
+func (m *Metrics) SignedUp() {
+	if m != nil {
+		m.signups.Inc()
+	}
+}
+
+// TokenRejected counts a request rejected by the authentication middleware
+func (m *Metrics) TokenRejected(reason string) {
+	if m != nil {
+		m.tokenRejections.WithLabelValues(reason).Inc()
+	}
+}
+
+// outcome labels the result of an operation
+func outcome(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
@@ -0,0 +1,61 @@
+// Package random abstracts the source of the random bytes behind event
+// IDs and generated secrets, so that tests can make them deterministic.
+package random
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	mathrand "math/rand/v2"
+	"sync"
+)
+
+// Source fills byte slices with random bytes, as io.Reader does
+type Source interface {
+	Read(p []byte) (int, error)
+}
+
+// Crypto is the cryptographically secure source of the operating system
+var Crypto Source = rand.Reader
+
+// Hex returns n random bytes read from source, hex encoded
+func Hex(source Source, n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(source, b); err != nil {
+		return "", fmt.Errorf("failed to read random bytes: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Uint64 returns a random integer read from source
+func Uint64(source Source) (uint64, error) {
+	var b [8]byte
+	if _, err := io.ReadFull(source, b[:]); err != nil {
+		return 0, fmt.Errorf("failed to read random bytes: %w", err)
+	}
+	return binary.LittleEndian.Uint64(b[:]), nil
+}
+
+// Seeded is a deterministic source: two sources created with the same
+// seed return the same bytes. It is meant for tests only, and is safe
+// for concurrent use.
+type Seeded struct {
+	mu     sync.Mutex
+	chacha *mathrand.ChaCha8
+}
+
+// NewSeeded creates a deterministic source from seed
+func NewSeeded(seed uint64) *Seeded {
+	var key [32]byte
+	binary.LittleEndian.PutUint64(key[:], seed)
+	return &Seeded{chacha: mathrand.NewChaCha8(key)}
+}
+
+// Read fills p with the next bytes of the sequence
+func (s *Seeded) Read(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.chacha.Read(p)
+}
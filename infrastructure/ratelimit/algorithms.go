@@ -0,0 +1,122 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"math"
+	"time"
+)
+
+// Decision is the outcome of a request against a policy
+type Decision struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration // Until the quota is fully available again
+	RetryAfter time.Duration // Until the next request is allowed, when denied
+}
+
+// tokenBucketState is the stored state of a token bucket
+type tokenBucketState struct {
+	Tokens  float64 `json:"tokens"`
+	Updated int64   `json:"updated"` // Unix nanoseconds
+}
+
+// takeTokenBucket refills the bucket for the time elapsed since the last
+// request and takes a token from it
+func takeTokenBucket(raw []byte, p Policy, now time.Time) (Decision, []byte, error) {
+	limit := float64(p.Limit)
+	rate := limit / p.Period.Seconds() // Tokens per second
+
+	state := tokenBucketState{Tokens: limit, Updated: now.UnixNano()}
+	if raw != nil {
+		if err := json.Unmarshal(raw, &state); err != nil {
+			return Decision{}, nil, err
+		}
+		elapsed := now.Sub(time.Unix(0, state.Updated)).Seconds()
+		if elapsed > 0 {
+			state.Tokens = math.Min(limit, state.Tokens+elapsed*rate)
+		}
+		state.Updated = now.UnixNano()
+	}
+
+	decision := Decision{Limit: p.Limit}
+	if state.Tokens >= 1 {
+		state.Tokens--
+		decision.Allowed = true
+	} else {
+		decision.RetryAfter = seconds((1 - state.Tokens) / rate)
+	}
+	decision.Remaining = int(math.Floor(state.Tokens))
+	decision.Reset = seconds((limit - state.Tokens) / rate)
+
+	encoded, err := json.Marshal(state)
+	return decision, encoded, err
+}
+
+// slidingWindowState is the stored state of a sliding window counter
+type slidingWindowState struct {
+	Window   int64 `json:"window"` // Start of the current window, Unix nanoseconds
+	Current  int   `json:"current"`
+	Previous int   `json:"previous"`
+}
+
+// takeSlidingWindow counts the request in the current fixed window and
+// estimates the sliding window from the overlap with the previous one
+func takeSlidingWindow(raw []byte, p Policy, now time.Time) (Decision, []byte, error) {
+	start := now.Truncate(p.Period)
+
+	var state slidingWindowState
+	if raw != nil {
+		if err := json.Unmarshal(raw, &state); err != nil {
+			return Decision{}, nil, err
+		}
+	}
+	switch state.Window {
+	case start.UnixNano():
+	case start.Add(-p.Period).UnixNano():
+		state.Previous, state.Current = state.Current, 0
+	default:
+		state.Previous, state.Current = 0, 0
+	}
+	state.Window = start.UnixNano()
+
+	elapsed := now.Sub(start)
+	overlap := 1 - elapsed.Seconds()/p.Period.Seconds()
+	used := float64(state.Previous)*overlap + float64(state.Current)
+
+	decision := Decision{Limit: p.Limit, Reset: start.Add(p.Period).Sub(now)}
+	if used+1 <= float64(p.Limit) {
+		state.Current++
+		used++
+		decision.Allowed = true
+	} else {
+		decision.RetryAfter = slidingWindowRetryAfter(state, p, elapsed)
+	}
+	decision.Remaining = max(0, int(math.Floor(float64(p.Limit)-used)))
+
+	encoded, err := json.Marshal(state)
+	return decision, encoded, err
+}
+
+// slidingWindowRetryAfter returns how long until the weight of the
+// previous windows has decayed enough to allow one more request
+func slidingWindowRetryAfter(
+	state slidingWindowState,
+	p Policy,
+	elapsed time.Duration,
+) time.Duration {
+	limit := float64(p.Limit)
+	if state.Current+1 > p.Limit {
+		// The current window is full: wait for it to become the previous
+		// one and for its weight to decay
+		decay := 1 - (limit-1)/float64(state.Current)
+		return p.Period - elapsed + seconds(decay*p.Period.Seconds())
+	}
+	decay := 1 - (limit-float64(state.Current)-1)/float64(state.Previous)
+	return seconds(decay*p.Period.Seconds()) - elapsed
+}
+
+// seconds converts fractional seconds to a duration
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,101 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
+)
+
+// epoch is the time fake clocks start at, the start of a minute
+var epoch = time.Date(2030, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+// quota runs an algorithm over the successive requests of a client,
+// keeping its state
+type quota struct {
+	t      *testing.T
+	take   func(raw []byte, p Policy, now time.Time) (Decision, []byte, error)
+	policy Policy
+	clock  *clock.Fake
+	state  []byte
+}
+
+// request takes a request at the time of the clock and checks the decision
+func (q *quota) request(want Decision) {
+	q.t.Helper()
+	decision, state, err := q.take(q.state, q.policy, q.clock.Now())
+	if err != nil {
+		q.t.Fatalf("failed to take a request: %v", err)
+	}
+	q.state = state
+	want.Limit = q.policy.Limit
+	if decision != want {
+		q.t.Fatalf(
+			"at %s: expected %+v, got %+v",
+			q.clock.Now().Sub(epoch),
+			want,
+			decision,
+		)
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	clk := clock.NewFake(epoch)
+	q := &quota{
+		t:      t,
+		take:   takeTokenBucket,
+		policy: Policy{Limit: 2, Period: time.Minute},
+		clock:  clk,
+	}
+
+	// A burst empties the bucket, which refills a token every 30s
+	q.request(Decision{Allowed: true, Remaining: 1, Reset: 30 * time.Second})
+	q.request(Decision{Allowed: true, Remaining: 0, Reset: time.Minute})
+	q.request(Decision{Remaining: 0, Reset: time.Minute, RetryAfter: 30 * time.Second})
+
+	// A partial refill is not enough for a request
+	clk.Advance(15 * time.Second)
+	q.request(Decision{Remaining: 0, Reset: 45 * time.Second, RetryAfter: 15 * time.Second})
+
+	clk.Advance(15 * time.Second)
+	q.request(Decision{Allowed: true, Remaining: 0, Reset: time.Minute})
+
+	// The bucket holds at most Limit tokens however long it is left alone
+	clk.Advance(time.Hour)
+	q.request(Decision{Allowed: true, Remaining: 1, Reset: 30 * time.Second})
+	q.request(Decision{Allowed: true, Remaining: 0, Reset: time.Minute})
+	q.request(Decision{Remaining: 0, Reset: time.Minute, RetryAfter: 30 * time.Second})
+}
+
+func TestSlidingWindow(t *testing.T) {
+	clk := clock.NewFake(epoch)
+	q := &quota{
+		t:      t,
+		take:   takeSlidingWindow,
+		policy: Policy{Limit: 2, Period: time.Minute},
+		clock:  clk,
+	}
+
+	// Once the window is full, the next request waits for the window to
+	// end and for half of its weight to decay
+	q.request(Decision{Allowed: true, Remaining: 1, Reset: time.Minute})
+	q.request(Decision{Allowed: true, Remaining: 0, Reset: time.Minute})
+	clk.Advance(20 * time.Second)
+	q.request(Decision{Remaining: 0, Reset: 40 * time.Second, RetryAfter: 70 * time.Second})
+
+	// At the window boundary the previous window still weighs fully
+	clk.Advance(40 * time.Second)
+	q.request(Decision{Remaining: 0, Reset: time.Minute, RetryAfter: 30 * time.Second})
+
+	// Halfway through, it weighs half; the request taken then moves to the
+	// previous window at the next boundary, where it allows another
+	clk.Advance(30 * time.Second)
+	q.request(Decision{Allowed: true, Remaining: 0, Reset: 30 * time.Second})
+	q.request(Decision{Remaining: 0, Reset: 30 * time.Second, RetryAfter: 30 * time.Second})
+	clk.Advance(30 * time.Second)
+	q.request(Decision{Allowed: true, Remaining: 0, Reset: time.Minute})
+
+	// Windows older than the previous one are forgotten
+	clk.Advance(2 * time.Minute)
+	q.request(Decision{Allowed: true, Remaining: 1, Reset: time.Minute})
+}
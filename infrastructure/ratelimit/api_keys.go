@@ -0,0 +1,49 @@
+package ratelimit
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
+)
+
+// APIKeys authenticates the API keys of the clients that policies keyed
+// by api_key limit. Keys are only kept hashed, so that looking one up
+// does not compare secrets byte by byte.
+type APIKeys struct {
+	clients map[[sha256.Size]byte]string
+}
+
+// ParseAPIKeys reads "client:key" entries, as set in RATE_LIMIT_API_KEYS
+func ParseAPIKeys(entries []string) (*APIKeys, error) {
+	keys := &APIKeys{clients: make(map[[sha256.Size]byte]string, len(entries))}
+	for _, entry := range entries {
+		client, key, ok := strings.Cut(entry, ":")
+		if !ok || client == "" || key == "" {
+			return nil, fmt.Errorf("%w: API key entries must be \"client:key\"", utils.ErrInvalidInput)
+		}
+		hash := sha256.Sum256([]byte(key))
+		if other, ok := keys.clients[hash]; ok {
+			return nil, fmt.Errorf(
+				"%w: clients %s and %s have the same API key",
+				utils.ErrInvalidInput,
+				other,
+				client,
+			)
+		}
+		keys.clients[hash] = client
+	}
+	return keys, nil
+}
+
+// Len returns the number of keys
+func (k *APIKeys) Len() int {
+	return len(k.clients)
+}
+
+// ValidateAPIKey returns the client of a known key
+func (k *APIKeys) ValidateAPIKey(key string) (string, bool) {
+	client, ok := k.clients[sha256.Sum256([]byte(key))]
+	return client, ok
+}
@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseAPIKeys(t *testing.T) {
+	keys, err := ParseAPIKeys([]string{"partner:0123456789abcdef", "mobile:fedcba9876543210"})
+	if err != nil {
+		t.Fatalf("failed to parse the keys: %v", err)
+	}
+	if client, ok := keys.ValidateAPIKey("fedcba9876543210"); !ok || client != "mobile" {
+		t.Errorf("expected the key of mobile, got %q, %t", client, ok)
+	}
+	if _, ok := keys.ValidateAPIKey("made-up"); ok {
+		t.Error("expected an unknown key to be refused")
+	}
+
+	for _, entries := range [][]string{
+		{"no-separator"},
+		{":key-without-client"},
+		{"client-without-key:"},
+		{"a:same-key-twice", "b:same-key-twice"},
+	} {
+		if _, err := ParseAPIKeys(entries); err == nil {
+			t.Errorf("expected %q to be refused", entries)
+		}
+	}
+}
+
+func TestLoadPoliciesKeyedByAPIKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ratelimits.yaml")
+	content := `policies:
+  - name: partners
+    routes: ["GET /api/v1/users"]
+    algorithm: token_bucket
+    limit: 100
+    period: 1m
+    key: api_key
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without keys, every client would be limited by IP instead
+	_, err := LoadPolicies(path, false)
+	if err == nil || !strings.Contains(err.Error(), "RATE_LIMIT_API_KEYS") {
+		t.Errorf("expected the policy to require API keys, got %v", err)
+	}
+	if policies, err := LoadPolicies(path, true); err != nil || len(policies) != 1 {
+		t.Errorf("expected the policy to load with API keys, got %+v (%v)", policies, err)
+	}
+}
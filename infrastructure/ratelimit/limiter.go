@@ -0,0 +1,50 @@
+package ratelimit
+
+import (
+	"context"
+
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
+	"github.com/EngenMe/go-clean-architecture/interfaces/repositories"
+)
+
+// Limiter applies policies with their counters kept in a RateLimitStore
+type Limiter struct {
+	store repositories.RateLimitStore
+	clock clock.Clock
+}
+
+// NewLimiter creates a new limiter whose windows and buckets follow clk
+func NewLimiter(store repositories.RateLimitStore, clk clock.Clock) *Limiter {
+	return &Limiter{store: store, clock: clk}
+}
+
+// Allow counts a request of the client identified by key against policy
+func (l *Limiter) Allow(
+	ctx context.Context,
+	policy Policy,
+	key string,
+) (Decision, error) {
+	take, ttl := takeTokenBucket, policy.Period
+	if policy.Algorithm == SlidingWindow {
+		// The previous window still weighs on the current one
+		take, ttl = takeSlidingWindow, 2*policy.Period
+	}
+
+	now := l.clock.Now()
+	var decision Decision
+	err := l.store.Update(
+		ctx,
+		policy.Name+":"+key,
+		now,
+		ttl,
+		func(state []byte) ([]byte, error) {
+			var (
+				next []byte
+				err  error
+			)
+			decision, next, err = take(state, policy, now)
+			return next, err
+		},
+	)
+	return decision, err
+}
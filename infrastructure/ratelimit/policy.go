@@ -0,0 +1,178 @@
+// Package ratelimit implements the rate limit policies of the API and the
+// token bucket and sliding window algorithms that enforce them.
+package ratelimit
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/infrastructure/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// Algorithm names a rate limit algorithm
+type Algorithm string
+
+// Supported algorithms
+const (
+	// TokenBucket allows bursts of up to Limit requests and refills at
+	// Limit requests per Period
+	TokenBucket Algorithm = "token_bucket"
+	// SlidingWindow allows Limit requests in any window of Period,
+	// weighting the previous fixed window by its overlap
+	SlidingWindow Algorithm = "sliding_window"
+)
+
+// KeySource names what identifies a client
+type KeySource string
+
+// Supported key sources; requests without a valid token or API key fall
+// back to the client IP
+const (
+	KeyByIP     KeySource = "ip"
+	KeyByUser   KeySource = "user"
+	KeyByAPIKey KeySource = "api_key"
+)
+
+// Policy limits the requests of each client to a set of routes
+type Policy struct {
+	Name      string        `yaml:"name"`
+	Routes    []string      `yaml:"routes"` // "METHOD /path" as registered, e.g. "GET /api/v1/users/email/:email"
+	Algorithm Algorithm     `yaml:"algorithm"`
+	Limit     int           `yaml:"limit"`
+	Period    time.Duration `yaml:"period"`
+	Key       KeySource     `yaml:"key"`
+}
+
+// Validate checks that the policy is usable
+func (p Policy) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("%w: rate limit policy name is required", utils.ErrInvalidInput)
+	}
+	if len(p.Routes) == 0 {
+		return fmt.Errorf("%w: rate limit policy %s has no routes", utils.ErrInvalidInput, p.Name)
+	}
+	for _, route := range p.Routes {
+		if _, _, ok := strings.Cut(route, " "); !ok {
+			return fmt.Errorf(
+				"%w: rate limit policy %s: route %q must be \"METHOD /path\"",
+				utils.ErrInvalidInput,
+				p.Name,
+				route,
+			)
+		}
+	}
+	switch p.Algorithm {
+	case TokenBucket, SlidingWindow:
+	default:
+		return fmt.Errorf(
+			"%w: rate limit policy %s: unknown algorithm %q",
+			utils.ErrInvalidInput,
+			p.Name,
+			p.Algorithm,
+		)
+	}
+	if p.Limit <= 0 || p.Period <= 0 {
+		return fmt.Errorf(
+			"%w: rate limit policy %s: limit and period must be positive",
+			utils.ErrInvalidInput,
+			p.Name,
+		)
+	}
+	switch p.Key {
+	case KeyByIP, KeyByUser, KeyByAPIKey:
+	default:
+		return fmt.Errorf(
+			"%w: rate limit policy %s: unknown key %q",
+			utils.ErrInvalidInput,
+			p.Name,
+			p.Key,
+		)
+	}
+	return nil
+}
+
+// DefaultPolicies protects the public endpoints that are open to brute
+// forcing and enumeration
+func DefaultPolicies() []Policy {
+	return []Policy{
+		{
+			Name:      "login",
+			Routes:    []string{"POST /api/v1/auth/login"},
+			Algorithm: TokenBucket,
+			Limit:     5,
+			Period:    time.Minute,
+			Key:       KeyByIP,
+		},
+		{
+			Name:      "signup",
+			Routes:    []string{"POST /api/v1/auth/signup", "POST /api/v1/signup"},
+			Algorithm: SlidingWindow,
+			Limit:     10,
+			Period:    time.Hour,
+			Key:       KeyByIP,
+		},
+		{
+			Name:      "user-lookup",
+			Routes:    []string{"GET /api/v1/users/email/:email"},
+			Algorithm: SlidingWindow,
+			Limit:     30,
+			Period:    time.Minute,
+			Key:       KeyByIP,
+		},
+	}
+}
+
+// policyFile is the layout of a rate limit configuration file
+type policyFile struct {
+	Policies []Policy `yaml:"policies"`
+}
+
+// LoadPolicies reads the policies from a YAML or JSON file, or returns the
+// default policies when path is empty. Policies keyed by api_key are
+// refused unless API keys are configured, as they would otherwise limit
+// every client by IP.
+func LoadPolicies(path string, apiKeys bool) ([]Policy, error) {
+	if path == "" {
+		return DefaultPolicies(), nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rate limit policies: %w", err)
+	}
+
+	var file policyFile
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse rate limit policies %s: %w", path, err)
+	}
+
+	routes := make(map[string]string)
+	for _, policy := range file.Policies {
+		if err := policy.Validate(); err != nil {
+			return nil, err
+		}
+		if policy.Key == KeyByAPIKey && !apiKeys {
+			return nil, fmt.Errorf(
+				"%w: rate limit policy %s is keyed by api_key, but RATE_LIMIT_API_KEYS is empty",
+				utils.ErrInvalidInput,
+				policy.Name,
+			)
+		}
+		for _, route := range policy.Routes {
+			if other, ok := routes[route]; ok {
+				return nil, fmt.Errorf(
+					"%w: route %q is limited by both %s and %s",
+					utils.ErrInvalidInput,
+					route,
+					other,
+					policy.Name,
+				)
+			}
+			routes[route] = policy.Name
+		}
+	}
+	return file.Policies, nil
+}
@@ -0,0 +1,35 @@
+package ratelimit
+
+import (
+	"sync/atomic"
+)
+
+// PolicySet holds the policies by route. The policies can be replaced
+// while requests are served, e.g. when the configuration is reloaded.
+type PolicySet struct {
+	byRoute atomic.Pointer[map[string]Policy]
+}
+
+// NewPolicySet creates a policy set holding policies
+func NewPolicySet(policies []Policy) *PolicySet {
+	set := &PolicySet{}
+	set.Store(policies)
+	return set
+}
+
+// Store replaces the policies atomically
+func (s *PolicySet) Store(policies []Policy) {
+	byRoute := make(map[string]Policy)
+	for _, policy := range policies {
+		for _, route := range policy.Routes {
+			byRoute[route] = policy
+		}
+	}
+	s.byRoute.Store(&byRoute)
+}
+
+// Lookup returns the policy limiting a route, as "METHOD /path"
+func (s *PolicySet) Lookup(route string) (Policy, bool) {
+	policy, ok := (*s.byRoute.Load())[route]
+	return policy, ok
+}
@@ -0,0 +1,99 @@
+package tracing
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// spanKey is the statement setting holding the span of a query
+const spanKey = "tracing:span"
+
+// GormPlugin implements GORM's Plugin interface by recording each
+// statement as a span, child of the span of the statement's context. The
+// SQL is recorded with its placeholders, without the parameters.
+type GormPlugin struct{}
+
+// NewGormPlugin creates a new GORM tracing plugin
+func NewGormPlugin() gorm.Plugin {
+	return &GormPlugin{}
+}
+
+// Name returns the name of the plugin
+func (p *GormPlugin) Name() string {
+	return "tracing"
+}
+
+// Initialize registers the span callbacks first and last around each kind
+// of operation
+func (p *GormPlugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().Before("*").Register("tracing:before_create", startSpan("create")),
+		callbacks.Create().After("*").Register("tracing:after_create", endSpan),
+		callbacks.Query().Before("*").Register("tracing:before_query", startSpan("query")),
+		callbacks.Query().After("*").Register("tracing:after_query", endSpan),
+		callbacks.Update().Before("*").Register("tracing:before_update", startSpan("update")),
+		callbacks.Update().After("*").Register("tracing:after_update", endSpan),
+		callbacks.Delete().Before("*").Register("tracing:before_delete", startSpan("delete")),
+		callbacks.Delete().After("*").Register("tracing:after_delete", endSpan),
+		callbacks.Row().Before("*").Register("tracing:before_row", startSpan("row")),
+		callbacks.Row().After("*").Register("tracing:after_row", endSpan),
+		callbacks.Raw().Before("*").Register("tracing:before_raw", startSpan("raw")),
+		callbacks.Raw().After("*").Register("tracing:after_raw", endSpan),
+	)
+}
+
+// startSpan returns a callback starting the span of an operation
+func startSpan(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx := db.Statement.Context
+		if ctx == nil || !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+			// Queries outside of a traced request, such as the outbox
+			// polling, would each start a new trace
+			return
+		}
+
+		name := "gorm." + operation
+		if db.Statement.Table != "" {
+			name += " " + db.Statement.Table
+		}
+		_, span := Tracer().Start(
+			ctx,
+			name,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.DBSystemKey.String(db.Dialector.Name()),
+				semconv.DBCollectionName(db.Statement.Table),
+				semconv.DBOperationName(operation),
+			),
+		)
+		db.InstanceSet(spanKey, span)
+	}
+}
+
+// endSpan ends the span of an operation with its SQL and outcome
+func endSpan(db *gorm.DB) {
+	value, ok := db.InstanceGet(spanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	span.SetAttributes(
+		semconv.DBQueryText(db.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	)
+	if err := db.Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
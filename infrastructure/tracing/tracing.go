@@ -0,0 +1,104 @@
+// Package tracing configures OpenTelemetry tracing: the W3C trace context
+// propagation of incoming requests and the exporter spans are sent to.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName names the tracer of the application spans
+const InstrumentationName = "github.com/EngenMe/go-clean-architecture"
+
+// Supported span exporters
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// Options configures tracing
+type Options struct {
+	// Exporter is none, stdout or otlp. OTLP exports over HTTP.
+	Exporter string
+	// Endpoint is the base URL of the OTLP collector; the standard
+	// OTEL_EXPORTER_OTLP_* variables apply when empty
+	Endpoint string
+	// ServiceName identifies the application in the traces
+	ServiceName string
+	// SampleRatio is the fraction of new traces that are recorded; the
+	// decision of an incoming traceparent is followed
+	SampleRatio float64
+	// Writer receives the spans of the stdout exporter
+	Writer io.Writer
+}
+
+// Tracer returns the tracer of the application spans
+func Tracer() trace.Tracer {
+	return otel.Tracer(InstrumentationName)
+}
+
+// Setup installs the global propagator and tracer provider. The returned
+// function flushes the pending spans and must be called on shutdown. With
+// the none exporter, trace contexts are still propagated, so that problem
+// responses and logs report the trace ID of the caller.
+func Setup(ctx context.Context, options Options) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(
+		propagation.NewCompositeTextMapPropagator(
+			propagation.TraceContext{},
+			propagation.Baggage{},
+		),
+	)
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch options.Exporter {
+	case ExporterNone, "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(options.Writer))
+	case ExporterOTLP:
+		var otlpOptions []otlptracehttp.Option
+		if options.Endpoint != "" {
+			otlpOptions = append(otlpOptions, otlptracehttp.WithEndpointURL(options.Endpoint))
+		}
+		exporter, err = otlptracehttp.New(ctx, otlpOptions...)
+	default:
+		return nil, fmt.Errorf(
+			"unsupported tracing exporter %q (expected %s, %s or %s)",
+			options.Exporter,
+			ExporterNone,
+			ExporterStdout,
+			ExporterOTLP,
+		)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s span exporter: %w", options.Exporter, err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(
+			sdktrace.ParentBased(sdktrace.TraceIDRatioBased(options.SampleRatio)),
+		),
+		sdktrace.WithResource(
+			resource.NewWithAttributes(
+				semconv.SchemaURL,
+				semconv.ServiceName(options.ServiceName),
+			),
+		),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
@@ -1,46 +0,0 @@
-package utils
-
-import (
-	"log"
-	"os"
-	"strconv"
-
-	"github.com/joho/godotenv"
-)
-
-// LoadConfig loads environment variables from .env file
-func LoadConfig() {
-	err := godotenv.Load()
-	if err != nil {
-		log.Println("Warning: .env file not found, using environment variables")
-	}
-}
-
-// GetEnv gets an environment variable or returns a default value
-func GetEnv(key, defaultValue string) string {
-	value := os.Getenv(key)
-	if value == "" {
-		return defaultValue
-	}
-	return value
-}
-
-// GetEnvAsInt gets an environment variable as an integer or returns a default value
-func GetEnvAsInt(key string, defaultValue int) int {
-	valueStr := GetEnv(key, "")
-	if valueStr == "" {
-		return defaultValue
-	}
-
-	value, err := strconv.Atoi(valueStr)
-	if err != nil {
-		log.Printf(
-			"Warning: Unable to parse %s as int, using default value %d\n",
-			key,
-			defaultValue,
-		)
-		return defaultValue
-	}
-
-	return value
-}
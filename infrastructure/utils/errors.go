@@ -3,48 +3,103 @@ package utils
 import (
 	"errors"
 	"net/http"
+
+	"github.com/EngenMe/go-clean-architecture/infrastructure/i18n"
 )
 
 // Common error types
 var (
-	ErrNotFound           = errors.New("resource not found")
-	ErrUnauthorized       = errors.New("unauthorized")
-	ErrBadRequest         = errors.New("bad request")
-	ErrConflict           = errors.New("resource already exists")
-	ErrEmailAlreadyExists = errors.New("email already exists")
-	ErrInvalidInput       = errors.New("invalid input data")
-	ErrWeakPassword       = errors.New("password does not meet security requirements")
+	ErrNotFound             = errors.New("resource not found")
+	ErrUnauthorized         = errors.New("unauthorized")
+	ErrForbidden            = errors.New("forbidden")
+	ErrBadRequest           = errors.New("bad request")
+	ErrConflict             = errors.New("resource already exists")
+	ErrEmailAlreadyExists   = errors.New("email already exists")
+	ErrInvalidInput         = errors.New("invalid input data")
+	ErrWeakPassword         = errors.New("password does not meet security requirements")
+	ErrPatchTestFailed      = errors.New("patch test operation failed")
+	ErrUnsupportedMedia     = errors.New("unsupported media type")
+	ErrReferenceViolation   = errors.New("referenced resource does not exist or is still in use")
+	ErrConcurrentUpdate     = errors.New("concurrent update detected, please retry")
+	ErrTooManyRequests      = errors.New("too many requests, please retry later")
+	ErrPayloadTooLarge      = errors.New("request body is too large")
+	ErrIdempotencyKeyReused = errors.New("idempotency key was already used with a different payload")
+	ErrRequestInProgress    = errors.New("a request with this idempotency key is still in progress")
 )
 
-// APIError represents an API error response
-type APIError struct {
-	Status  int    `json:"status" example:"400"`
-	Message string `json:"message" example:"An error occurred"`
+// LocalizedError is an error whose detail is translated for API clients
+type LocalizedError struct {
+	Err  error    // Sentinel error giving the kind of the error
+	Key  string   // Message catalog key of the detail
+	Args []string // Name/value pairs of the message placeholders
 }
 
-// ErrorToStatusCode maps error types to HTTP status codes
-func ErrorToStatusCode(err error) int {
-	switch {
-	case errors.Is(err, ErrNotFound):
-		return http.StatusNotFound
-	case errors.Is(err, ErrUnauthorized):
-		return http.StatusUnauthorized
-	case errors.Is(err, ErrBadRequest), errors.Is(
-		err,
-		ErrInvalidInput,
-	), errors.Is(err, ErrWeakPassword):
-		return http.StatusBadRequest
-	case errors.Is(err, ErrConflict), errors.Is(err, ErrEmailAlreadyExists):
-		return http.StatusConflict
-	default:
-		return http.StatusInternalServerError
-	}
+// NewLocalizedError creates an error of the kind of err whose detail is
+// the message key, see i18n.Translate
+func NewLocalizedError(err error, key string, args ...string) error {
+	return &LocalizedError{Err: err, Key: key, Args: args}
+}
+
+// Error returns the English message
+func (e *LocalizedError) Error() string {
+	return e.Err.Error() + ": " + e.Detail(i18n.DefaultLocale)
+}
+
+// Unwrap returns the sentinel error
+func (e *LocalizedError) Unwrap() error {
+	return e.Err
+}
+
+// Detail returns the detail in locale
+func (e *LocalizedError) Detail(locale string) string {
+	return i18n.Translate(locale, e.Key, e.Args...)
 }
 
-// NewAPIError creates a new API error
-func NewAPIError(status int, message string) APIError {
-	return APIError{
-		Status:  status,
-		Message: message,
+// errorKind describes how an error is reported to API clients
+type errorKind struct {
+	err    error
+	status int
+	code   string // Stable, machine-readable problem code, titled by the "problem.<code>" message
+}
+
+// errorKinds lists the reported errors, the most specific first. Errors
+// matching none of them are internal errors.
+var errorKinds = []errorKind{
+	{ErrNotFound, http.StatusNotFound, "not_found"},
+	{ErrUnauthorized, http.StatusUnauthorized, "unauthorized"},
+	{ErrForbidden, http.StatusForbidden, "forbidden"},
+	{ErrWeakPassword, http.StatusBadRequest, "weak_password"},
+	{ErrInvalidInput, http.StatusBadRequest, "validation_failed"},
+	{ErrBadRequest, http.StatusBadRequest, "bad_request"},
+	{ErrEmailAlreadyExists, http.StatusConflict, "email_already_exists"},
+	{ErrPatchTestFailed, http.StatusConflict, "patch_test_failed"},
+	{ErrReferenceViolation, http.StatusConflict, "reference_violation"},
+	{ErrConcurrentUpdate, http.StatusConflict, "concurrent_update"},
+	{ErrRequestInProgress, http.StatusConflict, "request_in_progress"},
+	{ErrConflict, http.StatusConflict, "conflict"},
+	{ErrPayloadTooLarge, http.StatusRequestEntityTooLarge, "payload_too_large"},
+	{ErrUnsupportedMedia, http.StatusUnsupportedMediaType, "unsupported_media_type"},
+	{ErrIdempotencyKeyReused, http.StatusUnprocessableEntity, "idempotency_key_reused"},
+	{ErrTooManyRequests, http.StatusTooManyRequests, "too_many_requests"},
+}
+
+// internalErrorKind reports unexpected errors
+var internalErrorKind = errorKind{
+	status: http.StatusInternalServerError,
+	code:   "internal_error",
+}
+
+// kindOf returns how err is reported
+func kindOf(err error) errorKind {
+	for _, kind := range errorKinds {
+		if errors.Is(err, kind.err) {
+			return kind
+		}
 	}
+	return internalErrorKind
+}
+
+// ErrorToStatusCode maps error types to HTTP status codes
+func ErrorToStatusCode(err error) int {
+	return kindOf(err).status
 }
@@ -2,40 +2,85 @@ package utils
 
 import (
 	"fmt"
-	"os"
 	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/clock"
 	"github.com/golang-jwt/jwt/v5"
 )
 
 // JWTClaims represents the claims in the JWT token
 type JWTClaims struct {
-	UserID uint   `json:"user_id"`
-	Email  string `json:"email"`
+	UserID uint          `json:"user_id"`
+	Email  string        `json:"email"`
+	Role   entities.Role `json:"role"`
 	jwt.RegisteredClaims
 }
 
+// JWT issues access tokens signed with its current secret and accepts
+// the tokens signed with any secret of its keyring, so that the secret
+// can be rotated without invalidating the tokens already issued. The keys
+// can be replaced while requests are served.
+type JWT struct {
+	keys  atomic.Pointer[jwtKeys]
+	clock clock.Clock // Issues and expires the tokens
+}
+
+// jwtKeys is the keyring of a JWT
+type jwtKeys struct {
+	signing      []byte
+	verification []jwt.VerificationKey
+	expiration   time.Duration
+}
+
+// NewJWT creates a JWT signing tokens with secret that expire after
+// expiration as told by clk, and accepting the tokens signed with
+// previous secrets
+func NewJWT(
+	secret string,
+	previous []string,
+	expiration time.Duration,
+	clk clock.Clock,
+) *JWT {
+	j := &JWT{clock: clk}
+	j.SetKeys(secret, previous, expiration)
+	return j
+}
+
+// SetKeys replaces the keyring atomically
+func (j *JWT) SetKeys(secret string, previous []string, expiration time.Duration) {
+	keys := &jwtKeys{
+		signing:      []byte(secret),
+		verification: []jwt.VerificationKey{[]byte(secret)},
+		expiration:   expiration,
+	}
+	for _, key := range previous {
+		keys.verification = append(keys.verification, []byte(key))
+	}
+	j.keys.Store(keys)
+}
+
 // GenerateToken generates a new JWT token for a user
-func GenerateToken(user *entities.User) (string, error) {
-	expirationHours := GetEnvAsInt("JWT_EXPIRATION_HOURS", 24)
-	expirationTime := time.Now().Add(time.Duration(expirationHours) * time.Hour)
+func (j *JWT) GenerateToken(user *entities.User) (string, error) {
+	keys := j.keys.Load()
+	now := j.clock.Now()
 
 	claims := &JWTClaims{
 		UserID: user.ID,
-		Email:  user.Email,
+		Email:  user.Email.String(),
+		Role:   user.Role,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(now.Add(keys.expiration)),
+			IssuedAt:  jwt.NewNumericDate(now),
 			Subject:   strconv.FormatUint(uint64(user.ID), 10),
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
-	jwtSecret := []byte(os.Getenv("JWT_SECRET"))
-	tokenString, err := token.SignedString(jwtSecret)
+	tokenString, err := token.SignedString(keys.signing)
 	if err != nil {
 		return "", err
 	}
@@ -44,8 +89,8 @@ func GenerateToken(user *entities.User) (string, error) {
 }
 
 // ValidateToken validates a JWT token and returns the claims
-func ValidateToken(tokenString string) (*JWTClaims, error) {
-	jwtSecret := []byte(os.Getenv("JWT_SECRET"))
+func (j *JWT) ValidateToken(tokenString string) (*JWTClaims, error) {
+	keys := j.keys.Load()
 
 	token, err := jwt.ParseWithClaims(
 		tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
@@ -56,8 +101,9 @@ func ValidateToken(tokenString string) (*JWTClaims, error) {
 					token.Header["alg"],
 				)
 			}
-			return jwtSecret, nil
+			return jwt.VerificationKeySet{Keys: keys.verification}, nil
 		},
+		jwt.WithTimeFunc(j.clock.Now),
 	)
 
 	if err != nil {
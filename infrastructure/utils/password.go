@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"context"
+
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/tracing"
+	"go.opentelemetry.io/otel/codes"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// HashPassword hashes a password with bcrypt, once it passed
+// entities.ValidatePassword. Hashing is deliberately slow, so it is
+// recorded as a span of the trace of ctx.
+func HashPassword(ctx context.Context, password string) (entities.HashedPassword, error) {
+	if err := entities.ValidatePassword(password); err != nil {
+		return entities.HashedPassword{}, NewRuleFieldError("password", err)
+	}
+
+	_, span := tracing.Tracer().Start(ctx, "bcrypt.hash")
+	defer span.End()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return entities.HashedPassword{}, err
+	}
+	return entities.NewHashedPassword(string(hash))
+}
+
+// ComparePassword reports whether password matches a bcrypt hash, with a
+// nil error, recording the comparison as a span of the trace of ctx
+func ComparePassword(ctx context.Context, hash entities.HashedPassword, password string) error {
+	_, span := tracing.Tracer().Start(ctx, "bcrypt.compare")
+	defer span.End()
+
+	return bcrypt.CompareHashAndPassword([]byte(hash.Hash()), []byte(password))
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/EngenMe/go-clean-architecture/infrastructure/i18n"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem details
+const ProblemContentType = "application/problem+json"
+
+// problemTypePrefix is prefixed to problem codes to form their type URI
+const problemTypePrefix = "/problems/"
+
+// Problem is an RFC 7807 problem details error response
+type Problem struct {
+	Type     string       `json:"type" example:"/problems/validation_failed"`
+	Title    string       `json:"title" example:"Invalid input"`
+	Status   int          `json:"status" example:"400"`
+	Detail   string       `json:"detail,omitempty" example:"email must be a valid email address"`
+	Instance string       `json:"instance,omitempty" example:"/api/v1/users"`
+	Code     string       `json:"code" example:"validation_failed"`
+	TraceID  string       `json:"traceId,omitempty" example:"4bf92f3577b34da6a3ce929d0e0e4736"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// NewProblem describes err for API clients in locale. Internal errors
+// get no detail, so that they cannot leak implementation details.
+func NewProblem(err error, locale string) Problem {
+	kind := kindOf(err)
+	problem := Problem{
+		Type:   problemTypePrefix + kind.code,
+		Title:  i18n.Translate(locale, "problem."+kind.code),
+		Status: kind.status,
+		Code:   kind.code,
+	}
+	if kind.err == nil {
+		return problem
+	}
+
+	var validationError *ValidationError
+	var localizedError *LocalizedError
+	switch {
+	case errors.As(err, &validationError):
+		problem.Errors = validationError.Localize(locale)
+		problem.Detail = describeFields(problem.Errors)
+	case errors.As(err, &localizedError):
+		problem.Detail = localizedError.Detail(locale)
+	default:
+		problem.Detail = problemDetail(err, kind.err)
+	}
+	return problem
+}
+
+// problemDetail returns the message of the innermost error wrapping the
+// sentinel error, without the message of the sentinel, which the title
+// already gives. Outer wrappers only add context meant for logs.
+func problemDetail(err error, sentinel error) string {
+	detail := err.Error()
+	for e := err; e != nil && e != sentinel; e = errors.Unwrap(e) {
+		detail = e.Error()
+	}
+	detail = strings.TrimPrefix(detail, sentinel.Error()+": ")
+	if detail == sentinel.Error() {
+		return ""
+	}
+	return detail
+}
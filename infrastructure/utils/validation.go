@@ -0,0 +1,190 @@
+package utils
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/infrastructure/i18n"
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError describes why one field of a request is invalid
+type FieldError struct {
+	Field   string   `json:"field" example:"email"`
+	Rule    string   `json:"rule" example:"email"`
+	Message string   `json:"message" example:"must be a valid email address"`
+	key     string   // Message catalog key of Message
+	args    []string // Name/value pairs of the message placeholders
+}
+
+// NewFieldError reports a single invalid field, described by a message
+// key and its name/value pairs, see i18n.Translate
+func NewFieldError(field, rule, key string, args ...string) error {
+	return &ValidationError{Fields: []FieldError{newFieldError(field, rule, key, args...)}}
+}
+
+// newFieldError creates a field error with its English message
+func newFieldError(field, rule, key string, args ...string) FieldError {
+	return FieldError{
+		Field:   field,
+		Rule:    rule,
+		Message: i18n.Translate(i18n.DefaultLocale, key, args...),
+		key:     key,
+		args:    args,
+	}
+}
+
+// NewRuleFieldError reports field as invalid when err is a rule of the
+// domain it breaks, see entities.RuleError; other errors are returned as is
+func NewRuleFieldError(field string, err error) error {
+	var fields FieldErrors
+	fields.Check(field, err)
+	return fields.Err()
+}
+
+// FieldErrors collects the invalid fields of a request checked against the
+// rules of the domain. The zero value is ready to use.
+type FieldErrors struct {
+	fields []FieldError
+	err    error // First error that is not a rule of the domain
+}
+
+// Check records field as invalid when err is a rule of the domain it
+// breaks, see entities.RuleError; other errors are kept for Err
+func (e *FieldErrors) Check(field string, err error) {
+	var ruleError *entities.RuleError
+	switch {
+	case err == nil:
+	case errors.As(err, &ruleError):
+		key, args := ruleMessage(ruleError)
+		e.fields = append(e.fields, newFieldError(field, ruleError.Rule, key, args...))
+	case e.err == nil:
+		e.err = err
+	}
+}
+
+// Err returns the first error that is not a rule of the domain, then a
+// *ValidationError listing the invalid fields, or nil if there is none
+func (e *FieldErrors) Err() error {
+	if e.err != nil {
+		return e.err
+	}
+	if len(e.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: e.fields}
+}
+
+// ValidationError reports the invalid fields of a request. It matches
+// ErrInvalidInput.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// Error lists the invalid fields
+func (e *ValidationError) Error() string {
+	return ErrInvalidInput.Error() + ": " + describeFields(e.Fields)
+}
+
+// Unwrap makes the error match ErrInvalidInput
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidInput
+}
+
+// Localize returns the field errors with their messages in locale
+func (e *ValidationError) Localize(locale string) []FieldError {
+	fields := make([]FieldError, len(e.Fields))
+	for i, field := range e.Fields {
+		fields[i] = field
+		if field.key != "" {
+			fields[i].Message = i18n.Translate(locale, field.key, field.args...)
+		}
+	}
+	return fields
+}
+
+// describeFields joins the field errors as "email must be ...; password ..."
+func describeFields(fields []FieldError) string {
+	messages := make([]string, len(fields))
+	for i, field := range fields {
+		messages[i] = field.Field + " " + field.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// NewValidationError converts the errors of a validator whose field names
+// are JSON names, see JSONFieldName
+func NewValidationError(validationErrors validator.ValidationErrors) *ValidationError {
+	fields := make([]FieldError, len(validationErrors))
+	for i, fieldError := range validationErrors {
+		// Drop the struct name from the namespace, keeping nested paths
+		// such as "address.city"
+		_, field, found := strings.Cut(fieldError.Namespace(), ".")
+		if !found {
+			field = fieldError.Field()
+		}
+		key, args := validationMessage(fieldError)
+		fields[i] = newFieldError(field, fieldError.Tag(), key, args...)
+	}
+	return &ValidationError{Fields: fields}
+}
+
+// JSONFieldName names struct fields by their JSON name, as clients know
+// them. It is meant for validator.RegisterTagNameFunc.
+func JSONFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+// validationMessage returns the message key and arguments describing a
+// failed validation rule
+func validationMessage(fieldError validator.FieldError) (string, []string) {
+	param := fieldError.Param()
+	switch tag := fieldError.Tag(); tag {
+	case "required", "email", "url", "http_url", "dive":
+		return "validation." + tag, nil
+	case "oneof":
+		return "validation.oneof", []string{"values", strings.Join(strings.Fields(param), ", ")}
+	case "min", "max", "len":
+		return "validation." + tag + "." + sizeKind(fieldError.Kind()), []string{"param", param}
+	case "gt", "gte", "lt", "lte":
+		return "validation." + tag, []string{"param", param}
+	default:
+		if param != "" {
+			tag += "=" + param
+		}
+		return "validation.default", []string{"rule", tag}
+	}
+}
+
+// ruleMessage returns the message key and arguments describing a rule of
+// the domain, which only applies to strings
+func ruleMessage(ruleError *entities.RuleError) (string, []string) {
+	switch rule := ruleError.Rule; rule {
+	case "required", "email", "printable":
+		return "validation." + rule, nil
+	case "min", "max":
+		return "validation." + rule + ".string", []string{"param", strconv.Itoa(ruleError.Param)}
+	default:
+		return "validation.default", []string{"rule", rule}
+	}
+}
+
+// sizeKind tells how a size rule applies to a kind of field: to the length
+// of strings, the items of collections or the value of numbers
+func sizeKind(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "items"
+	default:
+		return "number"
+	}
+}
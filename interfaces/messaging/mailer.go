@@ -0,0 +1,17 @@
+package messaging
+
+import (
+	"context"
+)
+
+// Email is a plain text message to a single recipient
+type Email struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends emails
+type Mailer interface {
+	Send(ctx context.Context, email Email) error
+}
@@ -0,0 +1,14 @@
+package messaging
+
+import (
+	"context"
+
+	"github.com/EngenMe/go-clean-architecture/domain/events"
+)
+
+// Publisher delivers domain events to a sink. Delivery is at-least-once,
+// so implementations and their consumers must tolerate duplicates, which
+// share the same envelope ID.
+type Publisher interface {
+	Publish(ctx context.Context, envelope events.Envelope) error
+}
@@ -9,6 +9,11 @@ import (
 // Entity defines common operations for entities
 type Entity interface {
 	GetID() uint
+}
+
+// Identifiable is implemented by pointers to entities, so that stores can
+// assign the ID of the entities they create
+type Identifiable interface {
 	SetID(id uint)
 }
 
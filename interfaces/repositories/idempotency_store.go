@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+)
+
+// IdempotencyStore defines the storage of Idempotency-Key responses
+type IdempotencyStore interface {
+	// Reserve atomically claims key at now for a new request, until
+	// now+ttl. If the key is already claimed and not expired at now, it
+	// returns the existing record and false instead.
+	Reserve(
+		ctx context.Context,
+		key string,
+		requestHash string,
+		now time.Time,
+		ttl time.Duration,
+	) (*entities.IdempotencyRecord, bool, error)
+	// Complete stores the response of the request that reserved key
+	Complete(
+		ctx context.Context,
+		key string,
+		statusCode int,
+		contentType string,
+		body []byte,
+	) error
+	// Release removes a reservation so that the request can be retried
+	Release(ctx context.Context, key string) error
+	// DeleteExpired removes the records expired at now
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+	"github.com/EngenMe/go-clean-architecture/domain/events"
+)
+
+// OutboxRepository defines operations for the transactional outbox
+type OutboxRepository interface {
+	// Add stores events in the outbox, inside the caller's transaction if
+	// any, due from when they occurred
+	Add(ctx context.Context, envelopes ...events.Envelope) error
+	// ClaimDue returns up to limit undelivered messages due at now and
+	// hides them from other relays until now+lease
+	ClaimDue(
+		ctx context.Context,
+		now time.Time,
+		lease time.Duration,
+		limit int,
+	) ([]entities.OutboxMessage, error)
+	// MarkDelivered records a successful delivery
+	MarkDelivered(ctx context.Context, id uint, deliveredAt time.Time) error
+	// MarkFailed records a failed delivery and schedules the next attempt
+	MarkFailed(
+		ctx context.Context,
+		id uint,
+		lastError string,
+		nextAttemptAt time.Time,
+	) error
+	// OldestPending returns when the oldest undelivered message occurred,
+	// or nil if there is none
+	OldestPending(ctx context.Context) (*time.Time, error)
+}
@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimitStore defines the storage of rate limit counters
+type RateLimitStore interface {
+	// Update atomically replaces the state stored under key with the
+	// result of fn, which receives nil when there is no state unexpired at
+	// now. The new state expires at now+ttl.
+	Update(
+		ctx context.Context,
+		key string,
+		now time.Time,
+		ttl time.Duration,
+		fn func(state []byte) ([]byte, error),
+	) error
+	// DeleteExpired removes the states expired at now
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
@@ -0,0 +1,11 @@
+package repositories
+
+import (
+	"context"
+)
+
+// UnitOfWork runs a function atomically. Repositories called with the
+// context passed to fn take part in the same transaction.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
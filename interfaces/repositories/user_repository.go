@@ -10,7 +10,8 @@ import (
 type UserRepository interface {
 	Create(ctx context.Context, user *entities.User) error
 	GetByID(ctx context.Context, id uint) (*entities.User, error)
-	GetByEmail(ctx context.Context, email string) (*entities.User, error)
+	// GetByEmail finds the user with an email regardless of its case
+	GetByEmail(ctx context.Context, email entities.Email) (*entities.User, error)
 	GetAll(ctx context.Context) ([]entities.User, error)
 	Update(ctx context.Context, user *entities.User) error
 	Delete(ctx context.Context, id uint) error
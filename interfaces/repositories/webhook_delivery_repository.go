@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/EngenMe/go-clean-architecture/domain/entities"
+)
+
+// WebhookDeliveryRepository defines operations for the webhook delivery log
+type WebhookDeliveryRepository interface {
+	// Enqueue stores pending deliveries, skipping the ones already recorded
+	// for the same subscription and event
+	Enqueue(ctx context.Context, deliveries ...entities.WebhookDelivery) error
+	// ClaimDue returns up to limit pending deliveries due at now and hides
+	// them from other dispatchers until now+lease
+	ClaimDue(
+		ctx context.Context,
+		now time.Time,
+		lease time.Duration,
+		limit int,
+	) ([]entities.WebhookDelivery, error)
+	// MarkSucceeded records a successful attempt
+	MarkSucceeded(
+		ctx context.Context,
+		id uint,
+		responseStatus int,
+		deliveredAt time.Time,
+	) error
+	// MarkFailed records a failed attempt and schedules the next one, or
+	// gives up on the delivery when nextAttemptAt is nil
+	MarkFailed(
+		ctx context.Context,
+		id uint,
+		responseStatus int,
+		lastError string,
+		nextAttemptAt *time.Time,
+	) error
+	// FindByID returns a delivery, or nil if it does not exist
+	FindByID(ctx context.Context, id uint) (*entities.WebhookDelivery, error)
+	// FindBySubscription returns the latest deliveries of a subscription,
+	// newest first
+	FindBySubscription(
+		ctx context.Context,
+		subscriptionID uint,
+		limit int,
+	) ([]entities.WebhookDelivery, error)
+	// Replay schedules a delivery to be sent again at now, whatever its status
+	Replay(ctx context.Context, id uint, now time.Time) error
+}